@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// errPipeClosed is the panic value used to unwind an asynchronous dump once
+// the reader of its output has been closed.
+var errPipeClosed = errors.New("spew: output reader closed")
+
+// pipeWriter is an io.Writer which unwinds the dump in progress with a panic
+// once writing to the underlying pipe fails, which happens when the reader of
+// the output has been closed, so the remainder of the dump is not formatted
+// needlessly.
+type pipeWriter struct {
+	pw *io.PipeWriter
+}
+
+// Write writes p to the underlying pipe.
+func (w pipeWriter) Write(p []byte) (int, error) {
+	n, err := w.pw.Write(p)
+	if err != nil {
+		panic(errPipeClosed)
+	}
+	return n, nil
+}
+
+// DumpAsync starts dumping the passed arguments exactly the same as Dump in a
+// separate goroutine and returns a reader of the output.  The output is
+// produced as it is read, so huge dumps are able to be streamed into HTTP
+// responses or compressing writers without buffering all of it in memory:
+//
+//	r := spew.DumpAsync(state)
+//	defer r.Close()
+//	io.Copy(w, r)
+//
+// Closing the reader stops the dump.  A panic while dumping, which is only
+// possible when a callback such as TransformFunc panics, is returned as an
+// error by Read instead of crashing the program.
+func (c *ConfigState) DumpAsync(a ...interface{}) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil && r != errPipeClosed {
+				pw.CloseWithError(fmt.Errorf("spew: panic while dumping: %v", r))
+				return
+			}
+			pw.Close()
+		}()
+		fdump(c, pipeWriter{pw}, a...)
+	}()
+	return pr
+}
+
+// DumpAsync starts dumping the passed arguments using the global Config in a
+// separate goroutine and returns a reader of the output.  See
+// ConfigState.DumpAsync for details.
+func DumpAsync(a ...interface{}) io.ReadCloser {
+	return globalConfig().DumpAsync(a...)
+}
@@ -0,0 +1,67 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestDumpAsync ensures dumps are streamed through the returned reader as
+// intended.
+func TestDumpAsync(t *testing.T) {
+	cfg := spew.ConfigState{Indent: " "}
+	v := map[string][]int{"a": {1, 2}}
+
+	r := cfg.DumpAsync(v, "b")
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if want := cfg.Sdump(v, "b"); string(got) != want {
+		t.Errorf("DumpAsync mismatch:\n got: %s\nwant: %s", got, want)
+	}
+	r.Close()
+
+	// Closing the reader early stops the dump.
+	big := make([]int, 100000)
+	r = cfg.DumpAsync(big)
+	buf := make([]byte, 10)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("ReadFull: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	// Panics while dumping are returned as errors.
+	cfg.TransformFunc = func(v reflect.Value) (reflect.Value, bool) {
+		panic("transform")
+	}
+	_, err = ioutil.ReadAll(cfg.DumpAsync(1))
+	if err == nil || !strings.Contains(err.Error(), "transform") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
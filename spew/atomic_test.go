@@ -0,0 +1,57 @@
+//go:build go1.19 && !spew_noop
+// +build go1.19,!spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// atomicNode is a type which links to others through an atomic.Pointer.
+type atomicNode struct {
+	V    int
+	Next atomic.Pointer[atomicNode]
+}
+
+// TestAtomicPointer ensures atomic.Pointer values are displayed using their
+// Load method rather than their internal state.
+func TestAtomicPointer(t *testing.T) {
+	cfg := spew.ConfigState{DisablePointerAddresses: true}
+	an := &atomicNode{V: 1}
+	an.Next.Store(&atomicNode{V: 2})
+
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{cfg.Sprint(an), "<*>{1 <*>{2 <nil>}}"},
+		{cfg.Sdump(an), "(*spew_test.atomicNode)({\n" +
+			"V: (int) 1,\nNext: (*spew_test.atomicNode)({\n" +
+			"V: (int) 2,\nNext: (*spew_test.atomicNode)(<nil>)\n})\n})\n"},
+	}
+	for i, test := range tests {
+		if test.got != test.want {
+			t.Errorf("Atomic pointer #%d mismatch:\n got: %q\nwant: %q", i,
+				test.got, test.want)
+		}
+	}
+}
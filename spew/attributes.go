@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Attribute is a single key/value pair produced by Attributes.  The value is
+// always one of bool, int64, float64, or string, which correspond to the
+// scalar attribute value types of OpenTelemetry, so each attribute maps
+// directly to an attribute.KeyValue.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// AttributeLimits specifies the limits to respect when converting a value
+// into attributes.  The zero value imposes no limits.
+type AttributeLimits struct {
+	// Prefix is prepended to the key of every attribute, separated by a
+	// dot.  The key of a scalar value without a prefix is "value".
+	Prefix string
+
+	// MaxAttributes is the maximum number of attributes to produce.  When
+	// a value would produce more, the final attribute is replaced by one
+	// with the key "spew.omitted", under the prefix, and a value of the
+	// number of attributes which were omitted.
+	MaxAttributes int
+
+	// MaxValueLength is the maximum number of bytes of string values.
+	// Longer values are truncated at a UTF-8 boundary and "..." is
+	// appended.
+	MaxValueLength int
+}
+
+// attrState contains information about the state of a conversion to
+// attributes.
+type attrState struct {
+	cs       *ConfigState
+	limits   AttributeLimits
+	pointers map[uintptr]bool
+	attrs    []Attribute
+	omitted  int
+}
+
+// add records an attribute while respecting the limit on the number of them.
+func (a *attrState) add(key string, value interface{}) {
+	if key == "" {
+		key = "value"
+	}
+	if s, ok := value.(string); ok && a.limits.MaxValueLength > 0 &&
+		len(s) > a.limits.MaxValueLength {
+
+		cs := ConfigState{MaxStringLength: a.limits.MaxValueLength}
+		s, _ = truncateString(&cs, s)
+		value = s + string(ellipsisBytes)
+	}
+
+	if a.limits.MaxAttributes > 0 && len(a.attrs) >= a.limits.MaxAttributes {
+		a.omitted++
+		return
+	}
+	a.attrs = append(a.attrs, Attribute{Key: key, Value: value})
+}
+
+// methodString returns the result of the error or Stringer interface of the
+// passed value when it implements one and methods are enabled.
+func (a *attrState) methodString(v reflect.Value) (s string, found bool) {
+	if a.cs.DisableMethods {
+		return "", false
+	}
+	iv, ok := interfaceValue(a.cs, v)
+	if !ok || !iv.CanInterface() {
+		return "", false
+	}
+
+	defer func() {
+		if err := recover(); err != nil {
+			s = fmt.Sprintf("%s%v%s", panicBytes, err, closeParenBytes)
+			found = true
+		}
+	}()
+	return methodText(a.cs, iv.Interface())
+}
+
+// walk converts the passed value into attributes keyed by dotted paths
+// beginning with the passed key.
+func (a *attrState) walk(key string, v reflect.Value, depth int) {
+	// Follow pointers and interfaces while detecting circular references.
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			a.add(key, string(nilAngleBytes))
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			if s, ok := a.methodString(v); ok {
+				a.add(key, s)
+				return
+			}
+			addr := v.Pointer()
+			if a.pointers[addr] {
+				a.add(key, string(circularBytes))
+				return
+			}
+			a.pointers[addr] = true
+			defer delete(a.pointers, addr)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		a.add(key, string(invalidAngleBytes))
+		return
+	}
+	if s, ok := a.methodString(v); ok {
+		a.add(key, s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		a.add(key, v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		a.add(key, v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		// Values which don't fit in an int64 are stringified since
+		// OpenTelemetry has no unsigned attribute type.
+		if u := v.Uint(); u <= math.MaxInt64 {
+			a.add(key, int64(u))
+		} else {
+			a.add(key, strconv.FormatUint(u, 10))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		a.add(key, v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		a.add(key, fmt.Sprint(v.Complex()))
+
+	case reflect.String:
+		a.add(key, v.String())
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			a.add(key, string(nilAngleBytes))
+			return
+		}
+		a.add(key, fmt.Sprintf("%#x", v.Pointer()))
+
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if a.cs.MaxDepth != 0 && depth >= a.cs.MaxDepth {
+			a.add(key, string(truncationMarker(a.cs, maxShortBytes, "MaxDepth",
+				a.cs.MaxDepth)))
+			return
+		}
+		a.walkComposite(key, v, depth)
+
+	default:
+		a.add(key, fmt.Sprint(v))
+	}
+}
+
+// walkComposite converts the elements of the passed slice, array, map, or
+// struct into attributes.  The keys of elements are their index, map key, or
+// field name respectively.
+func (a *attrState) walkComposite(key string, v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			a.add(key, string(nilAngleBytes))
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			a.walk(fieldPath(key, strconv.Itoa(i)), v.Index(i), depth+1)
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			a.add(key, string(nilAngleBytes))
+			return
+		}
+		keys := v.MapKeys()
+		if a.cs.SortKeys {
+			sortValues(keys, a.cs)
+		}
+		for _, k := range keys {
+			a.walk(fieldPath(key, fmt.Sprint(k)), v.MapIndex(k), depth+1)
+		}
+
+	case reflect.Struct:
+		for _, sf := range structFields(a.cs, v) {
+			a.walk(fieldPath(key, sf.name), sf.value, depth+1)
+		}
+	}
+}
+
+// Attributes converts the passed value into a flat list of attributes keyed by
+// dotted paths, such as "req.Header.Host", which are suitable for attaching a
+// debug dump to a trace as OpenTelemetry span event attributes instead of
+// writing it to a log:
+//
+//	var kvs []attribute.KeyValue
+//	for _, attr := range cs.Attributes(req, limits) {
+//		switch v := attr.Value.(type) {
+//		case bool:
+//			kvs = append(kvs, attribute.Bool(attr.Key, v))
+//		...
+//		}
+//	}
+//	span.AddEvent("request", trace.WithAttributes(kvs...))
+//
+// Scalars are kept as typed values while everything else is stringified.  The
+// passed limits are respected so the result fits within the attribute limits
+// of the tracer.  The MaxDepth, DisableMethods, DisablePointerMethods,
+// SortKeys, SpewKeys, and FlattenEmbedded options are honored.  No attributes
+// are returned while output is disabled.
+func (c *ConfigState) Attributes(v interface{}, limits AttributeLimits) []Attribute {
+	if NoopBuild || outputDisabled(c) {
+		return nil
+	}
+
+	a := attrState{cs: c, limits: limits, pointers: make(map[uintptr]bool)}
+	a.walk(limits.Prefix, reflect.ValueOf(v), 0)
+	if a.omitted > 0 {
+		// Replace the final attribute with the number of omitted ones.
+		a.omitted++
+		a.attrs[len(a.attrs)-1] = Attribute{
+			Key:   fieldPath(limits.Prefix, "spew.omitted"),
+			Value: int64(a.omitted),
+		}
+	}
+	return a.attrs
+}
+
+// Attributes converts the passed value into a flat list of attributes keyed by
+// dotted paths using the global Config.  See ConfigState.Attributes for
+// details.
+func Attributes(v interface{}, limits AttributeLimits) []Attribute {
+	return globalConfig().Attributes(v, limits)
+}
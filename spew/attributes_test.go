@@ -0,0 +1,90 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// attrTester is used to test conversion of values into attributes.
+type attrTester struct {
+	Name  string
+	Count uint64
+	Tags  []string
+	Attrs map[string]bool
+	Err   error
+	Next  *attrTester
+}
+
+// TestAttributes ensures values are converted into attributes keyed by dotted
+// paths while respecting the limits.
+func TestAttributes(t *testing.T) {
+	v := &attrTester{
+		Name:  "spew",
+		Count: math.MaxUint64,
+		Tags:  []string{"a"},
+		Attrs: map[string]bool{"y": false, "x": true},
+		Err:   customError(2),
+	}
+	v.Next = v
+	cs := spew.ConfigState{SortKeys: true}
+
+	tests := []struct {
+		in     interface{}
+		limits spew.AttributeLimits
+		want   []spew.Attribute
+	}{
+		{5, spew.AttributeLimits{}, []spew.Attribute{{"value", int64(5)}}},
+		{1.5, spew.AttributeLimits{Prefix: "f"}, []spew.Attribute{{"f", 1.5}}},
+		{v, spew.AttributeLimits{Prefix: "dbg"}, []spew.Attribute{
+			{"dbg.Name", "spew"},
+			{"dbg.Count", "18446744073709551615"},
+			{"dbg.Tags.0", "a"},
+			{"dbg.Attrs.x", true},
+			{"dbg.Attrs.y", false},
+			{"dbg.Err", "error: 2"},
+			{"dbg.Next", "<already shown>"},
+		}},
+		{v, spew.AttributeLimits{MaxAttributes: 3, MaxValueLength: 3},
+			[]spew.Attribute{
+				{"Name", "spe..."},
+				{"Count", "184..."},
+				{"spew.omitted", int64(5)},
+			}},
+		{[]int(nil), spew.AttributeLimits{}, []spew.Attribute{{"value", "<nil>"}}},
+	}
+
+	for i, test := range tests {
+		got := cs.Attributes(test.in, test.limits)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Attributes #%d:\n got: %v\nwant: %v", i, got, test.want)
+		}
+	}
+
+	// No attributes are returned while output is disabled.
+	cs.Disabled = true
+	if got := cs.Attributes(v, spew.AttributeLimits{}); got != nil {
+		t.Errorf("Attributes with Disabled: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+)
+
+// Baseline is a snapshot of the values within a value which later states of
+// it may be compared against so only what changed is displayed.  It is
+// created with NewBaseline.
+type Baseline struct {
+	cs     *ConfigState
+	paths  []string
+	values map[string]string
+}
+
+// Change is a single value which differs between a Baseline and a later state
+// of the value it was taken of.
+type Change struct {
+	// Path is the path to the value, which is in the same form as the paths
+	// passed to the RedactFunc option.  It is empty for the value itself.
+	Path string
+
+	// Old and New are the value in the baseline and the later state in the
+	// same format as Dump.  Old is empty for values which were added, such
+	// as map entries and slice elements, while New is empty for values
+	// which were removed.
+	Old, New string
+}
+
+// leafWalker visits the leaf values within a value along with their paths.
+// It is the traversal shared by baselines and deep hashes.
+type leafWalker struct {
+	cs       *ConfigState
+	pointers map[uintptr]bool
+	visit    func(path, text string)
+
+	// ignorePaths are the paths of values which are not visited, while
+	// ignoreUnexported specifies that unexported struct fields are not
+	// visited.
+	ignorePaths      map[string]bool
+	ignoreUnexported bool
+
+	// pointerAddrs specifies that the addresses of the pointers followed
+	// are visited, while hideAddrs specifies that the addresses of
+	// functions, channels, and unsafe pointers are omitted from leaves.
+	pointerAddrs bool
+	hideAddrs    bool
+}
+
+// newLeafWalker returns a leafWalker which formats the leaf values using the
+// passed configuration.  The options which observe or bound a whole dump, such
+// as NodeFunc, ProgressFunc, and MaxNodes, are cleared since each leaf value is
+// rendered on its own rather than as part of one.
+func newLeafWalker(cs *ConfigState) *leafWalker {
+	lcs := *cs
+	lcs.NodeFunc, lcs.ProgressFunc, lcs.MaxNodes = nil, nil, 0
+	return &leafWalker{cs: &lcs, pointers: make(map[uintptr]bool)}
+}
+
+// leaf visits the passed value located at path in the same format as Dump.
+func (l *leafWalker) leaf(path string, v reflect.Value) {
+	var buf bytes.Buffer
+	switch v.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if l.hideAddrs {
+			buf.WriteString(typeString(l.cs, v.Type()))
+			if v.IsNil() {
+				buf.Write(spaceBytes)
+				buf.Write(nilAngleBytes)
+			}
+			l.visit(path, buf.String())
+			return
+		}
+	}
+	d := dumpState{w: &buf, cs: l.cs, pointers: make(map[visitKey]int)}
+	d.dump(v)
+	l.visit(path, buf.String())
+}
+
+// hasMethod returns whether or not the passed value is displayed via its error
+// or Stringer interface, in which case its contents are not walked.
+func (l *leafWalker) hasMethod(v reflect.Value) bool {
+	if l.cs.DisableMethods {
+		return false
+	}
+	iv, ok := interfaceValue(l.cs, v)
+	if !ok {
+		return false
+	}
+	t := iv.Type()
+	return t.Implements(errorType) || t.Implements(stringerType)
+}
+
+// walk visits all of the leaf values within the passed value located at path.
+func (l *leafWalker) walk(path string, v reflect.Value) {
+	if l.ignorePaths[path] {
+		return
+	}
+
+	// Follow pointers and interfaces while detecting circular references.
+	// Nil pointers and interfaces are visited as leaves.
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			l.leaf(path, v)
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			addr := v.Pointer()
+			if l.pointers[addr] {
+				l.visit(path, string(circularBytes))
+				return
+			}
+			if l.pointerAddrs {
+				var buf bytes.Buffer
+				printHexPtr(&buf, addr)
+				l.visit(path, buf.String())
+			}
+			l.pointers[addr] = true
+			defer delete(l.pointers, addr)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || l.hasMethod(v) {
+		l.leaf(path, v)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := structFields(l.cs, v)
+		if len(fields) == 0 {
+			l.leaf(path, v)
+		}
+		for _, sf := range fields {
+			if l.ignoreUnexported && sf.field.PkgPath != "" {
+				continue
+			}
+			l.walk(fieldPath(path, sf.path), sf.value)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 || v.Type().Elem().Kind() == reflect.Uint8 {
+			l.leaf(path, v)
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			l.walk(indexPath(path, i), v.Index(i))
+		}
+
+	case reflect.Map:
+		if v.Len() == 0 {
+			l.leaf(path, v)
+			return
+		}
+		keys := v.MapKeys()
+		sortValues(keys, l.cs)
+		for _, k := range keys {
+			l.walk(keyPath(path, k), v.MapIndex(k))
+		}
+
+	default:
+		l.leaf(path, v)
+	}
+}
+
+// snapshot returns the paths, in the order they are displayed, and text of
+// all of the leaf values within the passed value.
+func snapshot(cs *ConfigState, a interface{}) ([]string, map[string]string) {
+	var paths []string
+	values := make(map[string]string)
+	l := newLeafWalker(cs)
+	l.visit = func(path, text string) {
+		if _, ok := values[path]; !ok {
+			paths = append(paths, path)
+		}
+		values[path] = text
+	}
+	l.walk("", reflect.ValueOf(a))
+	return paths, values
+}
+
+// NewBaseline takes a snapshot of all of the values within the passed value
+// so a later state of it may be compared against the snapshot with Changes or
+// DumpChanges, which is useful for tracking what parts of a complex
+// configuration or state were mutated between two points in a program:
+//
+//	baseline := spew.NewBaseline(state)
+//	handle(state)
+//	baseline.DumpChanges(state)
+//
+// The values are formatted using the options of c when the snapshot is
+// taken, so later changes to the value do not affect it.
+func (c *ConfigState) NewBaseline(a interface{}) *Baseline {
+	paths, values := snapshot(c, a)
+	return &Baseline{cs: c, paths: paths, values: values}
+}
+
+// NewBaseline takes a snapshot of all of the values within the passed value
+// using the global Config.  See ConfigState.NewBaseline for details.
+func NewBaseline(a interface{}) *Baseline {
+	return globalConfig().NewBaseline(a)
+}
+
+// Changes returns the values within the passed value which differ from the
+// baseline, in the order they are displayed, followed by the values which
+// were removed since the baseline was taken.
+func (b *Baseline) Changes(a interface{}) []Change {
+	paths, values := snapshot(b.cs, a)
+	var changes []Change
+	for _, path := range paths {
+		if old := b.values[path]; old != values[path] {
+			changes = append(changes, Change{Path: path, Old: old,
+				New: values[path]})
+		}
+	}
+	for _, path := range b.paths {
+		if _, ok := values[path]; !ok {
+			changes = append(changes, Change{Path: path, Old: b.values[path]})
+		}
+	}
+	return changes
+}
+
+// FdumpChanges outputs the values within the passed value which differ from
+// the baseline to io.Writer w.  See DumpChanges for details.
+func (b *Baseline) FdumpChanges(w io.Writer, a interface{}) {
+	if NoopBuild || outputDisabled(b.cs) {
+		return
+	}
+	b.writeChanges(w, a, b.Changes(a))
+}
+
+// writeChanges outputs the passed changes to the passed value to io.Writer w.
+func (b *Baseline) writeChanges(w io.Writer, a interface{}, changes []Change) {
+	if NoopBuild || outputDisabled(b.cs) {
+		return
+	}
+	w.Write(openParenBytes)
+	w.Write([]byte(typeString(b.cs, reflect.TypeOf(a))))
+	w.Write(closeParenBytes)
+	w.Write(newlineBytes)
+	if len(changes) == 0 {
+		w.Write([]byte(b.cs.Indent))
+		w.Write(unchangedBytes)
+		w.Write(newlineBytes)
+	}
+	for _, change := range changes {
+		w.Write([]byte(b.cs.Indent))
+		if change.Path != "" {
+			w.Write([]byte(change.Path))
+			w.Write(colonSpaceBytes)
+		}
+		if change.Old == "" {
+			w.Write(absentBytes)
+		} else {
+			w.Write([]byte(change.Old))
+		}
+		w.Write(changedArrowBytes)
+		if change.New == "" {
+			w.Write(absentBytes)
+		} else {
+			w.Write([]byte(change.New))
+		}
+		w.Write(newlineBytes)
+	}
+}
+
+// SdumpChanges returns a string with the values within the passed value which
+// differ from the baseline.  See DumpChanges for details.
+func (b *Baseline) SdumpChanges(a interface{}) string {
+	var buf bytes.Buffer
+	b.FdumpChanges(&buf, a)
+	return buf.String()
+}
+
+// DumpChanges outputs the values within the passed value which differ from the
+// baseline to standard out, one per line, prefixed by their path and showing
+// both the old and new value.  Values which were added or removed since the
+// baseline was taken are shown as <absent> on the respective side.  For
+// example:
+//
+//	(*main.Config)
+//	 Server.Port: (int) 8080 → (int) 9090
+//	 Server.Hosts[2]: <absent> → (string) (len=1) "c"
+//
+// Values which implement the error or Stringer interface are compared by
+// their output rather than walked, and map keys are always sorted so the
+// entries of maps are compared by key.
+func (b *Baseline) DumpChanges(a interface{}) {
+	b.FdumpChanges(os.Stdout, a)
+}
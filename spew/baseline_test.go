@@ -0,0 +1,125 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// baselineState is used to test comparing values against a baseline.
+type baselineState struct {
+	Name  string
+	Hosts []string
+	Opts  map[string]int
+	Next  *baselineState
+}
+
+// TestBaseline ensures only the values which differ from a baseline are
+// reported along with their paths.
+func TestBaseline(t *testing.T) {
+	state := &baselineState{Name: "a", Hosts: []string{"x", "y"},
+		Opts: map[string]int{"k": 1, "gone": 2}}
+	state.Next = state
+	cs := spew.ConfigState{Indent: " "}
+	baseline := cs.NewBaseline(state)
+
+	if got, want := baseline.SdumpChanges(state),
+		"(*spew_test.baselineState)\n <unchanged>\n"; got != want {
+		t.Errorf("unchanged: got %q, want %q", got, want)
+	}
+
+	state.Name = "b"
+	state.Hosts = append(state.Hosts, "z")
+	state.Opts["k"] = 3
+	delete(state.Opts, "gone")
+	want := []spew.Change{
+		{Path: "Name", Old: "(string) (len=1) \"a\"", New: "(string) (len=1) \"b\""},
+		{Path: "Hosts[2]", New: "(string) (len=1) \"z\""},
+		{Path: "Opts[k]", Old: "(int) 1", New: "(int) 3"},
+		{Path: "Opts[gone]", Old: "(int) 2"},
+	}
+	if got := baseline.Changes(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes: got %#v, want %#v", got, want)
+	}
+
+	wantDump := "(*spew_test.baselineState)\n" +
+		" Name: (string) (len=1) \"a\" → (string) (len=1) \"b\"\n" +
+		" Hosts[2]: <absent> → (string) (len=1) \"z\"\n" +
+		" Opts[k]: (int) 1 → (int) 3\n" +
+		" Opts[gone]: (int) 2 → <absent>\n"
+	if got := baseline.SdumpChanges(state); got != wantDump {
+		t.Errorf("SdumpChanges: got %q, want %q", got, wantDump)
+	}
+
+	// Values which are not walked are compared as a whole.
+	if got, want := spew.NewBaseline(5).Changes(6),
+		[]spew.Change{{Old: "(int) 5", New: "(int) 6"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scalar: got %#v, want %#v", got, want)
+	}
+}
+
+// TestBaselineDumpCallbacks ensures baselines are able to be taken and compared
+// with configurations which observe or bound whole dumps.
+func TestBaselineDumpCallbacks(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   spew.ConfigState
+	}{
+		{"NodeFunc", spew.ConfigState{NodeFunc: func(spew.Node) {}}},
+		{"ProgressFunc", spew.ConfigState{ProgressInterval: 1,
+			ProgressFunc: func(spew.Progress) bool { return true }}},
+		{"MaxNodes", spew.ConfigState{MaxNodes: 1}},
+	}
+	for _, test := range tests {
+		state := &baselineState{Name: "a", Hosts: []string{"x"}}
+		baseline := test.cs.NewBaseline(state)
+		state.Name = "b"
+		want := []spew.Change{{Path: "Name", Old: "(string) (len=1) \"a\"",
+			New: "(string) (len=1) \"b\""}}
+		if got := baseline.Changes(state); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+// TestBaselineDisabled ensures the changes to a value are not output while
+// output is disabled.
+func TestBaselineDisabled(t *testing.T) {
+	state := &baselineState{Name: "a"}
+	baseline := spew.NewBaseline(state)
+	state.Name = "b"
+
+	cs := spew.ConfigState{Disabled: true}
+	if got := cs.NewBaseline(state).SdumpChanges(state); got != "" {
+		t.Errorf("SdumpChanges with Disabled: %q", got)
+	}
+
+	defer spew.Enable()
+	spew.Disable()
+	if got := baseline.SdumpChanges(state); got != "" {
+		t.Errorf("SdumpChanges while disabled: %q", got)
+	}
+	if got := baseline.Changes(state); len(got) != 1 {
+		t.Errorf("Changes while disabled: got %v, want 1 change", got)
+	}
+}
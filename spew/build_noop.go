@@ -0,0 +1,36 @@
+//go:build spew_noop
+// +build spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// NOTE: Due to the above build constraints, this file will only be compiled
+// when "-tags spew_noop" is added to the go build command line.  This allows
+// debug dumps which were left in the code to be compiled out of release
+// builds.
+
+package spew
+
+// NoopBuild is a build-time constant which specifies whether or not the
+// output functions of this package are compiled as no-ops.  Since it is a
+// constant, the formatting code which is only reachable from them is removed
+// by the linker as dead code.
+//
+// The Dump family of functions and the custom formatter produce no output,
+// the Print family of functions write nothing and return zero, the Sprint
+// family of functions return an empty string, and Errorf is equivalent to
+// fmt.Errorf.
+const NoopBuild = true
@@ -0,0 +1,70 @@
+//go:build spew_noop
+// +build spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestNoopBuild ensures the output functions produce no output when built with
+// the spew_noop tag.
+func TestNoopBuild(t *testing.T) {
+	var buf bytes.Buffer
+	spew.Fdump(&buf, 1)
+	spew.Fprintf(&buf, "%v", 1)
+	spew.Config.Fprintln(&buf, 1)
+	spew.NewPrinter().To(&buf).Dump(1)
+	spew.FdumpJSONLines(&buf, 1)
+	spew.FdumpMethods(&buf, 1)
+	spew.DumpPanic(&buf, 1)
+	func() {
+		defer spew.RecoverDump(&buf)
+		panic(1)
+	}()
+	spew.FdumpRepro(&buf, 1)
+	spew.Capture(1).Fdump(&buf)
+	spew.NewBaseline(1).FdumpChanges(&buf, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	spew.Watch(ctx, time.Millisecond, func() interface{} { return 1 }, &buf)
+	if buf.Len() != 0 {
+		t.Errorf("output written: %q", buf.String())
+	}
+	if s := spew.Sdump(1) + spew.Sprintf("%v", 1); s != "" {
+		t.Errorf("Sprint functions returned %q", s)
+	}
+	if attrs := spew.Attributes(1, spew.AttributeLimits{}); len(attrs) != 0 {
+		t.Errorf("Attributes returned %v", attrs)
+	}
+	if spew.V(0).Enabled() {
+		t.Errorf("V(0) enabled")
+	}
+
+	errBase := errors.New("base")
+	if err := spew.Errorf("wrapped: %w", errBase); !errors.Is(err, errBase) {
+		t.Errorf("Errorf did not wrap: %v", err)
+	}
+}
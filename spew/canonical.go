@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// canonicalState contains information about the state of producing the
+// canonical form of a value.
+type canonicalState struct {
+	buf      bytes.Buffer
+	pointers map[visitKey]bool
+}
+
+// writeType writes the name of the passed type.
+func (c *canonicalState) writeType(t reflect.Type) {
+	c.buf.WriteString(t.String())
+}
+
+// writeNil writes a nil value of the passed type, such as (*T)(nil).
+func (c *canonicalState) writeNil(t reflect.Type) {
+	c.buf.WriteByte('(')
+	c.writeType(t)
+	c.buf.WriteString(")(nil)")
+}
+
+// writeFloat writes the passed float with the fewest digits which represent
+// it exactly.
+func (c *canonicalState) writeFloat(f float64, bitSize int) {
+	switch {
+	case math.IsNaN(f):
+		c.buf.WriteString("NaN")
+	case math.IsInf(f, 1):
+		c.buf.WriteString("+Inf")
+	case math.IsInf(f, -1):
+		c.buf.WriteString("-Inf")
+	default:
+		c.buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+}
+
+// write writes the canonical form of the passed value.  The type of scalars
+// is only written when typed is set, which is the case for the top-level
+// value and values held by interfaces, since it is otherwise implied by the
+// type of the value containing them.
+func (c *canonicalState) write(v reflect.Value, typed bool) {
+	if !v.IsValid() {
+		c.buf.WriteString("nil")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			c.buf.WriteString("nil")
+			return
+		}
+		c.write(v.Elem(), true)
+		return
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			c.writeNil(v.Type())
+			return
+		}
+		key := newVisitKey(v)
+		if c.pointers[key] {
+			c.buf.WriteString("<cycle>")
+			return
+		}
+		c.pointers[key] = true
+		c.buf.WriteByte('&')
+		c.write(v.Elem(), true)
+		delete(c.pointers, key)
+		return
+
+	case reflect.Struct:
+		c.writeType(v.Type())
+		c.buf.WriteByte('{')
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				c.buf.WriteByte(',')
+			}
+			c.buf.WriteString(t.Field(i).Name)
+			c.buf.WriteByte(':')
+			c.write(v.Field(i), false)
+		}
+		c.buf.WriteByte('}')
+		return
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			c.writeNil(v.Type())
+			return
+		}
+		c.writeType(v.Type())
+		c.buf.WriteByte('{')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				c.buf.WriteByte(',')
+			}
+			c.write(v.Index(i), false)
+		}
+		c.buf.WriteByte('}')
+		return
+
+	case reflect.Map:
+		if v.IsNil() {
+			c.writeNil(v.Type())
+			return
+		}
+		key := newVisitKey(v)
+		if c.pointers[key] {
+			c.buf.WriteString("<cycle>")
+			return
+		}
+		c.pointers[key] = true
+		defer delete(c.pointers, key)
+
+		// Entries are sorted by the canonical form of their keys.
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			kc := canonicalState{pointers: c.pointers}
+			kc.write(k, false)
+			entries = append(entries, entry{kc.buf.String(), v.MapIndex(k)})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+		c.writeType(v.Type())
+		c.buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				c.buf.WriteByte(',')
+			}
+			c.buf.WriteString(e.key)
+			c.buf.WriteByte(':')
+			c.write(e.value, false)
+		}
+		c.buf.WriteByte('}')
+		return
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// Addresses are never included, so only whether or not the value is
+		// nil is written.
+		if v.IsNil() {
+			c.writeNil(v.Type())
+			return
+		}
+		c.buf.WriteByte('(')
+		c.writeType(v.Type())
+		c.buf.WriteString(")(non-nil)")
+		return
+	}
+
+	if typed {
+		c.writeType(v.Type())
+		c.buf.WriteByte('(')
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		c.buf.WriteString(strconv.FormatBool(v.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.buf.WriteString(strconv.FormatInt(v.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		c.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+
+	case reflect.Float32, reflect.Float64:
+		c.writeFloat(v.Float(), v.Type().Bits())
+
+	case reflect.Complex64, reflect.Complex128:
+		bitSize := v.Type().Bits() / 2
+		cv := v.Complex()
+		c.buf.WriteByte('(')
+		c.writeFloat(real(cv), bitSize)
+		if im := imag(cv); !math.Signbit(im) && !math.IsInf(im, 1) {
+			c.buf.WriteByte('+')
+		}
+		c.writeFloat(imag(cv), bitSize)
+		c.buf.WriteString("i)")
+
+	case reflect.String:
+		c.buf.WriteString(strconv.QuoteToASCII(v.String()))
+	}
+	if typed {
+		c.buf.WriteByte(')')
+	}
+}
+
+// Canonical returns a compact and fully deterministic single-line form of the
+// passed value which is intended for use as a key, such as in caches and for
+// deduplicating values.  Values which are deeply equal, other than the
+// addresses of the pointers they contain, produce the same form.  Unlike the
+// other output functions, it does not depend on any configuration and does
+// not invoke any methods, such as the error and Stringer interfaces.
+//
+// The form resembles a Go composite literal, such as
+//
+//	&main.Config{Name:"a",Hosts:[]string{"x","y"},Opts:map[string]int{"k":1}}
+//
+// where map entries are sorted by the form of their keys, strings are quoted
+// with all non-ASCII characters escaped, floats are written with the fewest
+// digits which represent them exactly, and scalars held by interfaces are
+// written along with their type, such as int(5).  The addresses of pointers,
+// functions, channels, and unsafe pointers are never included, and cycles
+// are written as <cycle>.  Unexported struct fields are included.
+//
+// The form is guaranteed not to change between minor versions of this
+// package, so it is safe to persist.
+func Canonical(v interface{}) string {
+	c := canonicalState{pointers: make(map[visitKey]bool)}
+	c.write(reflect.ValueOf(v), true)
+	return c.buf.String()
+}
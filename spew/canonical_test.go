@@ -0,0 +1,68 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// canonicalConfig is used to test the canonical form of values.
+type canonicalConfig struct {
+	Name  string
+	Hosts []string
+	Opts  map[string]int
+	Any   interface{}
+	Next  *canonicalConfig
+	fn    func()
+}
+
+// TestCanonical ensures the canonical form of values is deterministic and
+// does not change.  The expected forms must never be modified within a major
+// version since they may have been persisted.
+func TestCanonical(t *testing.T) {
+	cfg := &canonicalConfig{Name: "a\té", Hosts: []string{"x", "y"},
+		Opts: map[string]int{"b": 2, "a": 1}, Any: uint8(5), fn: func() {}}
+	cfg.Next = cfg
+
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, "nil"},
+		{5, "int(5)"},
+		{[]interface{}{1.5, "s", nil, true}, `[]interface {}{float64(1.5),string("s"),nil,bool(true)}`},
+		{map[int]bool{10: true, 2: false}, "map[int]bool{10:true,2:false}"},
+		{[2]complex64{1 + 2i, complex64(complex(0, math.Inf(-1)))}, "[2]complex64{(1+2i),(0-Infi)}"},
+		{[]float64{0.1, math.NaN(), 1e21}, "[]float64{0.1,NaN,1e+21}"},
+		{[]string(nil), "([]string)(nil)"},
+		{(*int)(nil), "(*int)(nil)"},
+		{cfg, `&spew_test.canonicalConfig{Name:"a\t\u00e9",Hosts:[]string{"x","y"},` +
+			`Opts:map[string]int{"a":1,"b":2},Any:uint8(5),Next:<cycle>,` +
+			`fn:(func())(non-nil)}`},
+	}
+	for i, test := range tests {
+		if got := spew.Canonical(test.in); got != test.want {
+			t.Errorf("#%d: got %s, want %s", i, got, test.want)
+		}
+	}
+}
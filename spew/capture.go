@@ -0,0 +1,430 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// captureVersion is the version of the serialized form of a CapturedDump.
+const captureVersion = 1
+
+// ErrCaptureVersion is returned by DecodeCapture when the serialized capture
+// was produced by an incompatible version of this package.
+var ErrCaptureVersion = errors.New("spew: unsupported capture version")
+
+// CaptureNode is a single value within a CapturedDump.
+type CaptureNode struct {
+	// Name is the name of the field for struct fields.
+	Name string
+
+	// Key is the map key for map entries.
+	Key *CaptureNode
+
+	// Type is the name of the type of the value.  For pointers it is the
+	// type of the final value pointed to, prefixed by an asterisk for each
+	// level of indirection.
+	Type string
+
+	// Kind is the kind of the value.
+	Kind reflect.Kind
+
+	// Leaf is set for values which are displayed as Value rather than
+	// walked, such as scalars, nil slices and maps, and values which
+	// implement the error or Stringer interfaces.
+	Leaf bool
+
+	// Value is the text displayed after the type of leaves, the text
+	// displayed in place of the value pointed to for nil and circular
+	// pointers, or the marker displayed in place of the contents of values
+	// beyond the maximum depth.
+	Value string
+
+	// Addrs are the addresses in the chain of pointers for pointers.
+	Addrs []uintptr
+
+	// Len and Cap are the length and capacity of slices, arrays, and maps.
+	Len, Cap int
+
+	// Children are the elements of slices and arrays, the fields of structs,
+	// the entries of maps, and the value pointed to by pointers.
+	Children []*CaptureNode
+}
+
+// CapturedDump is a self-contained snapshot of the values walked by Dump
+// which may be serialized and rendered later, possibly by another process.
+type CapturedDump struct {
+	Version int
+
+	// Indent, DisableCapacities, and DisablePointerAddresses are the options
+	// of the ConfigState the capture was made with which affect rendering.
+	Indent                  string
+	DisableCapacities       bool
+	DisablePointerAddresses bool
+
+	// Values are the root nodes of each argument captured.
+	Values []*CaptureNode
+}
+
+// captureState contains information about the state of a capture.
+type captureState struct {
+	cs       *ConfigState
+	pointers map[uintptr]bool
+}
+
+// isLeaf returns whether or not the passed value is captured as text rather
+// than walked.
+func (c *captureState) isLeaf(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+	case reflect.Slice, reflect.Array:
+		// Byte slices are hex dumped.
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+	default:
+		return true
+	}
+	if _, ok := reflectTypeValue(v); ok {
+		return true
+	}
+	if _, ok := weakPointerTarget(v); ok {
+		return true
+	}
+	if v.Kind() == reflect.Struct && !c.cs.DisableContainerTraversal {
+		if _, ok := containerValues(v); ok {
+			return true
+		}
+	}
+	if c.cs.DisableMethods {
+		return false
+	}
+	iv, ok := interfaceValue(c.cs, v)
+	if !ok {
+		return false
+	}
+	t := iv.Type()
+	return t.Implements(errorType) || t.Implements(stringerType) ||
+		t.Implements(reflect.TypeOf((*SpewDumper)(nil)).Elem())
+}
+
+// walk returns the node for the passed value at the passed depth.
+func (c *captureState) walk(v reflect.Value, depth int) *CaptureNode {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return &CaptureNode{Leaf: true, Value: string(invalidAngleBytes)}
+	}
+	if v.Kind() == reflect.Ptr {
+		return c.walkPtr(v, depth)
+	}
+
+	node := &CaptureNode{Type: typeString(c.cs, v.Type()), Kind: v.Kind()}
+	if c.isLeaf(v) {
+		// Leaves are rendered now exactly as Dump would display them
+		// without their type.
+		var buf bytes.Buffer
+		d := dumpState{w: &buf, cs: c.cs, depth: depth, ignoreNextType: true,
+			pointers: make(map[visitKey]int)}
+		d.dumpLine(v)
+		node.Leaf, node.Value = true, strings.TrimSuffix(buf.String(), "\n")
+		return node
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			node.Leaf, node.Value = true, string(nilAngleBytes)
+			return node
+		}
+		node.Len, node.Cap = v.Len(), v.Cap()
+	case reflect.Map:
+		if v.IsNil() {
+			node.Leaf, node.Value = true, string(nilAngleBytes)
+			return node
+		}
+		node.Len = v.Len()
+	}
+	if c.cs.MaxDepth != 0 && depth+1 > c.cs.MaxDepth {
+		var buf bytes.Buffer
+		printMaxDepth(&buf, c.cs, maxNewlineBytes)
+		node.Value = strings.TrimSuffix(buf.String(), "\n")
+		return node
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			node.Children = append(node.Children, c.walk(v.Index(i), depth+1))
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		if c.cs.SortKeys {
+			sortValues(keys, c.cs)
+		}
+		for _, k := range keys {
+			child := c.walk(v.MapIndex(k), depth+1)
+			child.Key = c.walk(k, depth+1)
+			node.Children = append(node.Children, child)
+		}
+
+	case reflect.Struct:
+		for _, sf := range structFields(c.cs, v) {
+			child := c.walk(sf.value, depth+1)
+			child.Name = sf.name
+			node.Children = append(node.Children, child)
+		}
+	}
+	return node
+}
+
+// walkPtr returns the node for the passed pointer at the passed depth.  The
+// chain of pointers is followed while detecting circular references in the
+// same way as Dump.
+func (c *captureState) walkPtr(v reflect.Value, depth int) *CaptureNode {
+	node := &CaptureNode{Kind: reflect.Ptr}
+	var visited []uintptr
+	defer func() {
+		for _, addr := range visited {
+			delete(c.pointers, addr)
+		}
+	}()
+
+	indirects := 0
+	ve := v
+	for ve.Kind() == reflect.Ptr {
+		if ve.IsNil() {
+			node.Value = string(nilAngleBytes)
+			break
+		}
+		indirects++
+		addr := ve.Pointer()
+		node.Addrs = append(node.Addrs, addr)
+		if c.pointers[addr] {
+			node.Value = string(circularBytes)
+			indirects--
+			break
+		}
+		c.pointers[addr] = true
+		visited = append(visited, addr)
+
+		ve = ve.Elem()
+		if ve.Kind() == reflect.Interface {
+			if ve.IsNil() {
+				node.Value = string(nilAngleBytes)
+				break
+			}
+			ve = ve.Elem()
+		}
+	}
+	node.Type = strings.Repeat("*", indirects) + typeString(c.cs, ve.Type())
+	if node.Value == "" {
+		node.Children = []*CaptureNode{c.walk(ve, depth)}
+	}
+	return node
+}
+
+// renderState contains information about the state of rendering a capture.
+type renderState struct {
+	capture *CapturedDump
+	w       io.Writer
+	depth   int
+}
+
+// indent writes the indentation for the current depth.
+func (r *renderState) indent() {
+	r.w.Write(bytes.Repeat([]byte(r.capture.Indent), r.depth))
+}
+
+// render writes the passed node in the same format as Dump.  The type is
+// omitted for values pointed to since it is part of the type of the pointer.
+func (r *renderState) render(n *CaptureNode, showType bool) {
+	if showType && n.Type != "" {
+		r.w.Write(openParenBytes)
+		r.w.Write([]byte(n.Type))
+		r.w.Write(closeParenBytes)
+		if n.Kind != reflect.Ptr {
+			r.w.Write(spaceBytes)
+		}
+	}
+
+	switch {
+	case n.Kind == reflect.Ptr:
+		if !r.capture.DisablePointerAddresses && len(n.Addrs) > 0 {
+			r.w.Write(openParenBytes)
+			for i, addr := range n.Addrs {
+				if i > 0 {
+					r.w.Write(pointerChainBytes)
+				}
+				printHexPtr(r.w, addr)
+			}
+			r.w.Write(closeParenBytes)
+		}
+		r.w.Write(openParenBytes)
+		if len(n.Children) > 0 {
+			r.render(n.Children[0], false)
+		} else {
+			r.w.Write([]byte(n.Value))
+		}
+		r.w.Write(closeParenBytes)
+		return
+
+	case n.Leaf:
+		r.w.Write([]byte(n.Value))
+		return
+	}
+
+	if n.Len != 0 || !r.capture.DisableCapacities && n.Cap != 0 {
+		r.w.Write(openParenBytes)
+		if n.Len != 0 {
+			r.w.Write(lenEqualsBytes)
+			printInt(r.w, int64(n.Len), 10)
+		}
+		if !r.capture.DisableCapacities && n.Cap != 0 {
+			if n.Len != 0 {
+				r.w.Write(spaceBytes)
+			}
+			r.w.Write(capEqualsBytes)
+			printInt(r.w, int64(n.Cap), 10)
+		}
+		r.w.Write(closeParenBytes)
+		r.w.Write(spaceBytes)
+	}
+
+	r.w.Write(openBraceNewlineBytes)
+	r.depth++
+	if n.Value != "" {
+		r.indent()
+		r.w.Write([]byte(n.Value))
+		r.w.Write(newlineBytes)
+	}
+	for i, child := range n.Children {
+		r.indent()
+		switch {
+		case child.Name != "":
+			r.w.Write([]byte(child.Name))
+			r.w.Write(colonSpaceBytes)
+		case child.Key != nil:
+			r.render(child.Key, true)
+			r.w.Write(colonSpaceBytes)
+		}
+		r.render(child, true)
+		if i < len(n.Children)-1 {
+			r.w.Write(commaNewlineBytes)
+		} else {
+			r.w.Write(newlineBytes)
+		}
+	}
+	r.depth--
+	r.indent()
+	r.w.Write(closeBraceBytes)
+}
+
+// Fdump renders the captured values to io.Writer w in the same format as
+// Dump.  Nothing is rendered while output is disabled by Disable.
+func (c *CapturedDump) Fdump(w io.Writer) {
+	if NoopBuild || !Enabled() {
+		return
+	}
+
+	for _, n := range c.Values {
+		r := renderState{capture: c, w: w}
+		r.render(n, true)
+		w.Write(newlineBytes)
+	}
+}
+
+// Sdump returns a string with the captured values rendered in the same format
+// as Dump.
+func (c *CapturedDump) Sdump() string {
+	var buf bytes.Buffer
+	c.Fdump(&buf)
+	return buf.String()
+}
+
+// Encode writes the capture to io.Writer w in a self-contained binary form,
+// which is able to be decoded with DecodeCapture.
+func (c *CapturedDump) Encode(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(c)
+}
+
+// DecodeCapture reads a capture written by CapturedDump.Encode from
+// io.Reader r.
+func DecodeCapture(r io.Reader) (*CapturedDump, error) {
+	var c CapturedDump
+	if err := gob.NewDecoder(r).Decode(&c); err != nil {
+		return nil, err
+	}
+	if c.Version != captureVersion {
+		return nil, fmt.Errorf("%w: %d", ErrCaptureVersion, c.Version)
+	}
+	return &c, nil
+}
+
+// Capture walks the passed arguments the same way as Dump and returns the
+// resulting tree of values rather than formatting it.  The capture is
+// independent of the original values, so it may be taken where a failure is
+// detected and only rendered later, or serialized with Encode and rendered by
+// another process, which keeps the cost of the text formatting off of hot
+// paths:
+//
+//	capture := spew.Capture(state)
+//	...
+//	capture.Fdump(os.Stderr)
+//
+// Values which are not walked, such as scalars and values which implement
+// the error or Stringer interfaces, are formatted when captured using the
+// options of c.  The Indent, DisableCapacities, and DisablePointerAddresses
+// options are recorded in the capture for rendering.  Nothing is captured
+// while output is disabled.
+func (c *ConfigState) Capture(a ...interface{}) *CapturedDump {
+	capture := &CapturedDump{
+		Version:                 captureVersion,
+		Indent:                  c.Indent,
+		DisableCapacities:       c.DisableCapacities,
+		DisablePointerAddresses: c.DisablePointerAddresses,
+	}
+	if NoopBuild || outputDisabled(c) {
+		return capture
+	}
+	for _, arg := range a {
+		if arg == nil {
+			capture.Values = append(capture.Values, &CaptureNode{
+				Type: "interface {}", Kind: reflect.Interface, Leaf: true,
+				Value: string(nilAngleBytes)})
+			continue
+		}
+		s := captureState{cs: c, pointers: make(map[uintptr]bool)}
+		capture.Values = append(capture.Values, s.walk(reflect.ValueOf(arg), 0))
+	}
+	return capture
+}
+
+// Capture walks the passed arguments the same way as Dump using the global
+// Config and returns the resulting tree of values.  See ConfigState.Capture
+// for details.
+func Capture(a ...interface{}) *CapturedDump {
+	return globalConfig().Capture(a...)
+}
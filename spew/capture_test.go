@@ -0,0 +1,128 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// captureNode is used to test captures of circular and nested values.
+type captureNode struct {
+	Name  string
+	Data  []byte
+	Tags  map[string]int
+	Items []interface{}
+	Err   error
+	Next  *captureNode
+}
+
+// TestCapture ensures captured values render the same as Dump, both directly
+// and after being encoded and decoded.
+func TestCapture(t *testing.T) {
+	n := &captureNode{
+		Name:  "a",
+		Data:  []byte{1, 2, 3},
+		Tags:  map[string]int{"x": 1, "y": 2},
+		Items: []interface{}{1, "s", nil, []int{}, map[int]int(nil)},
+		Err:   errors.New("boom"),
+	}
+	n.Next = n
+	i := 5
+	pi := &i
+
+	configs := []*spew.ConfigState{
+		{Indent: " ", SortKeys: true},
+		{Indent: "\t", SortKeys: true, DisableCapacities: true,
+			DisablePointerAddresses: true, DisableMethods: true},
+		{Indent: " ", MaxDepth: 1},
+	}
+	for ci, cs := range configs {
+		for vi, v := range []interface{}{n, *n, &pi, nil, 1.5, [2]string{"a"}} {
+			want := cs.Sdump(v)
+			capture := cs.Capture(v)
+			if got := capture.Sdump(); got != want {
+				t.Errorf("config #%d value #%d mismatch:\n got: %q\nwant: %q",
+					ci, vi, got, want)
+				continue
+			}
+
+			var buf bytes.Buffer
+			if err := capture.Encode(&buf); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			decoded, err := spew.DecodeCapture(&buf)
+			if err != nil {
+				t.Fatalf("DecodeCapture: %v", err)
+			}
+			if got := decoded.Sdump(); got != want {
+				t.Errorf("config #%d value #%d decoded mismatch:\n got: "+
+					"%q\nwant: %q", ci, vi, got, want)
+			}
+		}
+	}
+}
+
+// TestDecodeCaptureVersion ensures captures with an unknown version are
+// rejected.
+func TestDecodeCaptureVersion(t *testing.T) {
+	capture := spew.Capture(1)
+	capture.Version = 99
+	var buf bytes.Buffer
+	if err := capture.Encode(&buf); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if _, err := spew.DecodeCapture(&buf); !errors.Is(err, spew.ErrCaptureVersion) {
+		t.Errorf("DecodeCapture: got %v, want %v", err, spew.ErrCaptureVersion)
+	}
+}
+
+// TestCaptureOptions ensures captures of values made with the options which
+// observe a dump set render the same as Dump, and captures are not rendered
+// while output is disabled.
+func TestCaptureOptions(t *testing.T) {
+	n := &captureNode{Name: "a", Tags: map[string]int{"x": 1}}
+	nodes := 0
+	configs := []*spew.ConfigState{
+		{Indent: " ", NodeFunc: func(spew.Node) { nodes++ }},
+		{Indent: " ", ProgressInterval: 1,
+			ProgressFunc: func(spew.Progress) bool { return true }},
+	}
+	want := (&spew.ConfigState{Indent: " "}).Sdump(n)
+	for ci, cs := range configs {
+		if got := cs.Capture(n).Sdump(); got != want {
+			t.Errorf("config #%d mismatch:\n got: %q\nwant: %q", ci, got,
+				want)
+		}
+	}
+	if nodes == 0 {
+		t.Errorf("NodeFunc: not called")
+	}
+
+	capture := spew.Capture(n)
+	defer spew.Enable()
+	spew.Disable()
+	if got := capture.Sdump(); got != "" {
+		t.Errorf("Sdump while disabled: %q", got)
+	}
+}
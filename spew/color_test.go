@@ -0,0 +1,56 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestSupportsColor ensures color support is only reported for terminals.
+func TestSupportsColor(t *testing.T) {
+	if spew.SupportsColor(&bytes.Buffer{}) {
+		t.Errorf("SupportsColor: buffer reported as a terminal")
+	}
+
+	f, err := ioutil.TempFile("", "color")
+	if err != nil {
+		t.Fatalf("TempFile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if spew.SupportsColor(f) {
+		t.Errorf("SupportsColor: regular file reported as a terminal")
+	}
+
+	if orig, ok := os.LookupEnv("NO_COLOR"); ok {
+		defer os.Setenv("NO_COLOR", orig)
+	} else {
+		defer os.Unsetenv("NO_COLOR")
+	}
+	os.Setenv("NO_COLOR", "1")
+	if spew.SupportsColor(os.Stdout) {
+		t.Errorf("SupportsColor: reported with NO_COLOR set")
+	}
+}
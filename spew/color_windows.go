@@ -0,0 +1,53 @@
+//go:build windows
+// +build windows
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	// procSetConsoleMode is the SetConsoleMode function of kernel32, which
+	// is not provided by the syscall package.
+	procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+)
+
+// enableVirtualTerminalProcessing is the console mode flag which causes ANSI
+// escape sequences to be interpreted by the console.
+const enableVirtualTerminalProcessing = 0x0004
+
+// enableTerminalColor returns whether or not the passed file is a console
+// which renders ANSI escape sequences.  Virtual terminal processing is enabled
+// for consoles which support it, which is the case since Windows 10.  Older
+// consoles are unable to render them.
+func enableTerminalColor(f *os.File) bool {
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := procSetConsoleMode.Call(uintptr(h),
+		uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -18,50 +18,127 @@ package spew
 
 import (
 	"bytes"
+	"container/list"
+	"container/ring"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"reflect"
+	"runtime"
 	"sort"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Some constants in the form of bytes to avoid string overhead.  This mirrors
 // the technique used in the fmt package.
 var (
-	panicBytes            = []byte("(PANIC=")
-	plusBytes             = []byte("+")
-	iBytes                = []byte("i")
-	trueBytes             = []byte("true")
-	falseBytes            = []byte("false")
-	interfaceBytes        = []byte("(interface {})")
-	commaNewlineBytes     = []byte(",\n")
-	newlineBytes          = []byte("\n")
-	openBraceBytes        = []byte("{")
-	openBraceNewlineBytes = []byte("{\n")
-	closeBraceBytes       = []byte("}")
-	asteriskBytes         = []byte("*")
-	colonBytes            = []byte(":")
-	colonSpaceBytes       = []byte(": ")
-	openParenBytes        = []byte("(")
-	closeParenBytes       = []byte(")")
-	spaceBytes            = []byte(" ")
-	pointerChainBytes     = []byte("->")
-	nilAngleBytes         = []byte("<nil>")
-	maxNewlineBytes       = []byte("<max depth reached>\n")
-	maxShortBytes         = []byte("<max>")
-	circularBytes         = []byte("<already shown>")
-	circularShortBytes    = []byte("<shown>")
-	invalidAngleBytes     = []byte("<invalid>")
-	openBracketBytes      = []byte("[")
-	closeBracketBytes     = []byte("]")
-	percentBytes          = []byte("%")
-	precisionBytes        = []byte(".")
-	openAngleBytes        = []byte("<")
-	closeAngleBytes       = []byte(">")
-	openMapBytes          = []byte("map[")
-	closeMapBytes         = []byte("]")
-	lenEqualsBytes        = []byte("len=")
-	capEqualsBytes        = []byte("cap=")
+	panicBytes              = []byte("(PANIC=")
+	plusBytes               = []byte("+")
+	minusBytes              = []byte("-")
+	iBytes                  = []byte("i")
+	trueBytes               = []byte("true")
+	falseBytes              = []byte("false")
+	interfaceBytes          = []byte("(interface {})")
+	commaNewlineBytes       = []byte(",\n")
+	newlineBytes            = []byte("\n")
+	openBraceBytes          = []byte("{")
+	openBraceNewlineBytes   = []byte("{\n")
+	closeBraceBytes         = []byte("}")
+	stringContinuationBytes = []byte(" +\n")
+	backtickBytes           = []byte("`")
+	lineNumberSepBytes      = []byte(" | ")
+	frameBeginBytes         = []byte("--- SPEW BEGIN id=")
+	frameEndBytes           = []byte("--- SPEW END id=")
+	frameLinesBytes         = []byte(" (lines=")
+	frameCloseBytes         = []byte(" ---\n")
+	commaSpaceBytes         = []byte(", ")
+	asteriskBytes           = []byte("*")
+	colonBytes              = []byte(":")
+	colonSpaceBytes         = []byte(": ")
+	openParenBytes          = []byte("(")
+	closeParenBytes         = []byte(")")
+	spaceBytes              = []byte(" ")
+	pointerChainBytes       = []byte("->")
+	nilAngleBytes           = []byte("<nil>")
+	maxNewlineBytes         = []byte("<max depth reached>\n")
+	maxShortBytes           = []byte("<max>")
+	abortedBytes            = []byte("<dump aborted>")
+	nilParenBytes           = []byte("(nil)")
+	emptyBracesBytes        = []byte("{}")
+	recursiveMethodBytes    = []byte("<recursive method call>")
+	maxNodesBytes           = []byte("<max nodes reached>")
+	spareCapacityBytes      = []byte("<spare capacity ")
+	weakLiveBytes           = []byte("(weak, live)")
+	unexportedBytes         = []byte("unexported")
+	escapeBytes             = []byte("\x1b[")
+	sgrEndBytes             = []byte("m")
+	resetStyleBytes         = []byte("\x1b[0m")
+	formatVersionBytes      = []byte("spew-format-version: ")
+	zeroValueBytes          = []byte("<zero value>")
+	unchangedBytes          = []byte("<unchanged>")
+	absentBytes             = []byte("<absent>")
+	changedArrowBytes       = []byte(" → ")
+	weakCollectedBytes      = []byte("(weak, collected)")
+	circularBytes           = []byte("<already shown>")
+	circularShortBytes      = []byte("<shown>")
+	invalidAngleBytes       = []byte("<invalid>")
+	openBracketBytes        = []byte("[")
+	closeBracketBytes       = []byte("]")
+	percentBytes            = []byte("%")
+	precisionBytes          = []byte(".")
+	openAngleBytes          = []byte("<")
+	closeAngleBytes         = []byte(">")
+	openMapBytes            = []byte("map[")
+	closeMapBytes           = []byte("]")
+	lenEqualsBytes          = []byte("len=")
+	capEqualsBytes          = []byte("cap=")
+	elemEqualsBytes         = []byte("elem=")
+	elidedAngleBytes        = []byte("<elided>")
+	zeroBytes               = []byte("0")
+	sampledBytes            = []byte("<sampled ")
+	ofBytes                 = []byte(" of ")
+	sampledEntriesBytes     = []byte(" entries>")
+	sniffedUTF8Bytes        = []byte(" (utf8 text)")
+	sniffedTextBytes        = []byte(" (text, ")
+	sniffedEscapedBytes     = []byte(" bytes escaped)")
+	sniffedBinaryBytes      = []byte("(binary, ")
+	sniffedUnprintableBytes = []byte(" bytes not printable)")
+	skippedAngleBytes       = []byte("<skipped>")
+	omittedBytes            = []byte(" elements omitted>")
+	omittedOneBytes         = []byte(" element omitted>")
+	minEqualsBytes          = []byte("min=")
+	maxEqualsBytes          = []byte("max=")
+	meanEqualsBytes         = []byte("mean=")
+	samplesEqualsBytes      = []byte("samples=")
+	nonFiniteEqualsBytes    = []byte("non-finite=")
+	ellipsisBytes           = []byte("...")
+	interfaceArrowBytes     = []byte(" ⇒ ")
+	typedNilWarningBytes    = []byte(" ⚠ non-nil interface wrapping nil")
+	offsetEqualsBytes       = []byte("offset=")
+	sizeEqualsBytes         = []byte("size=")
+	paddingEqualsBytes      = []byte("padding=")
+	holesEqualsBytes        = []byte("holes=")
+	backquoteBytes          = []byte("`")
+	sampledEqualsBytes      = []byte("sampled=")
+	kindEqualsBytes         = []byte("kind=")
+	countEqualsBytes        = []byte("count=")
+	bytesEqualsBytes        = []byte("bytes=")
+	typeIndexBytes          = []byte("Type index:\n")
+	reflectTypeBytes        = []byte("(reflect.Type)")
+	fieldsColonBytes        = []byte("Fields: ")
+	methodsColonBytes       = []byte("Methods: ")
+	methodsSuffixBytes      = []byte(" methods ")
+	pointerReceiverBytes    = []byte(" (pointer receiver)")
+	methodSeparatorBytes    = []byte(" / ")
+	jsonBytes               = []byte("(json) ")
+	derivedBytes            = []byte(" (derived)")
+	panicHeaderBytes        = []byte("panic: ")
+	contextHeaderBytes      = []byte("\ncontext:\n")
+	stackHeaderBytes        = []byte("\nstack:\n")
 )
 
 // hexDigits is used to map a decimal value to a hex digit.
@@ -77,12 +154,11 @@ func catchPanic(w io.Writer, v reflect.Value) {
 	}
 }
 
-// handleMethods attempts to call the Error and String methods on the underlying
-// type the passed reflect.Value represents and outputes the result to Writer w.
-//
-// It handles panics in any called methods by catching and displaying the error
-// as the formatted value.
-func handleMethods(cs *ConfigState, w io.Writer, v reflect.Value) (handled bool) {
+// interfaceValue returns a reflect.Value for the passed value which can be
+// used to check whether the underlying type implements an interface with
+// either a value or a pointer receiver.  It returns false when the value
+// cannot be interfaced.
+func interfaceValue(cs *ConfigState, v reflect.Value) (reflect.Value, bool) {
 	// We need an interface to check if the type implements the error or
 	// Stringer interface.  However, the reflect package won't give us an
 	// interface on certain things like unexported struct fields in order
@@ -91,7 +167,7 @@ func handleMethods(cs *ConfigState, w io.Writer, v reflect.Value) (handled bool)
 	// values.
 	if !v.CanInterface() {
 		if UnsafeDisabled {
-			return false
+			return v, false
 		}
 
 		v = unsafeReflectValue(v)
@@ -109,35 +185,380 @@ func handleMethods(cs *ConfigState, w io.Writer, v reflect.Value) (handled bool)
 	if v.CanAddr() {
 		v = v.Addr()
 	}
+	return v, true
+}
 
-	// Is it an error or Stringer?
-	switch iface := v.Interface().(type) {
-	case error:
-		defer catchPanic(w, v)
-		if cs.ContinueOnMethod {
-			w.Write(openParenBytes)
-			w.Write([]byte(iface.Error()))
-			w.Write(closeParenBytes)
-			w.Write(spaceBytes)
-			return false
+var (
+	// errorType and stringerType are the types of the interfaces which
+	// are invoked to display values.
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// inaccessibleMethods returns whether or not the passed value implements the
+// error or Stringer interface, but it can't be invoked because the value was
+// obtained via unexported fields and the unsafe bypass is unavailable.
+func inaccessibleMethods(cs *ConfigState, v reflect.Value) bool {
+	if !UnsafeDisabled || v.CanInterface() {
+		return false
+	}
+	types := []reflect.Type{v.Type()}
+	if !cs.DisablePointerMethods {
+		types = append(types, reflect.PtrTo(v.Type()))
+	}
+	for _, t := range types {
+		if t.Implements(errorType) || t.Implements(stringerType) {
+			return true
 		}
+	}
+	return false
+}
 
-		w.Write([]byte(iface.Error()))
-		return true
+// printInaccessible outputs the placeholder for values with inaccessible
+// methods according to the InaccessibleValues option to Writer w.  It returns
+// whether or not the placeholder replaces the value.
+func printInaccessible(cs *ConfigState, w io.Writer, t reflect.Type) bool {
+	if cs.InaccessibleValues == InaccessibleSilent {
+		return false
+	}
+	w.Write(openParenBytes)
+	w.Write(unexportedBytes)
+	w.Write(spaceBytes)
+	w.Write([]byte(typeString(cs, t)))
+	w.Write(closeParenBytes)
+	if cs.InaccessibleValues == InaccessibleVerbose {
+		w.Write(spaceBytes)
+		return false
+	}
+	return true
+}
+
+// methodText returns the text produced by the error or Stringer interface of
+// the passed value and whether or not it implements either of them.  When it
+// implements both, the MethodPreference option of the passed ConfigState
+// determines which is used.
+func methodText(cs *ConfigState, iface interface{}) (string, bool) {
+	method, ok := textMethod(cs, iface)
+	if !ok {
+		return "", false
+	}
+	return method(), true
+}
+
+// textMethod returns a function which produces the text of the error or
+// Stringer interface of the passed value, in the same way as methodText, and
+// whether or not it implements either of them.
+func textMethod(cs *ConfigState, iface interface{}) (func() string, bool) {
+	switch iface := iface.(type) {
+	case error:
+		if s, ok := iface.(fmt.Stringer); ok {
+			switch cs.MethodPreference {
+			case PreferString:
+				return s.String, true
+			case PreferBoth:
+				return func() string {
+					return iface.Error() + string(methodSeparatorBytes) +
+						s.String()
+				}, true
+			}
+		}
+		return iface.Error, true
 
 	case fmt.Stringer:
-		defer catchPanic(w, v)
-		if cs.ContinueOnMethod {
-			w.Write(openParenBytes)
-			w.Write([]byte(iface.String()))
-			w.Write(closeParenBytes)
-			w.Write(spaceBytes)
-			return false
+		return iface.String, true
+	}
+	return nil, false
+}
+
+// escapeText returns the passed text with all non-printable characters and
+// invalid UTF-8 bytes escaped in the same way as Go string literals.
+func escapeText(text string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&buf, "\\x%02x", text[i])
+		case r == ' ' || strconv.IsPrint(r):
+			buf.WriteString(text[i : i+size])
+		default:
+			quoted := strconv.QuoteRune(r)
+			buf.WriteString(quoted[1 : len(quoted)-1])
 		}
-		w.Write([]byte(iface.String()))
+		i += size
+	}
+	return buf.String()
+}
+
+// reindent returns the passed text with each line after the first indented by
+// the passed number of levels of the configured indent so multi-line text
+// lines up with the nesting of the tree it is displayed in.  Trailing newlines
+// are not followed by an indent.
+func reindent(cs *ConfigState, text string, depth int) string {
+	if depth <= 0 || cs.Indent == "" || !strings.Contains(text, "\n") {
+		return text
+	}
+	indent := strings.Repeat(cs.Indent, depth)
+	trimmed := strings.TrimRight(text, "\n")
+	return strings.Replace(trimmed, "\n", "\n"+indent, -1) + text[len(trimmed):]
+}
+
+// handleMethods attempts to call the Error and String methods on the underlying
+// type the passed reflect.Value represents and outputes the result to Writer w.
+// Lines after the first are indented by the passed number of levels, which is
+// 0 for inline output.
+//
+// It handles panics in any called methods by catching and displaying the error
+// as the formatted value.
+func handleMethods(cs *ConfigState, w io.Writer, v reflect.Value, depth int) (handled bool) {
+	iv, ok := interfaceValue(cs, v)
+	if !ok {
+		if inaccessibleMethods(cs, v) {
+			return printInaccessible(cs, w, v.Type())
+		}
+		return false
+	}
+
+	// Is it an error or Stringer?
+	method, ok := textMethod(cs, iv.Interface())
+	if !ok {
+		return false
+	}
+
+	// Display a marker instead of invoking the method again when it called
+	// back into spew with the same value.
+	defer catchPanic(w, iv)
+	text, ok := callMethod(v, method)
+	if !ok {
+		w.Write(recursiveMethodBytes)
 		return true
 	}
-	return false
+	if cs.EscapeMethodOutput {
+		text = escapeText(text)
+	}
+	text = reindent(cs, text, depth)
+	if cs.ContinueOnMethod {
+		w.Write(openParenBytes)
+		w.Write([]byte(text))
+		w.Write(closeParenBytes)
+		w.Write(spaceBytes)
+		return false
+	}
+	w.Write([]byte(text))
+	return true
+}
+
+// transformValue invokes the TransformFunc callback of the passed ConfigState
+// and returns the replacement value along with whether or not the value was
+// replaced.  Invalid replacements are ignored.
+func transformValue(cs *ConfigState, v reflect.Value) (reflect.Value, bool) {
+	// Provide the callback with a value it is able to interface when
+	// possible so that unexported fields can be inspected as well.
+	iv := v
+	if !iv.CanInterface() {
+		iv = unsafeReflectValue(iv)
+	}
+	replacement, ok := cs.TransformFunc(iv)
+	if !ok || !replacement.IsValid() {
+		return v, false
+	}
+	if replacement.Kind() == reflect.Interface && !replacement.IsNil() {
+		replacement = replacement.Elem()
+	}
+	return replacement, true
+}
+
+// reflectTypeType is the type of the reflect.Type interface.
+var reflectTypeType = reflect.TypeOf((*reflect.Type)(nil)).Elem()
+
+// reflectTypeValue returns the reflect.Type held by the passed value when it
+// is the concrete value of a reflect.Type, along with whether or not it is.
+func reflectTypeValue(v reflect.Value) (reflect.Type, bool) {
+	if v.Kind() != reflect.Ptr || v.IsNil() || !v.Type().Implements(reflectTypeType) {
+		return nil, false
+	}
+	if !v.CanInterface() {
+		v = unsafeReflectValue(v)
+	}
+	if !v.CanInterface() {
+		return nil, false
+	}
+	t, ok := v.Interface().(reflect.Type)
+	return t, ok
+}
+
+// weakPointerTarget returns the target of the passed value when it is a
+// weak.Pointer, which is nil once the target has been garbage collected, along
+// with whether or not it is one.  The type is identified by name so the weak
+// package, which requires Go 1.24, does not need to be imported.
+func weakPointerTarget(v reflect.Value) (reflect.Value, bool) {
+	t := v.Type()
+	if v.Kind() != reflect.Struct || t.PkgPath() != "weak" ||
+		!strings.HasPrefix(t.Name(), "Pointer[") {
+
+		return reflect.Value{}, false
+	}
+	if !v.CanInterface() {
+		v = unsafeReflectValue(v)
+	}
+	if !v.CanInterface() {
+		return reflect.Value{}, false
+	}
+	m := v.MethodByName("Value")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// printTypeKind outputs the kind of the passed type to Writer w in the form
+// "(kind=struct)".
+func printTypeKind(w io.Writer, t reflect.Type) {
+	w.Write(openParenBytes)
+	w.Write(kindEqualsBytes)
+	io.WriteString(w, t.Kind().String())
+	w.Write(closeParenBytes)
+}
+
+// methodSignature returns the signature of the passed method in the form
+// "Name(int, ...string) (bool, error)".  The receiver is excluded.
+func methodSignature(t reflect.Type, m reflect.Method) string {
+	mt := m.Type
+	first := 1
+	if t.Kind() == reflect.Interface {
+		// The methods of interface types do not have a receiver.
+		first = 0
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(m.Name)
+	buf.WriteByte('(')
+	for i := first; i < mt.NumIn(); i++ {
+		if i > first {
+			buf.WriteString(", ")
+		}
+		if mt.IsVariadic() && i == mt.NumIn()-1 {
+			buf.WriteString("...")
+			buf.WriteString(mt.In(i).Elem().String())
+			continue
+		}
+		buf.WriteString(mt.In(i).String())
+	}
+	buf.WriteByte(')')
+
+	switch mt.NumOut() {
+	case 0:
+	case 1:
+		buf.WriteByte(' ')
+		buf.WriteString(mt.Out(0).String())
+	default:
+		buf.WriteString(" (")
+		for i := 0; i < mt.NumOut(); i++ {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(mt.Out(i).String())
+		}
+		buf.WriteByte(')')
+	}
+	return buf.String()
+}
+
+// isAtomicType returns whether or not the passed type is one of the types of
+// the sync/atomic package which provide a Load method.
+func isAtomicType(t reflect.Type) bool {
+	if t.PkgPath() != "sync/atomic" || t.Kind() != reflect.Struct {
+		return false
+	}
+	m, ok := reflect.PtrTo(t).MethodByName("Load")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1
+}
+
+// snapshotValue returns a snapshot of the passed value obtained from the
+// matching SnapshotFuncs callback of the passed ConfigState or, for the types
+// of the sync/atomic package, their Load method.  It returns false when the
+// value has neither or no pointer to it can be obtained.
+func snapshotValue(cs *ConfigState, v reflect.Value) (reflect.Value, bool) {
+	vt := v.Type()
+	fn := cs.SnapshotFuncs[vt]
+	if fn == nil && !isAtomicType(vt) {
+		return v, false
+	}
+
+	// Obtain a pointer to the value.  Values which are not addressable
+	// are already copies, so a pointer to another copy suffices.
+	var ptr reflect.Value
+	switch {
+	case v.CanAddr():
+		if !v.CanInterface() {
+			if UnsafeDisabled {
+				return v, false
+			}
+			v = unsafeReflectValue(v)
+		}
+		ptr = v.Addr()
+	case v.CanInterface():
+		ptr = reflect.New(vt)
+		ptr.Elem().Set(v)
+	default:
+		return v, false
+	}
+
+	var sv reflect.Value
+	if fn != nil {
+		sv = reflect.ValueOf(fn(ptr))
+	} else {
+		sv = ptr.MethodByName("Load").Call(nil)[0]
+	}
+	if !sv.IsValid() {
+		return v, false
+	}
+	if sv.Kind() == reflect.Interface && !sv.IsNil() {
+		sv = sv.Elem()
+	}
+	return sv, true
+}
+
+// SpewRedactor is an interface that may be implemented by types which hold
+// sensitive data.  When a value implements it, spew displays the stand-in
+// value returned by SpewRedacted in place of the original.  As with the error
+// and Stringer interfaces, implementations with a pointer receiver are also
+// detected on non-pointer values when the unsafe package is available.
+//
+// A stand-in of the same type, or a pointer to it, is displayed directly
+// without consulting SpewRedacted again.
+type SpewRedactor interface {
+	SpewRedacted() interface{}
+}
+
+// redactedStandIn returns the stand-in value provided by the SpewRedactor
+// implementation of the type the passed reflect.Value represents along with
+// whether or not the type implements the interface.
+func redactedStandIn(cs *ConfigState, v reflect.Value) (reflect.Value, bool) {
+	orig := v
+	v, ok := interfaceValue(cs, v)
+	if !ok {
+		return orig, false
+	}
+
+	r, ok := v.Interface().(SpewRedactor)
+	if !ok {
+		return orig, false
+	}
+
+	// Use a pointer to the stand-in so nil stand-ins are still represented
+	// by a valid reflect.Value of kind interface.
+	standIn := r.SpewRedacted()
+	sv := reflect.ValueOf(&standIn).Elem()
+	if standIn != nil {
+		sv = sv.Elem()
+		if sv.Kind() == reflect.Ptr && !sv.IsNil() &&
+			sv.Type().Elem() == orig.Type() {
+
+			sv = sv.Elem()
+		}
+	}
+	return sv, true
 }
 
 // printBool outputs a boolean value as true or false to Writer w.
@@ -159,27 +580,844 @@ func printUint(w io.Writer, val uint64, base int) {
 	w.Write([]byte(strconv.FormatUint(val, base)))
 }
 
+// spewTagKey is the key of the struct tags which control how the values of
+// struct fields are displayed.
+const spewTagKey = "spew"
+
+// fieldIntegerBase returns the base specified for the integers within the
+// passed struct field by its spew struct tag, or 0 when it has none.
+func fieldIntegerBase(field reflect.StructField) int {
+	tag, ok := field.Tag.Lookup(spewTagKey)
+	if !ok {
+		return 0
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "hex":
+			return 16
+		case "oct":
+			return 8
+		case "bin":
+			return 2
+		case "dec":
+			return 10
+		}
+	}
+	return 0
+}
+
+// fieldHoldsPointer returns whether or not the uintptr values within the
+// passed struct field hold addresses according to its spew struct tag.
+func fieldHoldsPointer(field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup(spewTagKey)
+	if !ok {
+		return false
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.TrimSpace(opt) == "ptr" {
+			return true
+		}
+	}
+	return false
+}
+
+// integerBase returns the base to display integers of the passed type in
+// according to the IntegerBase and TypeIntegerBases options of the passed
+// ConfigState.  The passed base of the enclosing struct field, if non-zero,
+// takes precedence.
+func integerBase(cs *ConfigState, t reflect.Type, fieldBase int) int {
+	if fieldBase != 0 {
+		return fieldBase
+	}
+	if base, ok := cs.TypeIntegerBases[t]; ok {
+		return base
+	}
+	return cs.IntegerBase
+}
+
+// printInteger outputs the passed signed or unsigned integer value to Writer w
+// in the passed base.  Hexadecimal, octal, and binary values are prefixed with
+// "0x", "0o", and "0b" respectively, while any other base means decimal.  The
+// digits of decimal values are grouped according to the DigitSeparator option
+// of the passed ConfigState.
+func printInteger(w io.Writer, cs *ConfigState, v reflect.Value, base int) {
+	var prefix string
+	switch base {
+	case 16:
+		prefix = "0x"
+	case 8:
+		prefix = "0o"
+	case 2:
+		prefix = "0b"
+	default:
+		base = 10
+	}
+
+	var u uint64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if base == 10 {
+			w.Write([]byte(groupDigits(strconv.FormatInt(i, 10),
+				cs.DigitSeparator)))
+			return
+		}
+		if i < 0 {
+			w.Write(minusBytes)
+			i = -i
+		}
+		u = uint64(i)
+	default:
+		u = v.Uint()
+	}
+	if base == 10 {
+		w.Write([]byte(groupDigits(strconv.FormatUint(u, 10), cs.DigitSeparator)))
+		return
+	}
+	w.Write([]byte(prefix))
+	printUint(w, u, base)
+}
+
+// printCount outputs the passed length, capacity, or count to Writer w with
+// its digits grouped according to the DigitSeparator option of the passed
+// ConfigState.
+func printCount(w io.Writer, cs *ConfigState, n int) {
+	w.Write([]byte(groupDigits(strconv.Itoa(n), cs.DigitSeparator)))
+}
+
+// groupDigits returns the passed decimal integer with sep inserted between
+// each group of three digits, counting from the right.  It is returned
+// unchanged when sep is empty.
+func groupDigits(s, sep string) string {
+	if sep == "" {
+		return s
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	head := len(s) % 3
+	if head == 0 {
+		head = 3
+	}
+	b.WriteString(s[:head])
+	for i := head; i < len(s); i += 3 {
+		b.WriteString(sep)
+		b.WriteString(s[i : i+3])
+	}
+	return b.String()
+}
+
+// floatFormat returns the format and precision to pass to strconv.FormatFloat
+// according to the FloatFormat and FloatPrecision options of the passed
+// ConfigState.
+func floatFormat(cs *ConfigState) (format byte, prec int) {
+	format, prec = cs.FloatFormat, cs.FloatPrecision
+	switch format {
+	case 'e', 'E', 'f', 'g', 'G', 'x', 'X':
+	default:
+		format = 'g'
+	}
+	if prec <= 0 {
+		prec = -1
+	}
+	return format, prec
+}
+
 // printFloat outputs a floating point value using the specified precision,
-// which is expected to be 32 or 64bit, to Writer w.
-func printFloat(w io.Writer, val float64, precision int) {
-	w.Write([]byte(strconv.FormatFloat(val, 'g', -1, precision)))
+// which is expected to be 32 or 64bit, to Writer w according to the float
+// formatting options of the passed ConfigState.
+func printFloat(w io.Writer, cs *ConfigState, val float64, precision int) {
+	format, prec := floatFormat(cs)
+	if cs.MarkNonFinite && isNonFinite(val) {
+		w.Write(openAngleBytes)
+		w.Write([]byte(strconv.FormatFloat(val, format, prec, precision)))
+		w.Write(closeAngleBytes)
+		return
+	}
+	w.Write([]byte(strconv.FormatFloat(val, format, prec, precision)))
+}
+
+// isNonFinite returns whether or not the passed float is NaN or an infinity.
+func isNonFinite(val float64) bool {
+	return math.IsNaN(val) || math.IsInf(val, 0)
 }
 
 // printComplex outputs a complex value using the specified float precision
-// for the real and imaginary parts to Writer w.
-func printComplex(w io.Writer, c complex128, floatPrecision int) {
+// for the real and imaginary parts to Writer w according to the float
+// formatting options of the passed ConfigState.
+func printComplex(w io.Writer, cs *ConfigState, c complex128, floatPrecision int) {
+	format, prec := floatFormat(cs)
 	r := real(c)
 	w.Write(openParenBytes)
-	w.Write([]byte(strconv.FormatFloat(r, 'g', -1, floatPrecision)))
+	w.Write([]byte(strconv.FormatFloat(r, format, prec, floatPrecision)))
 	i := imag(c)
 	if i >= 0 {
 		w.Write(plusBytes)
 	}
-	w.Write([]byte(strconv.FormatFloat(i, 'g', -1, floatPrecision)))
+	w.Write([]byte(strconv.FormatFloat(i, format, prec, floatPrecision)))
 	w.Write(iBytes)
 	w.Write(closeParenBytes)
 }
 
+// visitKey identifies a value for the purpose of detecting circular
+// references.  The type, and length for slices, is included along with the
+// address since distinct values are able to share an address, such as a struct
+// and its first field, or slices which share a backing array.
+type visitKey struct {
+	addr uintptr
+	typ  reflect.Type
+	len  int
+}
+
+// newVisitKey returns the visitKey of the passed pointer or slice.
+func newVisitKey(v reflect.Value) visitKey {
+	key := visitKey{addr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	return key
+}
+
+// truncationMarker returns the passed marker for output which was cut off.
+// When the ExplainTruncation option is set, the name and value of the limit
+// which caused it are inserted before its closing bracket, such as
+// "<max depth reached, MaxDepth=3>".
+func truncationMarker(cs *ConfigState, marker []byte, name string, value int) []byte {
+	if !cs.ExplainTruncation {
+		return marker
+	}
+
+	i := bytes.LastIndexAny(marker, ">)")
+	buf := make([]byte, 0, len(marker)+len(name)+24)
+	buf = append(buf, marker[:i]...)
+	buf = append(buf, ", "...)
+	buf = append(buf, name...)
+	buf = append(buf, '=')
+	buf = strconv.AppendInt(buf, int64(value), 10)
+	return append(buf, marker[i:]...)
+}
+
+// printMaxDepth outputs the passed marker for values beyond the MaxDepth
+// option to Writer w.
+func printMaxDepth(w io.Writer, cs *ConfigState, marker []byte) {
+	w.Write(truncationMarker(cs, marker, "MaxDepth", cs.MaxDepth))
+}
+
+// printOmitted outputs a marker indicating the number of elements of a
+// collection that were omitted to Writer w.
+func printOmitted(w io.Writer, cs *ConfigState, n int) {
+	w.Write(openAngleBytes)
+	printCount(w, cs, n)
+	marker := omittedBytes
+	if n == 1 {
+		marker = omittedOneBytes
+	}
+	w.Write(truncationMarker(cs, marker, "MaxElements", cs.MaxElements))
+}
+
+// printSampledEntries outputs a marker indicating the number of entries of a
+// map that were sampled out of its total number of entries to Writer w.
+func printSampledEntries(w io.Writer, cs *ConfigState, sampled, total int) {
+	w.Write(sampledBytes)
+	printCount(w, cs, sampled)
+	w.Write(ofBytes)
+	printCount(w, cs, total)
+	w.Write(truncationMarker(cs, sampledEntriesBytes, "SampleMaps", cs.SampleMaps))
+}
+
+// sampleKeys returns a random selection of the passed map keys according to
+// the SampleMaps and SampleSeed options of the passed ConfigState, in the
+// same relative order they were passed in, along with whether or not they
+// were sampled.  The keys must already be sorted when SampleSeed is set so
+// the same ones are selected each time.
+func sampleKeys(cs *ConfigState, keys []reflect.Value) ([]reflect.Value, bool) {
+	if cs.SampleMaps <= 0 || len(keys) <= cs.SampleMaps {
+		return keys, false
+	}
+	perm := rand.Perm
+	if cs.SampleSeed != 0 {
+		perm = rand.New(rand.NewSource(cs.SampleSeed)).Perm
+	}
+	indices := perm(len(keys))[:cs.SampleMaps]
+	sort.Ints(indices)
+	sampled := make([]reflect.Value, len(indices))
+	for i, index := range indices {
+		sampled[i] = keys[index]
+	}
+	return sampled, true
+}
+
+// shownElements returns the number of leading and trailing elements of a
+// collection with n elements that should be displayed according to the
+// MaxElements and TailElements options of the passed ConfigState.  Any
+// elements in between are omitted.
+func shownElements(cs *ConfigState, n int) (head, tail int) {
+	if cs.MaxElements <= 0 {
+		return n, 0
+	}
+	if cs.TailElements > 0 {
+		tail = cs.TailElements
+	}
+	if n <= cs.MaxElements+tail {
+		return n, 0
+	}
+	return cs.MaxElements, tail
+}
+
+// displayWidth returns the number of characters the passed output occupies
+// when displayed, which excludes the escape sequences of styles.
+func displayWidth(b []byte) int {
+	width := 0
+	for len(b) > 0 {
+		if bytes.HasPrefix(b, escapeBytes) {
+			end := bytes.Index(b, sgrEndBytes)
+			if end < 0 {
+				break
+			}
+			b = b[end+len(sgrEndBytes):]
+			continue
+		}
+		_, size := utf8.DecodeRune(b)
+		b = b[size:]
+		width++
+	}
+	return width
+}
+
+// isNumericKind returns whether the passed kind is an integer or floating
+// point number.
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// numericFloat returns the value of the passed integer or floating point
+// reflect.Value as a float64.
+func numericFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return float64(v.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// printNumeric outputs the passed integer or floating point reflect.Value to
+// Writer w according to the float formatting options of the passed
+// ConfigState.
+func printNumeric(w io.Writer, cs *ConfigState, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		printInt(w, v.Int(), 10)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		printUint(w, v.Uint(), 10)
+	case reflect.Float32:
+		printFloat(w, cs, v.Float(), 32)
+	case reflect.Float64:
+		printFloat(w, cs, v.Float(), 64)
+	}
+}
+
+// truncateString returns the passed string truncated according to the
+// MaxStringLength option of the passed ConfigState along with whether or not
+// it was truncated.  Strings are only truncated on UTF-8 character
+// boundaries.
+func truncateString(cs *ConfigState, s string) (string, bool) {
+	if cs.MaxStringLength <= 0 || len(s) <= cs.MaxStringLength {
+		return s, false
+	}
+	n := cs.MaxStringLength
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n], true
+}
+
+// canRawQuote returns whether or not the passed string spans multiple lines
+// and can be written as a raw string literal, which means it is valid UTF-8
+// and contains no backticks or control characters other than newlines and
+// tabs.
+func canRawQuote(s string) bool {
+	if !strings.Contains(s, "\n") || !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if r == '`' || r == '\uFEFF' ||
+			(unicode.IsControl(r) && r != '\n' && r != '\t') {
+			return false
+		}
+	}
+	return true
+}
+
+// anonStringPrefix is the prefix of the placeholders strings are replaced with
+// by the Anonymize option.
+const anonStringPrefix = "str#"
+
+// isPrintableByte returns whether or not the passed byte is printable ASCII or
+// whitespace, which is to say it is not escaped when quoted.
+func isPrintableByte(b byte) bool {
+	return (b >= ' ' && b <= '~') || b == '\t' || b == '\n' || b == '\r'
+}
+
+// sniffBytes examines the passed bytes to determine how they are best
+// displayed.  It returns whether or not they are valid UTF-8 text without
+// control characters other than whitespace along with the number of bytes
+// which are not printable ASCII or whitespace.
+func sniffBytes(b []byte) (utf8Text bool, unprintable int) {
+	utf8Text = utf8.Valid(b)
+	for i := 0; i < len(b); {
+		r, size := rune(b[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRune(b[i:])
+		}
+		if size == 1 && !isPrintableByte(b[i]) {
+			unprintable++
+		}
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			utf8Text = false
+		}
+		i += size
+	}
+	return utf8Text, unprintable
+}
+
+// splitString splits the passed string into pieces of at most n bytes, or a
+// single character when that is longer, without splitting any characters.
+func splitString(s string, n int) []string {
+	var pieces []string
+	for len(s) > n {
+		i := n
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		if i == 0 {
+			_, i = utf8.DecodeRuneInString(s)
+		}
+		pieces = append(pieces, s[:i])
+		s = s[i:]
+	}
+	if s != "" {
+		pieces = append(pieces, s)
+	}
+	return pieces
+}
+
+// printCharacter outputs the character the passed byte or rune value is
+// plausibly meant to hold, quoted and preceded by a space, to Writer w when
+// the ShowCharacters option of the passed ConfigState is enabled.  Bytes are
+// only considered characters when they are printable ASCII since other values
+// are more likely to be binary data.
+func printCharacter(w io.Writer, cs *ConfigState, v reflect.Value) {
+	if !cs.ShowCharacters {
+		return
+	}
+	var r rune
+	switch v.Kind() {
+	case reflect.Uint8:
+		b := v.Uint()
+		if b < ' ' || b > '~' {
+			return
+		}
+		r = rune(b)
+	case reflect.Int32:
+		r = rune(v.Int())
+		if !unicode.IsPrint(r) {
+			return
+		}
+	default:
+		return
+	}
+	w.Write(spaceBytes)
+	w.Write([]byte(strconv.QuoteRune(r)))
+}
+
+// printRunes outputs the passed string to Writer w as a sequence of quoted
+// runs of ASCII characters and individual non-ASCII characters followed by
+// their Unicode code points.  Bytes which are not valid UTF-8 are included in
+// the quoted runs as escape sequences.
+func printRunes(w io.Writer, s string) {
+	start := 0
+	first := true
+	flush := func(end int) {
+		if end > start {
+			if !first {
+				w.Write(spaceBytes)
+			}
+			w.Write([]byte(strconv.Quote(s[start:end])))
+			first = false
+		}
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r < utf8.RuneSelf || (r == utf8.RuneError && size == 1) {
+			i += size
+			continue
+		}
+		flush(i)
+		if !first {
+			w.Write(spaceBytes)
+		}
+		fmt.Fprintf(w, "%q %U", r, r)
+		first = false
+		i += size
+		start = i
+	}
+	flush(len(s))
+	if first {
+		w.Write([]byte(strconv.Quote(s)))
+	}
+}
+
+// isASCII returns whether the passed string consists entirely of ASCII
+// characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// printTruncatedLen outputs the marker that follows a string which was
+// truncated according to the MaxStringLength option and had the passed full
+// length to Writer w.
+func printTruncatedLen(w io.Writer, cs *ConfigState, n int) {
+	w.Write(ellipsisBytes)
+	w.Write(openParenBytes)
+	w.Write(lenEqualsBytes)
+	printCount(w, cs, n)
+	w.Write(truncationMarker(cs, closeParenBytes, "MaxStringLength",
+		cs.MaxStringLength))
+}
+
+var (
+	// listType and ringType are the types of the containers which are
+	// displayed as the sequence of values they hold.
+	listType = reflect.TypeOf(list.List{})
+	ringType = reflect.TypeOf(ring.Ring{})
+)
+
+// containerValues returns the values held by the passed value, in order, when
+// it is a container/list List or container/ring Ring.  The second return
+// value indicates whether or not it is one of them.
+func containerValues(v reflect.Value) ([]reflect.Value, bool) {
+	switch v.Type() {
+	case listType:
+		return listValues(v), true
+	case ringType:
+		return ringValues(v), true
+	}
+	return nil, false
+}
+
+// listValues returns the values held by the elements of the passed list.List.
+// The elements are accessed through the unexported fields since the methods
+// require a pointer.  At most the recorded length of the list is walked, so
+// corrupted links can't cause an endless loop.
+func listValues(v reflect.Value) []reflect.Value {
+	n := int(v.FieldByName("len").Int())
+	values := make([]reflect.Value, 0, n)
+	e := v.FieldByName("root").FieldByName("next")
+	for i := 0; i < n && !e.IsNil(); i++ {
+		values = append(values, e.Elem().FieldByName("Value"))
+		e = e.Elem().FieldByName("next")
+	}
+	return values
+}
+
+// ringValues returns the values held by the elements of the passed ring.Ring
+// starting with the passed element.  The walk stops once an element is seen
+// again, so it finishes even when the passed element is a copy which is not
+// itself part of the ring.
+func ringValues(v reflect.Value) []reflect.Value {
+	values := []reflect.Value{v.FieldByName("Value")}
+	next := v.FieldByName("next")
+	if next.IsNil() {
+		// An uninitialized ring is a ring of one element.
+		return values
+	}
+
+	// The element which the passed one was copied from, if it is a copy,
+	// is the one its predecessor links to.
+	visited := make(map[uintptr]bool)
+	if prev := v.FieldByName("prev"); !prev.IsNil() {
+		visited[prev.Elem().FieldByName("next").Pointer()] = true
+	}
+	if v.CanAddr() {
+		visited[v.Addr().Pointer()] = true
+	}
+	for r := next; !r.IsNil() && !visited[r.Pointer()]; r = r.Elem().FieldByName("next") {
+		visited[r.Pointer()] = true
+		values = append(values, r.Elem().FieldByName("Value"))
+	}
+	return values
+}
+
+// iteratorArity returns the number of values yielded per element by the
+// passed type when it is an iterator function with the signature of iter.Seq
+// or iter.Seq2, and 0 otherwise.  Named types with other element types are
+// recognized as well since only the shape of the signature matters.
+func iteratorArity(t reflect.Type) int {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return 0
+	}
+	yield := t.In(0)
+	if yield.Kind() != reflect.Func || yield.NumOut() != 1 ||
+		yield.Out(0).Kind() != reflect.Bool {
+
+		return 0
+	}
+	if n := yield.NumIn(); n == 1 || n == 2 {
+		return n
+	}
+	return 0
+}
+
+// sampledElement is an element drained from an iterator function.
+type sampledElement []reflect.Value
+
+// drainIterator calls the passed iterator function to collect up to the
+// maximum number of elements specified by the passed ConfigState.  It returns
+// the collected elements, whether or not the iterator had more, and the
+// value of a panic raised by the iterator, if any.
+func drainIterator(cs *ConfigState, v reflect.Value) (elems []sampledElement, more bool, panicVal interface{}) {
+	if !v.CanInterface() {
+		v = unsafeReflectValue(v)
+	}
+	if !v.CanInterface() {
+		return nil, false, nil
+	}
+
+	max := cs.MaxIteratorElements
+	yield := reflect.MakeFunc(v.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		if len(elems) == max {
+			more = true
+			return []reflect.Value{reflect.ValueOf(false)}
+		}
+		elems = append(elems, sampledElement(args))
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+
+	defer func() {
+		if err := recover(); err != nil {
+			panicVal = err
+		}
+	}()
+	v.Call([]reflect.Value{yield})
+	return elems, more, nil
+}
+
+// printSampled outputs an annotation of the number of elements drained from
+// an iterator function to Writer w followed by a space.
+func printSampled(w io.Writer, n int, more bool) {
+	w.Write(openParenBytes)
+	w.Write(sampledEqualsBytes)
+	printInt(w, int64(n), 10)
+	if more {
+		w.Write(plusBytes)
+	}
+	w.Write(closeParenBytes)
+	w.Write(spaceBytes)
+}
+
+// structField describes a struct field that is to be displayed.
+type structField struct {
+	name  string // name to display
+	path  string // path to the field relative to the struct
+	field reflect.StructField
+	value reflect.Value
+}
+
+// structFields returns the fields of the passed struct value in the order
+// they should be displayed according to the options of the passed
+// ConfigState.
+func structFields(cs *ConfigState, v reflect.Value) []structField {
+	fields := fieldsOf(v, "")
+	if cs.FlattenEmbedded && !cs.ShowLayout {
+		fields = flattenEmbedded(fields)
+	}
+	if (cs.SortFields || cs.FieldLess != nil) && !cs.ShowLayout {
+		sort.SliceStable(fields, func(i, j int) bool {
+			if cs.FieldLess != nil {
+				return cs.FieldLess(fields[i].field, fields[j].field)
+			}
+			return fields[i].name < fields[j].name
+		})
+	}
+	return fields
+}
+
+// fieldsOf returns the fields of the passed struct value in declaration
+// order with their paths prefixed by the passed prefix.
+func fieldsOf(v reflect.Value, prefix string) []structField {
+	vt := v.Type()
+	numFields := v.NumField()
+	fields := make([]structField, 0, numFields)
+	for i := 0; i < numFields; i++ {
+		vtf := vt.Field(i)
+		fields = append(fields, structField{
+			name:  vtf.Name,
+			path:  prefix + vtf.Name,
+			field: vtf,
+			value: v.Field(i),
+		})
+	}
+	return fields
+}
+
+// flattenEmbedded replaces the embedded structs, and non-nil pointers to
+// structs, in the passed fields with the fields they contain in the same way
+// Go promotes them.  Fields which Go would not promote because they are
+// shadowed or ambiguous are named by their full path instead, such as
+// "Base.ID", so no data is hidden.
+func flattenEmbedded(fields []structField) []structField {
+	type promotedField struct {
+		structField
+		depth int
+	}
+
+	var all []promotedField
+	visited := make(map[uintptr]bool)
+	var expand func(fields []structField, depth int)
+	expand = func(fields []structField, depth int) {
+		for _, sf := range fields {
+			if sf.field.Anonymous {
+				ev := sf.value
+				if ev.Kind() == reflect.Ptr && !ev.IsNil() &&
+					ev.Type().Elem().Kind() == reflect.Struct &&
+					!visited[ev.Pointer()] {
+
+					visited[ev.Pointer()] = true
+					ev = ev.Elem()
+				}
+				if ev.Kind() == reflect.Struct {
+					expand(fieldsOf(ev, sf.path+"."), depth+1)
+					continue
+				}
+			}
+			all = append(all, promotedField{sf, depth})
+		}
+	}
+	expand(fields, 0)
+
+	// Determine the shallowest depth of each name and how many fields share
+	// it there.  Only fields which are unique at that depth are promoted.
+	minDepths := make(map[string]int)
+	counts := make(map[string]int)
+	for _, pf := range all {
+		minDepth, ok := minDepths[pf.name]
+		switch {
+		case !ok || pf.depth < minDepth:
+			minDepths[pf.name] = pf.depth
+			counts[pf.name] = 1
+		case pf.depth == minDepth:
+			counts[pf.name]++
+		}
+	}
+
+	flattened := make([]structField, 0, len(all))
+	for _, pf := range all {
+		if pf.depth != minDepths[pf.name] || counts[pf.name] > 1 {
+			pf.name = pf.path
+		}
+		flattened = append(flattened, pf.structField)
+	}
+	return flattened
+}
+
+// fieldTag returns the portion of the tag of the passed struct field that
+// should be displayed according to the ShowTags and TagKeys options of the
+// passed ConfigState.
+func fieldTag(cs *ConfigState, field reflect.StructField) string {
+	if !cs.ShowTags || field.Tag == "" {
+		return ""
+	}
+	if len(cs.TagKeys) == 0 {
+		return string(field.Tag)
+	}
+
+	var tags []string
+	for _, key := range cs.TagKeys {
+		if value, ok := field.Tag.Lookup(key); ok {
+			tags = append(tags, key+":"+strconv.Quote(value))
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// printFieldLayout outputs the byte offset and size of the passed struct field
+// to Writer w.
+func printFieldLayout(w io.Writer, field reflect.StructField) {
+	w.Write(openParenBytes)
+	w.Write(offsetEqualsBytes)
+	printUint(w, uint64(field.Offset), 10)
+	w.Write(spaceBytes)
+	w.Write(sizeEqualsBytes)
+	printUint(w, uint64(field.Type.Size()), 10)
+	w.Write(closeParenBytes)
+}
+
+// printStructLayout outputs the total size of the passed struct type along
+// with the total amount of padding it contains and the location of each
+// padding hole, in the form offset:size, to Writer w.
+func printStructLayout(w io.Writer, t reflect.Type) {
+	// Find any gaps between the end of one field and the start of the next
+	// as well as after the final field.
+	var holes [][2]uintptr
+	var padding, end uintptr
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Offset > end {
+			holes = append(holes, [2]uintptr{end, field.Offset - end})
+			padding += field.Offset - end
+		}
+		if fieldEnd := field.Offset + field.Type.Size(); fieldEnd > end {
+			end = fieldEnd
+		}
+	}
+	if t.Size() > end {
+		holes = append(holes, [2]uintptr{end, t.Size() - end})
+		padding += t.Size() - end
+	}
+
+	w.Write(openAngleBytes)
+	w.Write(sizeEqualsBytes)
+	printUint(w, uint64(t.Size()), 10)
+	w.Write(spaceBytes)
+	w.Write(paddingEqualsBytes)
+	printUint(w, uint64(padding), 10)
+	if len(holes) > 0 {
+		w.Write(spaceBytes)
+		w.Write(holesEqualsBytes)
+		w.Write(openBracketBytes)
+		for i, hole := range holes {
+			if i > 0 {
+				w.Write(spaceBytes)
+			}
+			printUint(w, uint64(hole[0]), 10)
+			w.Write(colonBytes)
+			printUint(w, uint64(hole[1]), 10)
+		}
+		w.Write(closeBracketBytes)
+	}
+	w.Write(closeAngleBytes)
+}
+
 // printHexPtr outputs a uintptr formatted as hexadecimal with a leading '0x'
 // prefix to Writer w.
 func printHexPtr(w io.Writer, p uintptr) {
@@ -214,6 +1452,132 @@ func printHexPtr(w io.Writer, p uintptr) {
 	w.Write(buf)
 }
 
+// isPrimitiveKind returns whether the passed kind is a boolean, numeric, or
+// string kind.
+func isPrimitiveKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.Uintptr, reflect.String,
+		reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return isNumericKind(kind)
+}
+
+// typeString returns the name of the passed type as it should be displayed
+// according to the options of the passed ConfigState.
+func typeString(cs *ConfigState, t reflect.Type) string {
+	name := t.String()
+	if cs.ShowUnderlyingTypes && t.PkgPath() != "" &&
+		isPrimitiveKind(t.Kind()) {
+
+		name += "=" + t.Kind().String()
+	}
+	return name
+}
+
+// printStaticType outputs the passed interface type followed by an arrow which
+// indicates it holds the type that follows to Writer w.  Nothing is output
+// for nil types.
+func printStaticType(w io.Writer, t reflect.Type) {
+	if t == nil {
+		return
+	}
+	w.Write([]byte(t.String()))
+	w.Write(interfaceArrowBytes)
+}
+
+// isNilPointer returns whether the passed value is a nil pointer, which makes
+// an interface holding it non-nil.
+func isNilPointer(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// isLeafKind returns whether values of the passed kind are printed directly
+// rather than by descending into the elements they contain.
+func isLeafKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Invalid, reflect.Array, reflect.Slice, reflect.Map,
+		reflect.Struct, reflect.Ptr, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+// spewFuncPrefix is the prefix of the names of the functions in this package.
+var spewFuncPrefix = reflect.TypeOf(ConfigState{}).PkgPath() + "."
+
+// callerOutsidePackage returns the file and line of the innermost caller which
+// is not a function of this package after skipping the number of additional
+// frames specified by the CallerSkip option, or an empty string when there is
+// none.
+func callerOutsidePackage(cs *ConfigState) string {
+	pcs := make([]uintptr, 32+cs.CallerSkip)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	skip := cs.CallerSkip
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, spewFuncPrefix) {
+			if skip <= 0 {
+				return frame.File + ":" + strconv.Itoa(frame.Line)
+			}
+			skip--
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// fieldPath returns the path to the struct field named name within the value
+// at the parent path.
+func fieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// indexPath returns the path to the element at index i within the array or
+// slice at the parent path.
+func indexPath(parent string, i int) string {
+	return parent + "[" + strconv.Itoa(i) + "]"
+}
+
+// keyPath returns the path to the map entry with the passed key within the
+// map at the parent path.
+func keyPath(parent string, key reflect.Value) string {
+	return parent + "[" + fmt.Sprint(key) + "]"
+}
+
+// mapKeyPath returns the path of the passed map key itself, as opposed to the
+// value it maps to, within the map located at the parent path.  Braces are
+// used in place of brackets so the two can be told apart.
+func mapKeyPath(parent string, key reflect.Value) string {
+	return parent + "{" + fmt.Sprint(key) + "}"
+}
+
+// redactValue invokes the RedactFunc callback of the passed ConfigState, if
+// any, for the leaf value v located at path.  When the callback requests the
+// value be redacted, the replacement is output to Writer w and true is
+// returned.
+func redactValue(cs *ConfigState, w io.Writer, path string, v reflect.Value) bool {
+	if cs.RedactFunc == nil || !isLeafKind(v.Kind()) {
+		return false
+	}
+
+	// Provide the callback with a value it is able to interface when
+	// possible so that unexported fields can be inspected as well.
+	if !v.CanInterface() {
+		v = unsafeReflectValue(v)
+	}
+	replacement, redact := cs.RedactFunc(path, v.Type(), v)
+	if !redact {
+		return false
+	}
+	w.Write([]byte(replacement))
+	return true
+}
+
 // valuesSorter implements sort.Interface to allow a slice of reflect.Value
 // elements to be sorted.
 type valuesSorter struct {
@@ -234,7 +1598,7 @@ func newValuesSorter(values []reflect.Value, cs *ConfigState) sort.Interface {
 		vs.strings = make([]string, len(values))
 		for i := range vs.values {
 			b := bytes.Buffer{}
-			if !handleMethods(cs, &b, vs.values[i]) {
+			if !handleMethods(cs, &b, vs.values[i], 0) {
 				vs.strings = nil
 				break
 			}
@@ -1,3 +1,6 @@
+//go:build !spew_noop
+// +build !spew_noop
+
 /*
  * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
  *
@@ -18,7 +21,10 @@ package spew_test
 
 import (
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -89,6 +95,102 @@ func (e customError) Error() string {
 	return fmt.Sprintf("error: %d", int(e))
 }
 
+// detailedError is used to test the precedence between the error and Stringer
+// interfaces for types which implement both.
+type detailedError int
+
+func (e detailedError) Error() string {
+	return "failed"
+}
+
+func (e detailedError) String() string {
+	return fmt.Sprintf("failed: code %d", int(e))
+}
+
+// multiline is used to test re-indenting the multi-line output of Stringer
+// interfaces.
+type multiline string
+
+func (m multiline) String() string {
+	return string(m)
+}
+
+// redactor is used to test the SpewRedactor interface on a non-pointer
+// receiver.
+type redactor string
+
+// SpewRedacted implements the SpewRedactor interface for testing display of
+// stand-in values on types with non-pointer receivers.
+func (r redactor) SpewRedacted() interface{} {
+	return "<redacted>"
+}
+
+// predactor is used to test the SpewRedactor interface on a pointer receiver.
+type predactor struct {
+	user     string
+	password string
+}
+
+// SpewRedacted implements the SpewRedactor interface for testing display of
+// stand-in values on types with only pointer receivers.
+func (r *predactor) SpewRedacted() interface{} {
+	return &predactor{user: r.user, password: "***"}
+}
+
+// dumper is used to test the SpewDumper interface.
+type dumper []string
+
+// DumpSpew implements the SpewDumper interface for testing custom rendering
+// of values which participates in indentation.
+func (d dumper) DumpSpew(w io.Writer, depth int, cfg *spew.ConfigState, dump func(interface{})) {
+	indent := strings.Repeat(cfg.Indent, depth)
+	fmt.Fprint(w, "[\n")
+	for _, line := range d {
+		fmt.Fprintf(w, "%s%s- %s\n", indent, cfg.Indent, line)
+	}
+	fmt.Fprintf(w, "%s]", indent)
+}
+
+// nestingDumper is used to test the SpewDumper interface with nested values.
+type nestingDumper struct {
+	child interface{}
+}
+
+// DumpSpew implements the SpewDumper interface for testing nested values which
+// are dumped as part of the dump in progress.
+func (d *nestingDumper) DumpSpew(w io.Writer, depth int, cfg *spew.ConfigState, dump func(interface{})) {
+	indent := strings.Repeat(cfg.Indent, depth)
+	fmt.Fprintf(w, "<\n%s%s", indent, cfg.Indent)
+	dump(d.child)
+	fmt.Fprintf(w, "\n%s>", indent)
+}
+
+// fielder is used to test the SpewFielder interface.
+type fielder struct {
+	Raw string
+}
+
+// SpewFields implements the SpewFielder interface for testing display of
+// derived values alongside the real fields.
+func (f fielder) SpewFields() map[string]interface{} {
+	if f.Raw == "panic" {
+		panic("bad raw")
+	}
+	return map[string]interface{}{"Upper": strings.ToUpper(f.Raw), "Len": len(f.Raw)}
+}
+
+// wrapper is used to test replacing values via the TransformFunc option.
+type wrapper struct {
+	v interface{}
+}
+
+// lockedCounter is used to test snapshots of values which are protected by a
+// lock via the SnapshotFuncs option.
+type lockedCounter struct {
+	mu sync.Mutex
+	N  int
+}
+
 // stringizeWants converts a slice of wanted test output into a format suitable
 // for a test error message.
 func stringizeWants(wants []string) string {
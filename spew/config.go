@@ -21,8 +21,117 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"time"
 )
 
+// MethodPreference specifies which method is invoked for types which implement
+// both the error and Stringer interfaces.
+type MethodPreference int
+
+const (
+	// PreferError specifies the Error method is invoked.
+	PreferError MethodPreference = iota
+
+	// PreferString specifies the String method is invoked.
+	PreferString
+
+	// PreferBoth specifies both methods are invoked and their results are
+	// displayed together.
+	PreferBoth
+)
+
+// InaccessibleMode specifies how values are displayed when they implement the
+// error or Stringer interface, but it can't be invoked because they were
+// obtained via unexported fields and the unsafe bypass is unavailable, such as
+// when built with the safe tag.
+type InaccessibleMode int
+
+const (
+	// InaccessibleSilent specifies the internals of the values are
+	// displayed as if they did not implement the interfaces.
+	InaccessibleSilent InaccessibleMode = iota
+
+	// InaccessiblePlaceholder specifies the values are replaced with a
+	// placeholder naming their type, such as "(unexported time.Time)".
+	InaccessiblePlaceholder
+
+	// InaccessibleVerbose specifies the placeholder is displayed followed
+	// by the internals of the values.
+	InaccessibleVerbose
+)
+
+const (
+	// FormatVersion1 is the first version of the text format of the Dump
+	// family of functions.
+	FormatVersion1 = 1
+
+	// LatestFormatVersion is the latest version of the text format of the
+	// Dump family of functions.
+	LatestFormatVersion = FormatVersion1
+)
+
+// Progress describes the progress of a dump.  It is passed to the ProgressFunc
+// callback of ConfigState.
+type Progress struct {
+	// Nodes is the number of values visited so far.
+	Nodes int
+
+	// Bytes is the number of bytes written so far.
+	Bytes int64
+
+	// Elapsed is the time elapsed since the dump started.
+	Elapsed time.Duration
+}
+
+// Node describes a value which has been rendered by the Dump family of
+// functions.  It is passed to the NodeFunc callback of ConfigState.
+type Node struct {
+	// Path is the path of the value within the argument being dumped, in
+	// the same form as the paths passed to the RedactFunc callback.
+	Path string
+
+	// Type is the type of the value.  It is nil for invalid values.
+	Type reflect.Type
+
+	// Value is the value itself.
+	Value reflect.Value
+
+	// Depth is the nesting level of the value.
+	Depth int
+
+	// Start and End are the offsets of the text the value was rendered as,
+	// including its indentation, within the output of the argument being
+	// dumped.
+	Start, End int64
+}
+
+// DumpMetrics describes a single call of the Dump family of functions.  It is
+// passed to the Metrics option of ConfigState.
+type DumpMetrics struct {
+	// Caller is the file and line of the call site outside of this package,
+	// such as "/src/app/handler.go:42".
+	Caller string
+
+	// Bytes is the number of bytes written.
+	Bytes int64
+
+	// Duration is the time taken by the call.
+	Duration time.Duration
+
+	// Truncations is the number of values which were truncated due to the
+	// MaxDepth, MaxElements, or MaxStringLength options.
+	Truncations int
+}
+
+// MetricsRecorder is implemented by types which record metrics about calls of
+// the Dump family of functions, such as adapters to Prometheus or
+// OpenTelemetry instruments.  The number of invocations is the number of times
+// RecordDump is called.
+type MetricsRecorder interface {
+	RecordDump(m DumpMetrics)
+}
+
 // ConfigState houses the configuration options used by spew to format and
 // display values.  There is a global instance, Config, that is used to control
 // all top-level Formatter and Dump functionality.  Each ConfigState instance
@@ -76,6 +185,63 @@ type ConfigState struct {
 	// data structures in tests.
 	DisableCapacities bool
 
+	// ExplicitNilCollections specifies that nil maps and slices should be
+	// displayed in Go syntax, such as (map[string]int)(nil) when dumping and
+	// map[string]int(nil) with the custom formatter, while empty ones are
+	// displayed with braces on a single line, such as (map[string]int) {}
+	// when dumping.  This makes the distinction between them hard to miss.
+	ExplicitNilCollections bool
+
+	// ShowLengths specifies whether or not the lengths of maps and strings
+	// are always displayed when dumping, such as (map[string]int) (len=0),
+	// rather than only when they are non-zero.  This keeps sizes visible
+	// regardless of whether or not any contents are displayed.
+	ShowLengths bool
+
+	// SchemaOnly specifies that only the shape of values should be displayed
+	// when dumping, which is their types, the names of struct fields, and
+	// the lengths and capacities of collections and strings.  Numbers,
+	// strings, and other scalar values, including map keys and the contents
+	// of byte slices, are replaced with "<elided>", and neither pointer
+	// addresses nor the results of error and Stringer interfaces are
+	// displayed.  This is useful for sharing the structure of data in bug
+	// reports when the values themselves are confidential.
+	SchemaOnly bool
+
+	// Anonymize specifies that scalar values should be replaced with
+	// placeholders of the same type when dumping while keeping the types,
+	// lengths, and structure of values intact.  Strings are replaced with
+	// "str#1", "str#2", and so on, with equal strings within a dumped value
+	// sharing a placeholder, numbers with 0, booleans with true, and the
+	// contents of byte slices with zeros.  The results of error and Stringer
+	// interfaces are not displayed.  This allows dumps of production data to
+	// be shared without leaking the data itself.
+	Anonymize bool
+
+	// MethodPreference specifies which method is invoked for types which
+	// implement both the error and Stringer interfaces.  The default,
+	// PreferError, invokes Error.  PreferString invokes String instead, and
+	// PreferBoth displays the result of Error followed by the result of
+	// String separated by " / ".
+	MethodPreference MethodPreference
+
+	// EscapeMethodOutput specifies that non-printable characters, such as
+	// ANSI escape sequences, carriage returns, newlines, and bidirectional
+	// text controls, in the output of error and Stringer interfaces should
+	// be escaped in the same way as Go string literals, for example "\x1b".
+	// This prevents a malicious or buggy method from corrupting terminal
+	// output or spoofing log lines.
+	EscapeMethodOutput bool
+
+	// InaccessibleValues specifies how values which implement the error or
+	// Stringer interface are displayed when the interface can't be invoked
+	// because they were obtained via unexported fields and the unsafe
+	// bypass is unavailable.  This is only the case when the unsafe package
+	// is unavailable, such as when built with the safe tag.  The default,
+	// InaccessibleSilent, displays their internals as if they did not
+	// implement the interfaces.
+	InaccessibleValues InaccessibleMode
+
 	// ContinueOnMethod specifies whether or not recursion should continue once
 	// a custom error or Stringer interface is invoked.  The default, false,
 	// means it will print the results of invoking the custom error or Stringer
@@ -98,22 +264,550 @@ type ConfigState struct {
 	// be spewed to strings and sorted by those strings.  This is only
 	// considered if SortKeys is true.
 	SpewKeys bool
+
+	// SampleMaps specifies the number of entries of larger maps to display
+	// when dumping.  The entries are selected at random from the whole map,
+	// rather than being the first ones in iteration order, and are followed
+	// by a marker with the total number of entries, such as
+	// "<sampled 10 of 5,000 entries>".  The default, 0, displays all of the
+	// entries of maps.
+	SampleMaps int
+
+	// SampleSeed specifies the seed used to select the entries of maps
+	// sampled due to the SampleMaps option.  When it is non-zero the same
+	// entries are selected for maps with the same keys every time, which
+	// keeps the output stable between runs.  The default, 0, selects
+	// different entries each time.
+	SampleSeed int64
+
+	// AlignMapValues specifies that the values of maps should be aligned in
+	// a column after their keys when dumping, which makes maps such as
+	// configuration blocks easier to scan.  Maps with keys which span
+	// multiple lines are displayed as usual.
+	AlignMapValues bool
+
+	// MaxElements specifies the maximum number of leading elements of arrays,
+	// slices, and maps to display.  The remaining elements are replaced by a
+	// marker which indicates how many were omitted.  The default, 0, means
+	// there is no limit.  Byte arrays and slices which are displayed in
+	// hexdump style are not affected.
+	MaxElements int
+
+	// TailElements specifies the number of trailing elements of arrays,
+	// slices, and maps to display in addition to the leading elements when
+	// a collection is truncated due to MaxElements.  This is useful when the
+	// most recently appended entries are of interest.  It has no effect
+	// unless MaxElements is set.
+	TailElements int
+
+	// MaxStringLength specifies the maximum number of bytes of strings to
+	// display.  Longer strings are truncated on a character boundary and
+	// followed by an ellipsis and their full length, such as "...(len=53421)",
+	// so it is clear how much was elided.  The default, 0, means there is no
+	// limit.
+	MaxStringLength int
+
+	// WrapStrings specifies the maximum number of bytes of strings to
+	// display on each line when dumping.  Longer strings are split on
+	// character boundaries into quoted pieces joined by " +" with each
+	// continuation on its own line indented one level deeper than the
+	// string, so very long values do not produce a single enormous line.
+	// The default, 0, never wraps strings.
+	WrapStrings int
+
+	// RawStrings specifies that multi-line strings which can be written as
+	// raw string literals, because they contain no backticks or control
+	// characters other than tabs, should be displayed between backticks
+	// across real lines when dumping, with each line after the first indented
+	// one level deeper than the string.  This keeps embedded SQL, YAML, and
+	// templates readable instead of one long line of \n escapes.
+	RawStrings bool
+
+	// SniffBytes specifies that the contents of byte arrays and slices
+	// should be examined to pick how they are displayed when dumping.  Valid
+	// UTF-8 text without control characters other than whitespace is
+	// displayed as a quoted string, mostly printable data as a quoted string
+	// with the rest escaped, and anything else as a hexdump.  Each is
+	// annotated with the presentation chosen and why, such as "(utf8 text)"
+	// or "(binary, 12 of 16 bytes not printable)".
+	SniffBytes bool
+
+	// PrettyJSONThreshold specifies the minimum length of strings which are
+	// checked for holding a JSON object or array when dumping.  Those which
+	// do are displayed indented to the current depth and marked with
+	// "(json)" instead of as one long quoted line.  Strings truncated due to
+	// MaxStringLength are not checked.  The default, 0, disables the check.
+	PrettyJSONThreshold int
+
+	// ShowRunes specifies that strings which contain non-ASCII characters
+	// should be displayed as a sequence of quoted ASCII runs and individual
+	// non-ASCII characters along with their Unicode code points, such as
+	// "caf" 'é' U+00E9.  This is useful when debugging normalization and
+	// encoding issues which are otherwise hidden by the quoted form.
+	ShowRunes bool
+
+	// ShowCharacters specifies that byte and rune values which plausibly
+	// hold a character should be displayed as both their number and the
+	// quoted character, such as (uint8) 65 'A', which helps when debugging
+	// parsers and tokenizers.  Bytes are only shown as characters when they
+	// are printable ASCII.  Byte slices are still displayed as a hexdump.
+	ShowCharacters bool
+
+	// WarnTypedNil specifies that nil pointers held by interfaces, including
+	// the arguments passed to the output functions, should be annotated with
+	// a warning since the interface holding them is not nil.  Comparing such
+	// an interface, typically an error, to nil is a classic source of bugs.
+	WarnTypedNil bool
+
+	// IntegerBase specifies the base integers are displayed in, which is
+	// one of 16, 8, or 2 for hexadecimal, octal, or binary with a "0x",
+	// "0o", or "0b" prefix respectively.  The default, 0, as well as any
+	// other value means decimal.  This is useful when debugging flags,
+	// masks, and hardware registers.
+	//
+	// The base may be overridden for the integers within individual struct
+	// fields with a `spew:"hex"`, `spew:"oct"`, `spew:"bin"`, or
+	// `spew:"dec"` struct tag.
+	IntegerBase int
+
+	// TypeIntegerBases specifies the base integers of specific types are
+	// displayed in, which overrides IntegerBase for them.  The bases are the
+	// same as those of IntegerBase.  Struct tags take precedence.
+	TypeIntegerBases map[reflect.Type]int
+
+	// PointerTypes specifies uintptr types whose values hold addresses, such
+	// as those passed to system calls and cgo, which should be displayed in
+	// pointer notation when dumping, such as (Handle)(0xc000012345), rather
+	// than as plain numbers.  The values of individual struct fields are
+	// displayed this way with a `spew:"ptr"` struct tag.
+	PointerTypes map[reflect.Type]bool
+
+	// PointerResolver is invoked with the type and value of the uintptr
+	// values displayed in pointer notation, due to PointerTypes or the
+	// `spew:"ptr"` struct tag, to look up the value they point to when
+	// dumping.  When it returns true, the value it returns is dumped after
+	// the address, such as (Handle)(0xc000012345) → (main.Window) {...}.
+	// It must not return values which hold unsafe pointers to memory which
+	// may have been freed.
+	PointerResolver func(t reflect.Type, addr uintptr) (target reflect.Value, ok bool)
+
+	// DigitSeparator specifies a separator, such as "," or "_", to insert
+	// between each group of three digits of decimal integers, lengths, and
+	// capacities, such as 1,234,567, so large counts are not misread.  The
+	// default, an empty string, means digits are not grouped.
+	DigitSeparator string
+
+	// FloatFormat specifies the format floats, including the parts of
+	// complex numbers, are displayed in, which is one of the formats of
+	// strconv.FormatFloat: 'e', 'E', 'f', 'g', 'G', 'x', or 'X'.  The
+	// default, 0, as well as any other value means 'g'.
+	FloatFormat byte
+
+	// FloatPrecision specifies the number of digits floats are displayed
+	// with, which is the number of digits after the decimal point for the
+	// 'e', 'E', 'f', 'x', and 'X' formats or the total number of
+	// significant digits for the 'g' and 'G' formats.  The default, 0,
+	// means the smallest number of digits necessary to represent the value
+	// exactly, which avoids silently losing precision.  Limiting it hides
+	// the artifacts of binary floating point, such as 0.30000000000000004.
+	FloatPrecision int
+
+	// MarkNonFinite specifies that floats which are NaN or infinite should
+	// be displayed enclosed in angle brackets, such as <NaN> and <+Inf>, so
+	// they stand out in large dumps.  The numeric summaries produced due to
+	// the SummarizeNumbers option also include the number of them.
+	MarkNonFinite bool
+
+	// SummarizeNumbers specifies a threshold above which arrays and slices of
+	// integers and floats are displayed as a statistical summary consisting
+	// of the minimum, maximum, and mean along with a few sample values from
+	// each end rather than every element.  The default, 0, means numeric
+	// collections are always displayed in full.  This only applies to the
+	// Dump family of functions.
+	SummarizeNumbers int
+
+	// PackWidth specifies the maximum width, including the indentation, of
+	// the elements of slices and arrays of numbers and booleans displayed
+	// together on a single line when dumping, such as
+	// ([4]float64) (len=4 cap=4) {1, 2, 3, 4}, rather than one per line.
+	// Collections which do not fit, or which are truncated, are displayed
+	// one element per line as usual.  The default, 0, means elements are
+	// never packed.
+	PackWidth int
+
+	// ShowUnderlyingTypes specifies that the underlying kind of named types
+	// whose underlying type is a boolean, numeric, or string type should be
+	// displayed along with the type name, such as "(main.Flag=uint8)".  This
+	// makes the representation of opaque named types apparent without
+	// having to look up their definitions.
+	ShowUnderlyingTypes bool
+
+	// ShowInterfaceTypes specifies that the static type of values stored in
+	// interfaces with methods, such as struct fields of type io.Reader,
+	// should be displayed along with the concrete type, for example
+	// "(io.Reader ⇒ *bytes.Buffer)".  The empty interface is not annotated.
+	// This helps diagnose cases where the wrong implementation is used.
+	ShowInterfaceTypes bool
+
+	// ElideElementTypes specifies that the type of each element of arrays
+	// and slices should not be displayed when dumping when all of them have
+	// the same type, since it is already known from the type of the
+	// collection.  For collections of interfaces, the common type of the
+	// elements is displayed once in the header instead, such as
+	// ([]interface {}) (len=2 cap=2 elem=int).  This considerably shrinks
+	// dumps of large collections without losing any information.
+	ElideElementTypes bool
+
+	// ShowLayout specifies that the memory layout of structs should be
+	// displayed when dumping.  Each field is annotated with its byte offset
+	// and size, and each struct is followed by its total size, the total
+	// amount of padding, and the location of each padding hole in the form
+	// offset:size.  This is useful when optimizing the packing of structs.
+	ShowLayout bool
+
+	// ShowTags specifies that the tag of each struct field should be
+	// displayed next to the field name when dumping, so dumps double as a
+	// view of how structs map to their serialized forms.
+	ShowTags bool
+
+	// TagKeys specifies the keys of struct tags, such as "json" or "db", to
+	// display when ShowTags is set.  The default, an empty list, means the
+	// entire tag is displayed.
+	TagKeys []string
+
+	// FlattenEmbedded specifies that the fields of embedded structs should be
+	// displayed at the level of the struct which embeds them, as Go's field
+	// promotion does, instead of nested under the embedded type name when
+	// dumping.  Fields which are shadowed or ambiguous are displayed with
+	// their full path, such as "Base.ID".  Embedded pointers are followed
+	// when they are not nil, but their addresses are not displayed.  This
+	// option has no effect when ShowLayout is set.
+	FlattenEmbedded bool
+
+	// SortFields specifies that the fields of structs should be displayed
+	// sorted alphabetically by name instead of in declaration order when
+	// dumping, so dumps of structs whose declaration order differs between
+	// versions are able to be diffed.  Flattened fields are sorted by their
+	// displayed name.  This option has no effect when ShowLayout is set.
+	SortFields bool
+
+	// FieldLess, when set, specifies the order the fields of structs should
+	// be displayed in when dumping instead of declaration order.  It reports
+	// whether field a should be displayed before field b, and takes
+	// precedence over SortFields.  Fields it considers equal keep their
+	// relative order.  This option has no effect when ShowLayout is set.
+	FieldLess func(a, b reflect.StructField) bool
+
+	// AlignFields specifies that the names of struct fields should be padded
+	// when dumping so the values of the fields of a struct start in the
+	// same column, which makes wide structs easier to scan.
+	AlignFields bool
+
+	// ShowTypeDetails specifies that reflect.Type values should be dumped
+	// along with their method set and, for struct types, a summary of their
+	// fields.  Regardless of this option, reflect.Type values are displayed
+	// as their name and kind rather than the internal structure of the
+	// reflect package.
+	ShowTypeDetails bool
+
+	// DisableContainerTraversal specifies whether or not to disable
+	// displaying the elements of container/list List and container/ring Ring
+	// values in order.  When disabled, their internal structure of linked
+	// elements is displayed as is.
+	DisableContainerTraversal bool
+
+	// MaxIteratorElements specifies the maximum number of elements to drain
+	// from iterator functions, such as iter.Seq and iter.Seq2, in order to
+	// display them in place of the function pointer.  The elements are
+	// annotated as sampled, along with a trailing '+' when the iterator had
+	// more elements.  Iterators are not drained by default since doing so
+	// calls them, which may have side effects or exhaust single-use
+	// iterators.
+	MaxIteratorElements int
+
+	// ShowIndices specifies that each element of arrays and slices should be
+	// prefixed with its index, such as "[17]: ", when dumping.  This makes
+	// it easier to correlate elements in large dumps with the code that
+	// accesses them by index.
+	ShowIndices bool
+
+	// RedactFunc specifies an optional callback that is invoked for every
+	// leaf value (any value that is not a struct, array, slice, map,
+	// pointer, or interface) before it is printed.  The path argument
+	// identifies the location of the value relative to the top-level
+	// argument, for example "Users[2].Password" or "Env[HOME]".  Map keys
+	// themselves are identified with braces instead, for example
+	// "Env{HOME}", so a policy for the values of a map does not mask its
+	// keys.  When the callback returns true for redact, the returned
+	// replacement string is printed verbatim in place of the value.
+	//
+	// This allows applications to implement masking policies for sensitive
+	// data in a single place rather than at every call site.
+	RedactFunc func(path string, t reflect.Type, v reflect.Value) (replacement string, redact bool)
+
+	// TypeFormatters specifies functions which produce the inline
+	// representation of values of specific types for the custom formatter
+	// used by the Printf family of functions.  When a value's type has an
+	// entry, the function is invoked with the fmt.State, which may be
+	// queried for the '+' and '#' flags, in place of the normal output
+	// and any error or Stringer interfaces.  Pointers are followed as
+	// usual, so an entry for a type also applies to pointers to it.
+	//
+	// This allows the output of types from third-party packages to be
+	// customized without having to wrap them.
+	TypeFormatters map[reflect.Type]func(fs fmt.State, v reflect.Value)
+
+	// SkipTypes specifies types whose values, wherever they appear, should
+	// be replaced with a placeholder when dumping and formatting, such as
+	// (context.Context) <skipped>, rather than displaying their internal
+	// state.  The placeholder names the type whenever types are shown.
+	// Interface types match the struct fields, elements, and values declared
+	// with them regardless of the values they hold.  This is useful for types
+	// such as context.Context and *sql.DB whose internals are large and
+	// rarely of interest.
+	SkipTypes map[reflect.Type]bool
+
+	// IncludeTypes specifies the only types whose scalar values, such as
+	// numbers and strings, should be displayed when dumping and formatting.
+	// The values of other scalar types are replaced with a placeholder in
+	// the same way as SkipTypes, while structs, collections, pointers, and
+	// interfaces are still traversed to reach the values of the included
+	// types.  The default, an empty map, includes all types.
+	IncludeTypes map[reflect.Type]bool
+
+	// SnapshotFuncs specifies accessor callbacks for specific types, such as
+	// the nodes of lock-free data structures, which return a consistent
+	// snapshot of values of the type to display in their place.  The
+	// callbacks are invoked with a pointer to the value so they are able to
+	// use atomic loads or locks instead of spew racing on raw memory reads.
+	// Values which are not addressable, such as unexported struct fields
+	// when the unsafe package is not available, are displayed as is.
+	//
+	// The types of the sync/atomic package, such as atomic.Pointer and
+	// atomic.Value, are always displayed using their Load method in the
+	// same way.
+	SnapshotFuncs map[reflect.Type]func(v reflect.Value) interface{}
+
+	// Theme specifies the styles used to display the elements of dumps
+	// with the Dump family of functions, such as colored type names and
+	// values.  The styles are applied with ANSI escape sequences, so a theme
+	// should only be set when the output is written to a terminal which
+	// supports them, as reported by SupportsColor.  ThemeDark, ThemeLight,
+	// and ThemeMonochromeBold are provided.  The default, nil, means the
+	// output is not styled.
+	Theme *Theme
+
+	// ShowSpareCapacity specifies whether or not the Dump family of
+	// functions also displays the elements of slices between their length
+	// and capacity.  They are displayed after the elements of the slice
+	// following a marker such as "<spare capacity [2:4]>".  Stale data in
+	// the spare capacity of a slice is visible to later appends and
+	// reslicing, so this is useful for debugging aliasing issues.
+	ShowSpareCapacity bool
+
+	// ShowSliceAliases specifies whether or not slices which share a backing
+	// array with a slice which was dumped earlier by the same call to one of
+	// the Dump family of functions are annotated.  Slices which start within
+	// the earlier slice are annotated with the equivalent slice expression,
+	// such as "(aliases arg0.Items[4:10])", where argN is the position of the
+	// argument the slice was found in.  Others which overlap it are
+	// annotated with "(aliased by ...)" instead.  This is useful for
+	// debugging accidental aliasing, such as after append.
+	ShowSliceAliases bool
+
+	// ShowTypeIndex specifies whether or not the Dump family of functions
+	// appends an index of every distinct type encountered by the same call,
+	// such as "(string) count=3 bytes=45", after the dumped values.  The
+	// number of bytes is the total size of the rendered values of the type,
+	// including the values they contain, and the index is ordered by it from
+	// largest to smallest.  This is useful for finding what is filling a
+	// huge dump.
+	ShowTypeIndex bool
+
+	// ExplainTruncation specifies whether or not the markers for output which
+	// was cut off by a limit, such as MaxDepth, MaxElements, or
+	// MaxStringLength, include the name and value of the limit.  For
+	// example, "<max depth reached>" is displayed as "<max depth reached,
+	// MaxDepth=3>" instead.  This lets readers of partial output know exactly
+	// why it is incomplete and which limit to raise to see more.
+	ExplainTruncation bool
+
+	// FormatVersion pins the version of the text format of the Dump family
+	// of functions.  Future releases which change the spacing or ordering
+	// of the output for a given configuration will only do so in a new
+	// format version, so pinning it keeps golden files stable.  The default,
+	// 0, means LatestFormatVersion.  Versions newer than
+	// LatestFormatVersion are not known to this release and are treated as
+	// LatestFormatVersion.
+	FormatVersion int
+
+	// ShowFormatVersion specifies whether or not the Dump family of
+	// functions writes a header line with the format version of the output,
+	// such as "spew-format-version: 1", before the dumped values.
+	ShowFormatVersion bool
+
+	// FrameDumps specifies whether or not the output of each call to the
+	// Dump family of functions is surrounded by marker lines carrying an
+	// identifier unique to the call, such as "--- SPEW BEGIN id=1f2e3d4c ---"
+	// and "--- SPEW END id=1f2e3d4c (lines=87) ---", where lines is the
+	// number of lines between them.  This allows tools to reliably extract
+	// complete dumps from logs which interleave the output of many
+	// goroutines.
+	FrameDumps bool
+
+	// LineNumbers specifies whether or not the Dump family of functions
+	// prefixes each line of output with its line number, counting from 1 for
+	// each call, such as "  42 | ".  This makes it easy to refer to a line of
+	// a dump which has been shared with others.
+	LineNumbers bool
+
+	// Disabled specifies whether or not the output functions are cheap
+	// no-ops for this configuration, the same as they are for every
+	// configuration after Disable is called.
+	Disabled bool
+
+	// ParallelDump specifies whether or not the Dump family of functions
+	// formats multiple arguments concurrently.  Each argument is formatted
+	// into its own buffer by up to GOMAXPROCS goroutines and the results are
+	// then written in order, so the output is identical to the sequential
+	// output.  This considerably reduces the time taken to dump several
+	// large, independent values.
+	//
+	// NOTE: Since the arguments are formatted concurrently, every method and
+	// callback invoked while formatting them may be called from several
+	// goroutines at once and must be safe for concurrent use.  This includes
+	// error and Stringer methods, SpewDumper implementations, the functions
+	// in SnapshotFuncs, and callbacks such as NodeFunc, ProgressFunc,
+	// RedactFunc, TransformFunc, and OutputFunc.  The values reported to
+	// NodeFunc and ProgressFunc describe each argument on its own, so the
+	// calls for different arguments may be interleaved.  The Metrics
+	// recorder is still invoked once per call after every argument is
+	// written.
+	ParallelDump bool
+
+	// MaxNodes specifies the maximum number of values to visit while
+	// dumping each argument, regardless of their depth or the size of the
+	// output, which bounds the time spent dumping values of any shape.  The
+	// traversal stops once it is reached and the marker
+	// "<max nodes reached>" is written after the output so far.  The
+	// default, 0, means there is no limit.
+	MaxNodes int
+
+	// ProgressFunc specifies an optional callback which is invoked
+	// periodically while dumping a value with the Dump family of functions.
+	// It is passed the progress of the dump of the current argument, so
+	// long-running dumps of very large values are able to report it.
+	// Returning false aborts the dump, in which case the marker
+	// "<dump aborted>" is written after the output so far and any remaining
+	// arguments are not dumped.
+	ProgressFunc func(p Progress) bool
+
+	// ProgressInterval specifies the number of values visited between
+	// invocations of ProgressFunc.  The default, 0, means every 10000
+	// values.
+	ProgressInterval int
+
+	// NodeFunc specifies an optional callback which is invoked for every
+	// value rendered by the Dump family of functions once its text has been
+	// written, so values are reported after the values they contain.  The
+	// output is still written as usual, which allows the callback to build
+	// an index of the output, scan values for secrets, or mirror a dump into
+	// a structured sink alongside the text.
+	NodeFunc func(n Node)
+
+	// Metrics specifies an optional recorder which is passed metrics about
+	// each call of the Dump family of functions once it completes, so call
+	// sites which produce large or slow dumps in production are able to be
+	// found.
+	//
+	// NOTE: The recorder must be safe for concurrent use when the
+	// configuration is shared between goroutines.
+	Metrics MetricsRecorder
+
+	// CallerSkip specifies the number of additional stack frames to skip
+	// when determining the call site reported by features such as the
+	// Metrics option and Printer.CollapseRepeats.  The call site is the
+	// innermost caller outside of this package by default, so packages
+	// which wrap spew in their own helpers should set it to the number of
+	// their own frames in order to report the originating file and line.
+	CallerSkip int
+
+	// Scrubbers specifies regular expressions which are replaced in the
+	// complete output for each value, in order, before it is written and
+	// before OutputFunc is invoked.  This allows dumps to be normalized for
+	// comparison or sanitized for sharing.  ScrubAddresses, ScrubUUIDs, and
+	// ScrubTimestamps are provided for common cases.
+	Scrubbers []Scrubber
+
+	// OutputFunc specifies an optional callback which is invoked with the
+	// complete output for each value before it is written.  For the Dump
+	// family of functions it is called once per argument, including the
+	// trailing newline, and for the custom formatter it is called once per
+	// formatted value.  The returned string is written in place of the
+	// original output.
+	//
+	// This allows transformations such as scrubbing, re-indenting, or
+	// adding log framing without wrapping every io.Writer.
+	OutputFunc func(out string) string
+
+	// TransformFunc specifies an optional callback which is invoked for
+	// every value before it is displayed.  When it returns true for ok, the
+	// returned replacement is displayed in place of the original value.
+	// This allows internal representations to be converted to display
+	// forms or wrapper types to be collapsed.
+	//
+	// Replacements are walked as usual, so the values they contain are
+	// passed to the callback as well.  However, values with the same type
+	// as one which is currently being replaced are not, so callbacks which
+	// return values containing the original type do not recurse forever.
+	TransformFunc func(v reflect.Value) (replacement reflect.Value, ok bool)
 }
 
 // Config is the active configuration of the top-level functions.
 // The configuration can be changed by modifying the contents of spew.Config.
 var Config = ConfigState{Indent: " "}
 
+// formatVersion returns the version of the text format to use according to the
+// FormatVersion option.
+func (c *ConfigState) formatVersion() int {
+	if c.FormatVersion <= 0 || c.FormatVersion > LatestFormatVersion {
+		return LatestFormatVersion
+	}
+	return c.FormatVersion
+}
+
+// skipsType returns whether or not values of the passed type are replaced
+// with a placeholder according to the SkipTypes and IncludeTypes options.
+func (c *ConfigState) skipsType(t reflect.Type) bool {
+	if c.SkipTypes[t] {
+		return true
+	}
+	return len(c.IncludeTypes) > 0 && isLeafKind(t.Kind()) && !c.IncludeTypes[t]
+}
+
+// hidesValues returns whether or not the SchemaOnly or Anonymize options
+// prevent the actual values from being displayed.
+func (c *ConfigState) hidesValues() bool {
+	return c.SchemaOnly || c.Anonymize
+}
+
 // Errorf is a wrapper for fmt.Errorf that treats each argument as if it were
 // passed with a Formatter interface returned by c.NewFormatter.  It returns
 // the formatted string as a value that satisfies error.  See NewFormatter
 // for formatting details.
 //
+// Arguments for %w verbs are passed through unchanged so the returned error
+// wraps them as usual and works with errors.Is and errors.As.  The same is
+// true of the arguments for '*' widths and precisions.
+//
 // This function is shorthand for the following syntax:
 //
 //	fmt.Errorf(format, c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Errorf(format string, a ...interface{}) (err error) {
-	return fmt.Errorf(format, c.convertArgs(a)...)
+	if NoopBuild || outputDisabled(c) {
+		return fmt.Errorf(format, a...)
+	}
+	return fmt.Errorf(format, unwrapArgs(format, a, c.convertArgs(a))...)
 }
 
 // Fprint is a wrapper for fmt.Fprint that treats each argument as if it were
@@ -125,6 +819,9 @@ func (c *ConfigState) Errorf(format string, a ...interface{}) (err error) {
 //
 //	fmt.Fprint(w, c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Fprint(w io.Writer, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(c) {
+		return 0, nil
+	}
 	return fmt.Fprint(w, c.convertArgs(a)...)
 }
 
@@ -137,6 +834,9 @@ func (c *ConfigState) Fprint(w io.Writer, a ...interface{}) (n int, err error) {
 //
 //	fmt.Fprintf(w, format, c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(c) {
+		return 0, nil
+	}
 	return fmt.Fprintf(w, format, c.convertArgs(a)...)
 }
 
@@ -148,6 +848,9 @@ func (c *ConfigState) Fprintf(w io.Writer, format string, a ...interface{}) (n i
 //
 //	fmt.Fprintln(w, c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(c) {
+		return 0, nil
+	}
 	return fmt.Fprintln(w, c.convertArgs(a)...)
 }
 
@@ -160,6 +863,9 @@ func (c *ConfigState) Fprintln(w io.Writer, a ...interface{}) (n int, err error)
 //
 //	fmt.Print(c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Print(a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(c) {
+		return 0, nil
+	}
 	return fmt.Print(c.convertArgs(a)...)
 }
 
@@ -172,6 +878,9 @@ func (c *ConfigState) Print(a ...interface{}) (n int, err error) {
 //
 //	fmt.Printf(format, c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Printf(format string, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(c) {
+		return 0, nil
+	}
 	return fmt.Printf(format, c.convertArgs(a)...)
 }
 
@@ -184,6 +893,9 @@ func (c *ConfigState) Printf(format string, a ...interface{}) (n int, err error)
 //
 //	fmt.Println(c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Println(a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(c) {
+		return 0, nil
+	}
 	return fmt.Println(c.convertArgs(a)...)
 }
 
@@ -195,6 +907,9 @@ func (c *ConfigState) Println(a ...interface{}) (n int, err error) {
 //
 //	fmt.Sprint(c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Sprint(a ...interface{}) string {
+	if NoopBuild || outputDisabled(c) {
+		return ""
+	}
 	return fmt.Sprint(c.convertArgs(a)...)
 }
 
@@ -206,6 +921,9 @@ func (c *ConfigState) Sprint(a ...interface{}) string {
 //
 //	fmt.Sprintf(format, c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Sprintf(format string, a ...interface{}) string {
+	if NoopBuild || outputDisabled(c) {
+		return ""
+	}
 	return fmt.Sprintf(format, c.convertArgs(a)...)
 }
 
@@ -217,6 +935,9 @@ func (c *ConfigState) Sprintf(format string, a ...interface{}) string {
 //
 //	fmt.Sprintln(c.NewFormatter(a), c.NewFormatter(b))
 func (c *ConfigState) Sprintln(a ...interface{}) string {
+	if NoopBuild || outputDisabled(c) {
+		return ""
+	}
 	return fmt.Sprintln(c.convertArgs(a)...)
 }
 
@@ -274,6 +995,50 @@ func (c *ConfigState) Dump(a ...interface{}) {
 	fdump(c, os.Stdout, a...)
 }
 
+// DumpIf displays the passed parameters to standard out exactly the same as
+// Dump when cond is true.  No formatting work is performed when it is false,
+// so permanent diagnostic dumps guarded by feature flags cost nothing while
+// they are disabled.
+func (c *ConfigState) DumpIf(cond bool, a ...interface{}) {
+	if cond {
+		fdump(c, os.Stdout, a...)
+	}
+}
+
+// DumpWhen displays the passed parameters to standard out exactly the same as
+// Dump when pred returns true.  The predicate is only invoked once, and no
+// formatting work is performed when it returns false.
+func (c *ConfigState) DumpWhen(pred func() bool, a ...interface{}) {
+	if pred() {
+		fdump(c, os.Stdout, a...)
+	}
+}
+
+// FdumpNamed formats and displays the values of the passed alternating labels
+// and values to io.Writer w with each prefixed by its label.  See DumpNamed
+// for details.
+func (c *ConfigState) FdumpNamed(w io.Writer, labelsAndValues ...interface{}) {
+	fdumpNamed(c, w, labelsAndValues)
+}
+
+// SdumpNamed returns a string with the values of the passed alternating labels
+// and values formatted the same as DumpNamed.
+func (c *ConfigState) SdumpNamed(labelsAndValues ...interface{}) string {
+	var buf bytes.Buffer
+	fdumpNamed(c, &buf, labelsAndValues)
+	return buf.String()
+}
+
+// DumpNamed displays the values of the passed alternating labels and values
+// to standard out exactly the same as Dump, except each is prefixed by its
+// label, such as "request: (*http.Request)...", so the dumps of several
+// values in one call remain attributable.  Labels which are not strings are
+// formatted with fmt.Sprint, and a trailing value without a label is
+// displayed without one.
+func (c *ConfigState) DumpNamed(labelsAndValues ...interface{}) {
+	fdumpNamed(c, os.Stdout, labelsAndValues)
+}
+
 // Sdump returns a string with the passed arguments formatted exactly the same
 // as Dump.
 func (c *ConfigState) Sdump(a ...interface{}) string {
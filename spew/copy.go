@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"reflect"
+)
+
+// CopyOptions specifies the behavior of DeepCopy.  The zero value copies the
+// exported parts of values while preserving cycles and shared pointers.
+type CopyOptions struct {
+	// CopyUnexported specifies that unexported struct fields are copied as
+	// well.  This relies on the unsafe package, so they are left as zero
+	// values when it is unavailable, such as when built with the safe tag.
+	CopyUnexported bool
+
+	// UnexportedFunc, when set, is called for each unexported struct field
+	// with its path, in the same form as the paths passed to the RedactFunc
+	// option, and value.  When it returns true for ok, the returned value
+	// is used as the copy of the field instead, which allows values such as
+	// mutexes to be replaced rather than copied.  As with CopyUnexported,
+	// the field is left as a zero value when the unsafe package is
+	// unavailable.
+	UnexportedFunc func(path string, v reflect.Value) (copied reflect.Value, ok bool)
+
+	// BreakCycles specifies that every reference to a pointer, map, or slice
+	// is copied separately, and references which would form a cycle are
+	// left nil.  By default, values which are referenced multiple times are
+	// only copied once, so cycles and sharing are preserved in the copy.
+	BreakCycles bool
+}
+
+// copyState contains information about the state of a deep copy.
+type copyState struct {
+	opts   *CopyOptions
+	copies map[visitKey]reflect.Value
+}
+
+// copyRef copies the passed pointer, map, or slice src into dst by calling
+// fill with the newly created ref, which must already be of the type of dst,
+// while detecting references which were already copied or form a cycle.
+func (c *copyState) copyRef(dst, src, ref reflect.Value, fill func()) {
+	key := newVisitKey(src)
+	if copied, ok := c.copies[key]; ok {
+		if !c.opts.BreakCycles {
+			dst.Set(copied)
+		}
+		return
+	}
+	c.copies[key] = ref
+	if c.opts.BreakCycles {
+		defer delete(c.copies, key)
+	}
+	fill()
+	dst.Set(ref)
+}
+
+// copy copies the passed value located at path into dst, which must be
+// settable.
+func (c *copyState) copy(path string, dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		ref := reflect.New(src.Type().Elem())
+		c.copyRef(dst, src, ref, func() {
+			c.copy(path, ref.Elem(), src.Elem())
+		})
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := src.Elem()
+		copied := reflect.New(elem.Type()).Elem()
+		c.copy(path, copied, elem)
+		dst.Set(copied)
+
+	case reflect.Struct:
+		t := src.Type()
+		for i := 0; i < src.NumField(); i++ {
+			sf := t.Field(i)
+			fpath := fieldPath(path, sf.Name)
+			sv, dv := src.Field(i), dst.Field(i)
+			if sf.PkgPath != "" {
+				dv = unsafeReflectValue(dv)
+				if !dv.CanSet() {
+					continue
+				}
+				if c.opts.UnexportedFunc != nil {
+					if copied, ok := c.opts.UnexportedFunc(fpath,
+						unsafeReflectValue(sv)); ok {
+
+						dv.Set(copied)
+						continue
+					}
+				}
+				if !c.opts.CopyUnexported {
+					continue
+				}
+				sv = unsafeReflectValue(sv)
+			}
+			c.copy(fpath, dv, sv)
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		ref := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.copyRef(dst, src, ref, func() {
+			for i := 0; i < src.Len(); i++ {
+				c.copy(indexPath(path, i), ref.Index(i), src.Index(i))
+			}
+		})
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(indexPath(path, i), dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		ref := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.copyRef(dst, src, ref, func() {
+			t := src.Type()
+			for _, k := range src.MapKeys() {
+				kpath := keyPath(path, k)
+				ck := reflect.New(t.Key()).Elem()
+				c.copy(kpath, ck, k)
+				cv := reflect.New(t.Elem()).Elem()
+				c.copy(kpath, cv, src.MapIndex(k))
+				ref.SetMapIndex(ck, cv)
+			}
+		})
+
+	default:
+		// Scalars are copied by value, while functions, channels, and
+		// unsafe pointers are shared with the original.
+		dst.Set(src)
+	}
+}
+
+// DeepCopy returns a deep copy of the passed value which shares no pointers,
+// maps, or slices with it, which is useful for snapshotting values before
+// dumping them asynchronously, such as with DumpAsync, while they continue to
+// be modified.  Pointers, maps, and slices are traversed the same way as
+// Dump, so cycles are handled safely and, by default, preserved in the copy.
+// Functions, channels, and unsafe pointers are shared with the original.
+//
+// Unexported struct fields are left as zero values unless requested with the
+// CopyUnexported or UnexportedFunc options.  A nil opts uses the defaults.
+func DeepCopy(v interface{}, opts *CopyOptions) interface{} {
+	if v == nil {
+		return nil
+	}
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	c := copyState{opts: opts, copies: make(map[visitKey]reflect.Value)}
+	src := reflect.ValueOf(v)
+	dst := reflect.New(src.Type()).Elem()
+	c.copy("", dst, src)
+	return dst.Interface()
+}
@@ -0,0 +1,97 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// copyNode is used to test deep copies.
+type copyNode struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]interface{}
+	Next     *copyNode
+	Shared   *int
+	Again    *int
+	mtx      *sync.Mutex
+	internal []int
+}
+
+// TestDeepCopy ensures deep copies share no references with the original
+// while preserving cycles and sharing according to the options.
+func TestDeepCopy(t *testing.T) {
+	shared := 5
+	orig := &copyNode{Name: "a", Tags: []string{"x"},
+		Attrs: map[string]interface{}{"n": []int{1}}, Shared: &shared,
+		Again: &shared, mtx: &sync.Mutex{}, internal: []int{2}}
+	orig.Next = orig
+
+	c := spew.DeepCopy(orig, nil).(*copyNode)
+	if c == orig || c.Next != c || c.Shared != c.Again || c.Shared == &shared {
+		t.Fatalf("cycles and sharing not preserved: %+v", c)
+	}
+	orig.Tags[0] = "y"
+	orig.Attrs["n"].([]int)[0] = 2
+	*orig.Shared = 6
+	if c.Tags[0] != "x" || c.Attrs["n"].([]int)[0] != 1 || *c.Shared != 5 {
+		t.Errorf("copy shares references with the original: %+v", c)
+	}
+	if c.mtx != nil || c.internal != nil {
+		t.Errorf("unexported fields copied by default: %+v", c)
+	}
+
+	// Cycles are cut and shared values copied separately when requested.
+	c = spew.DeepCopy(orig, &spew.CopyOptions{BreakCycles: true}).(*copyNode)
+	if c.Next != nil || c.Shared == c.Again || *c.Again != 6 {
+		t.Errorf("cycles not broken: %+v", c)
+	}
+
+	// Unexported fields are copied, or replaced by the hook, when requested
+	// and the unsafe package is available.
+	opts := &spew.CopyOptions{CopyUnexported: true,
+		UnexportedFunc: func(path string, v reflect.Value) (reflect.Value, bool) {
+			if path == "mtx" {
+				return reflect.ValueOf(&sync.Mutex{}), true
+			}
+			return reflect.Value{}, false
+		}}
+	c = spew.DeepCopy(orig, opts).(*copyNode)
+	if spew.UnsafeDisabled {
+		if c.mtx != nil || c.internal != nil {
+			t.Errorf("unexported fields copied without unsafe: %+v", c)
+		}
+		return
+	}
+	if c.mtx == nil || c.mtx == orig.mtx {
+		t.Errorf("mtx not replaced by hook: %+v", c)
+	}
+	if !reflect.DeepEqual(c.internal, []int{2}) || &c.internal[0] == &orig.internal[0] {
+		t.Errorf("internal not copied: %+v", c)
+	}
+
+	if got := spew.DeepCopy(nil, nil); got != nil {
+		t.Errorf("nil: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import "sync/atomic"
+
+// disabled indicates whether or not output has been disabled by Disable.  It
+// is accessed atomically.
+var disabled int32
+
+// Disable turns the output functions of this package into cheap no-ops for
+// every configuration until Enable is called.  This is checked before any
+// reflection is performed, so operators are able to silence a storm of dumps
+// from a misbehaving service at runtime, such as from an admin endpoint.  It
+// is safe for concurrent use.
+//
+// The Dump family of functions and the custom formatter produce no output,
+// the Print family of functions write nothing and return zero, the Sprint
+// family of functions return an empty string, and Errorf is equivalent to
+// fmt.Errorf.  The same applies to the other functions which output values,
+// such as DumpJSONLines, DumpMethods, DumpPanic, DumpRepro, Watch, captures,
+// and Baseline.DumpChanges, while Attributes returns no attributes and the
+// values returned by Value are logged as empty groups.
+func Disable() {
+	atomic.StoreInt32(&disabled, 1)
+}
+
+// Enable reverses the effect of Disable.  It is safe for concurrent use.
+func Enable() {
+	atomic.StoreInt32(&disabled, 0)
+}
+
+// Enabled returns whether or not output is enabled, which is the case unless
+// Disable has been called.
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
+
+// outputDisabled returns whether or not output is disabled for the passed
+// configuration, either globally by Disable or by its Disabled option.
+func outputDisabled(cs *ConfigState) bool {
+	return atomic.LoadInt32(&disabled) != 0 || cs.Disabled
+}
@@ -25,12 +25,25 @@ printing facilities for Go data types are as follows:
 	* Circular data structures are detected and handled properly
 	* Custom Stringer/error interfaces are optionally invoked, including
 	  on unexported types
+	* Values stored in interfaces held by unexported struct fields are
+	  unpacked and dumped in full, even without access to the unsafe package
+	* Stringer/error methods which call back into spew with the same value
+	  are displayed as <recursive method call> instead of recursing endlessly
 	* Custom types which only implement the Stringer/error interfaces via
 	  a pointer receiver are optionally invoked when passing non-pointer
 	  variables
 	* Byte arrays and slices are dumped like the hexdump -C command which
 	  includes offsets, byte values in hex, and ASCII output (only when using
 	  Dump style)
+	* Types which implement the SpewRedactor interface are displayed using
+	  the sanitized stand-in they provide
+	* Types which implement the SpewDumper interface take full control of
+	  how their values are rendered (only when using Dump style)
+	* Types which implement the SpewFielder interface contribute derived
+	  values which are displayed alongside their fields (only when using
+	  Dump style)
+	* Weak pointers (weak.Pointer) are displayed by whether or not their
+	  target is still alive followed by the target itself
 
 There are two different approaches spew allows for dumping Go data structures:
 
@@ -63,6 +76,120 @@ printing style, use the convenience wrappers Printf, Fprintf, etc with
 	spew.Fprintf(someWriter, "myVar1: %v -- myVar2: %+v", myVar1, myVar2)
 	spew.Fprintf(someWriter, "myVar3: %#v -- myVar4: %#+v", myVar3, myVar4)
 
+When built with Go 1.18 or later, the generic DumpT, FdumpT, and SdumpT
+functions accept functional options which apply to a single call, including
+typed handlers registered with WithTransform and WithFormatter:
+	spew.DumpT(myVar, spew.WithMaxDepth(2), spew.WithSortKeys())
+
+A reusable Printer may also be built by chaining methods instead of mutating
+ConfigState fields directly:
+	p := spew.NewPrinter().MaxDepth(3).SortKeys().NoAddresses().To(w)
+	p.Dump(myVar1)
+	p.Printf("myVar2: %v", myVar2)
+
+Printers built with CollapseRepeats count consecutive identical dumps from the
+same call site rather than writing them, similar to syslog deduplication:
+	p := spew.NewPrinter().CollapseRepeats()
+
+DumpNamed prefixes the dump of each value with a label, so the dumps of
+several values in one call remain attributable:
+	spew.DumpNamed("request", req, "response", resp)
+
+KV formats alternating keys and values as a single line of key=value pairs,
+which is the logfmt style parsed by many log pipelines:
+	log.Print(spew.KV("user", u, "order", o))
+
+DumpIf and DumpWhen skip all formatting work unless their condition holds, so
+diagnostic dumps guarded by feature flags cost nothing while disabled:
+	spew.DumpIf(flags.Debug, myVar)
+
+V provides leveled dumps which only produce output when the level is at most
+the one set by SetVerbosity, or by the Verbosity method of a Printer:
+	spew.SetVerbosity(2)
+	spew.V(2).Dump(myVar)
+
+Disable turns the output functions into cheap no-ops at runtime until Enable
+is called, which allows operators to silence a storm of dumps:
+	spew.Disable()
+
+Building with "-tags spew_noop" turns the Dump, Print, and Sprint families of
+functions into no-ops, so debug dumps left in the code are compiled out of
+release builds along with the formatting code only they reach.
+
+When built with Go 1.21 or later, Value converts a value into nested slog
+groups so structured log backends are able to index its fields:
+	slog.Info("request received", "req", spew.Value(req))
+
+Attributes flattens a value into key/value pairs keyed by dotted paths, with
+size limits, so debug dumps may be attached to traces as OpenTelemetry span
+event attributes:
+	attrs := spew.Attributes(req, spew.AttributeLimits{MaxAttributes: 128})
+
+RecoverDump may be deferred to dump the panic value, local context values,
+and the stack when recovering from a panic:
+	defer spew.RecoverDump(os.Stderr, req, state)
+
+DumpMethods lists the method set of a value, flagging pointer receiver
+methods, to help debug interface satisfaction:
+	spew.DumpMethods(myVar)
+
+FdumpJSONLines writes one JSON object per visited value, with its path, type,
+kind, value, and depth, so tools are able to filter enormous dumps:
+	err := spew.FdumpJSONLines(w, myVar)
+
+FuncMap provides sdump, sdumpCompact, sprint, and sprintf functions for
+text/template and html/template templates:
+	tmpl := template.New("report").Funcs(spew.FuncMap())
+
+DumpAsync streams a dump through a reader as it is produced, which avoids
+buffering huge dumps in memory:
+	io.Copy(w, spew.DumpAsync(myVar))
+
+DumpRepro displays only the non-zero values within a value along with their
+paths, which is a compact reproduction suitable for bug reports:
+	spew.DumpRepro(myVar)
+
+NewBaseline takes a snapshot of a value so only the values within it which
+changed since, along with their paths and old and new values, are displayed
+later:
+	baseline := spew.NewBaseline(myVar)
+	baseline.DumpChanges(myVar)
+
+Watch periodically calls a function and writes the changes to the value it
+returns, which is useful for observing slow state machines in long tests:
+	go spew.Watch(ctx, time.Second, func() interface{} { return myVar }, os.Stderr)
+
+DeepHash returns a stable hash of a value without rendering it, which is
+useful for deduplicating dumps, caching, and checking whether a value changed:
+	hash := spew.DeepHash(myVar, &spew.HashOptions{IgnoreAddresses: true})
+
+DeepCopy returns a copy of a value which shares no references with it, which
+is useful for snapshotting values before dumping them asynchronously:
+	r := spew.DumpAsync(spew.DeepCopy(myVar, nil))
+
+Canonical returns a compact, deterministic, single-line form of a value which
+does not depend on any configuration and is suitable for cache and
+deduplication keys:
+	key := spew.Canonical(myVar)
+
+Capture records the values walked by Dump so they may be rendered later, or
+encoded and rendered by another process, which keeps formatting off of hot
+paths:
+	capture := spew.Capture(myVar)
+	capture.Fdump(os.Stderr)
+
+NewRemoteSink returns a writer which ships dumps to a remote collector over a
+TCP or Unix socket, dropping them rather than blocking when it falls behind:
+	sink := spew.NewRemoteSink("tcp", "collector:9000", nil)
+	sink.Dump(myVar)
+
+NewDumpRing returns a writer which keeps the most recent dumps in memory so
+they are able to be attached to an error report once an error occurs:
+	ring := spew.NewDumpRing(16, 1<<20)
+	ring.Dump(myVar)
+	...
+	report.History = ring.Dumps()
+
 Configuration Options
 
 Configuration of spew is handled by fields in the ConfigState type.  For
@@ -73,11 +200,38 @@ It is also possible to create a ConfigState instance that provides methods
 equivalent to the top-level functions.  This allows concurrent configuration
 options.  See the ConfigState documentation for more details.
 
+Named profiles standardize the appearance of dumps with a single identifier.
+The built-in ProfileCompact, ProfileTest, ProfileVerbose, and
+ProfileProduction profiles may be selected, and custom profiles registered
+with RegisterProfile:
+	spew.Config.UseProfile(spew.ProfileProduction)
+
+Named integer constants without a Stringer, such as those from third-party
+packages, may be displayed symbolically, such as StateClosed(3), by
+registering the names of their values with RegisterEnum:
+	spew.RegisterEnum(reflect.TypeOf(StateOpen),
+		spew.EnumNames(0, "StateOpen", "StateClosing", "StateClosed"))
+
+Similarly, bitmask types may be displayed as the OR of the names of their
+flags, such as O_RDWR|O_CREATE (0x42), by registering the names of their bits
+with RegisterFlags:
+	spew.RegisterFlags(reflect.TypeOf(Mode(0)),
+		map[uint64]string{0x2: "O_RDWR", 0x40: "O_CREATE"})
+
+Applications which want to prevent libraries from changing the global
+configuration may lock it once initialized with LockConfig, which causes
+subsequent modifications to panic with ErrConfigMutated, or LockConfigFunc,
+which ignores them and reports them to a callback instead.
+
 The following configuration options are available:
 	* Indent
 		String to use for each indentation level for Dump functions.
 		It is a single space by default.  A popular alternative is "\t".
 
+	* Disabled
+		Turns the output functions into cheap no-ops for the configuration, the
+		same as Disable does globally.  Output is enabled by default.
+
 	* MaxDepth
 		Maximum number of levels to descend into nested data structures.
 		There is no limit by default.
@@ -100,6 +254,45 @@ The following configuration options are available:
 		capacities for arrays, slices, maps and channels. This is useful when
 		diffing data structures in tests.
 
+	* ExplicitNilCollections
+		Displays nil maps and slices in Go syntax, such as (map[string]int)(nil),
+		and empty ones with braces on a single line, such as (map[string]int) {},
+		so the two are easy to tell apart.  nil collections are displayed as <nil>
+		by default.
+
+	* ShowLengths
+		Always displays the lengths of maps and strings when dumping, including
+		when they are zero.  Lengths are only displayed when non-zero by default.
+
+	* SchemaOnly
+		Displays only the shape of values when dumping, which is their types,
+		field names, and lengths, replacing scalar values with "<elided>" and
+		omitting pointer addresses and the results of error and Stringer
+		interfaces.  Values are displayed in full by default.
+
+	* Anonymize
+		Replaces scalar values with placeholders of the same type when dumping,
+		such as "str#1", 0, and true, while keeping types, lengths, and structure
+		intact.  Equal strings share a placeholder.  Values are displayed in full
+		by default.
+
+	* MethodPreference
+		Selects which method is invoked for types which implement both the
+		error and Stringer interfaces: PreferError (the default),
+		PreferString, or PreferBoth to display both results.
+
+	* EscapeMethodOutput
+		Escapes non-printable characters, such as ANSI escape sequences and
+		newlines, in the output of error and Stringer interfaces so they
+		can't corrupt terminal output or spoof log lines.
+
+	* InaccessibleValues
+		How values are displayed when their error or Stringer interface can't be
+		invoked because the unsafe package is unavailable, such as when built with
+		the safe tag.  InaccessiblePlaceholder replaces them with "(unexported T)"
+		and InaccessibleVerbose displays the placeholder before their internals.
+		Their internals are displayed silently by default.
+
 	* ContinueOnMethod
 		Enables recursion into types after invoking error and Stringer interface
 		methods. Recursion after method invocation is disabled by default.
@@ -118,6 +311,309 @@ The following configuration options are available:
 		spewed to strings and sorted by those strings.  This is only
 		considered if SortKeys is true.
 
+	* SampleMaps
+		Number of randomly selected entries of larger maps to display when
+		dumping, followed by a marker with the total number of entries.  All of
+		the entries of maps are displayed by default.
+
+	* SampleSeed
+		Seed used to select the entries of maps sampled due to SampleMaps.  When
+		non-zero, the same entries are selected every time.  Different entries
+		are selected each time by default.
+
+	* AlignMapValues
+		Aligns the values of maps in a column after their keys when dumping.
+		Maps with keys which span multiple lines are displayed as usual.
+		Values are not aligned by default.
+
+	* MaxElements
+		Maximum number of leading elements of arrays, slices, and maps to
+		display.  There is no limit by default.
+
+	* TailElements
+		Number of trailing elements to display in addition to the leading
+		elements when a collection is truncated due to MaxElements.
+
+	* ShowUnderlyingTypes
+		Displays the underlying kind of named boolean, numeric, and string
+		types along with the type name, such as "(main.Flag=uint8)".
+		Only the type name is displayed by default.
+
+	* ShowInterfaceTypes
+		Displays the static type of values stored in non-empty interfaces
+		along with their concrete type, such as "(io.Reader ⇒ *bytes.Buffer)".
+		Only the concrete type is displayed by default.
+
+	* ElideElementTypes
+		Omits the type of each element of arrays and slices when all of them have
+		the same type, displaying the type of the elements of collections of
+		interfaces once in the header instead, such as "elem=int".  The type of
+		each element is displayed by default.
+
+	* ShowLayout
+		Displays the byte offset and size of struct fields along with the
+		total size and padding of structs when dumping.  Layout information
+		is not displayed by default.
+
+	* ShowTags
+		Displays the tag of each struct field next to the field name when
+		dumping.  Tags are not displayed by default.
+
+	* TagKeys
+		Limits the struct tag keys displayed when ShowTags is set, such as
+		"json" and "db".  The entire tag is displayed by default.
+
+	* FlattenEmbedded
+		Displays the fields of embedded structs at the level of the struct
+		which embeds them when dumping, as Go's field promotion does.
+		Embedded structs are nested by default.
+
+	* SortFields
+		Displays the fields of structs sorted alphabetically by name when
+		dumping.  Fields are displayed in declaration order by default.
+
+	* FieldLess
+		Function which orders the fields of structs when dumping, taking
+		precedence over SortFields.  It is nil by default.
+
+	* AlignFields
+		Pads the names of struct fields when dumping so the values of the
+		fields of a struct start in the same column.  Values are not aligned
+		by default.
+
+	* ShowTypeDetails
+		Dumps the method set of reflect.Type values and, for struct types, a
+		summary of their fields.  reflect.Type values are always displayed by
+		their name and kind.
+
+	* DisableContainerTraversal
+		Disables displaying the elements of container/list List and
+		container/ring Ring values in order.  Their internal structure of
+		linked elements is displayed instead.
+
+	* MaxIteratorElements
+		Maximum number of elements to drain from iterator functions, such as
+		iter.Seq and iter.Seq2, in order to display them.  Iterators are not
+		drained by default since doing so calls them.
+
+	* ShowIndices
+		Prefixes each array and slice element with its index when dumping.
+		Indices are not shown by default.
+
+	* RedactFunc
+		Callback invoked with the path, type, and value of every leaf
+		value which may return a replacement string to print in its
+		place.  This allows sensitive data to be masked centrally.
+		The paths of map keys use braces in place of brackets.
+		There is no redaction by default.
+
+	* TypeFormatters
+		Map of types to functions which produce the inline representation of
+		values of that type for the custom formatter.  This allows the output
+		of third-party types to be customized without wrapping them.
+
+	* SkipTypes
+		Map of types whose values are replaced with a placeholder, such as
+		"(context.Context) <skipped>", wherever they appear when dumping or
+		formatting.  No types are skipped by default.
+
+	* IncludeTypes
+		Map of the only types whose scalar values are displayed when dumping or
+		formatting, with the values of other scalar types replaced with the same
+		placeholder as SkipTypes.  All types are included by default.
+
+	* SnapshotFuncs
+		Accessor callbacks for specific types, such as the nodes of lock-free
+		data structures, which return a consistent snapshot of values of the
+		type to display in their place.  The types of the sync/atomic package
+		are always displayed using their Load method.
+
+	* Theme
+		Styles used to display the elements of dumps with ANSI escape sequences
+		for Dump functions, such as ThemeDark, ThemeLight, or ThemeMonochromeBold.
+		SupportsColor reports whether a writer is a terminal which supports them.
+		Output is not styled by default.
+
+	* ShowSpareCapacity
+		Also display the elements of slices between their length and capacity
+		after a marker such as "<spare capacity [2:4]>" for Dump functions.
+		Spare capacity is not displayed by default.
+
+	* ShowSliceAliases
+		Annotate slices which share a backing array with a slice dumped earlier
+		by the same call, such as "(aliases arg0.Items[4:10])".  Slices are not
+		annotated by default.
+
+	* ShowTypeIndex
+		Appends an index of every distinct type encountered by the same call
+		to the Dump functions along with the number of values and bytes
+		rendered for each.  It is not shown by default.
+
+	* ExplainTruncation
+		Include the name and value of the limit which cut off output in the
+		truncation markers, such as "<max depth reached, MaxDepth=3>".  Markers
+		do not name the limit by default.
+
+	* FormatVersion
+		Pins the version of the text format of Dump functions so future releases
+		do not change the output for golden files.  It is LatestFormatVersion by
+		default.
+
+	* ShowFormatVersion
+		Writes a header line with the format version, such as
+		"spew-format-version: 1", before the output of Dump functions.  The header
+		is not written by default.
+
+	* FrameDumps
+		Surrounds the output of each call to Dump functions with marker lines
+		carrying a unique identifier, such as "--- SPEW BEGIN id=1f2e3d4c ---" and
+		"--- SPEW END id=1f2e3d4c (lines=87) ---", so complete dumps can be
+		extracted from interleaved logs.  Dumps are not framed by default.
+
+	* LineNumbers
+		Prefixes each line of output of Dump functions with its line number, such
+		as "  42 | ", counting from 1 for each call.  Lines are not numbered by
+		default.
+
+	* ParallelDump
+		Enables formatting multiple arguments to the Dump family of functions
+		concurrently before writing them in order.  Every method and callback
+		invoked while formatting, such as NodeFunc and ProgressFunc, may then be
+		called from several goroutines at once and must be safe for concurrent
+		use.  Arguments are formatted sequentially by default.
+
+	* MaxNodes
+		Maximum number of values to visit while dumping each argument, after which
+		the traversal stops with the marker "<max nodes reached>".  There is no
+		limit by default.
+
+	* ProgressFunc
+		Callback invoked periodically while dumping with the number of values
+		visited, bytes written, and time elapsed.  Returning false aborts the
+		dump.  Progress is not reported by default.
+
+	* ProgressInterval
+		Number of values visited between invocations of ProgressFunc.  It is
+		10000 by default.
+
+	* NodeFunc
+		Callback invoked for every value rendered by Dump functions with its
+		path, type, depth, and the offsets of its text within the output.  It is
+		not invoked by default.
+
+	* Metrics
+		Recorder passed the call site, bytes written, duration, and number of
+		truncations of each call of the Dump functions, which may be bridged to
+		Prometheus or OpenTelemetry metrics.  Metrics are not recorded by default.
+
+	* CallerSkip
+		Number of additional stack frames to skip when reporting call sites, for
+		packages which wrap spew in their own helpers.  The innermost caller
+		outside of spew is reported by default.
+
+	* Scrubbers
+		Regular expressions and replacements applied to the complete output for
+		each value, such as ScrubAddresses, ScrubUUIDs, and ScrubTimestamps.
+		Output is not scrubbed by default.
+
+	* OutputFunc
+		Callback invoked with the complete output for each value which returns
+		the string to write in its place.  This allows transformations such as
+		scrubbing or log framing.  Output is written unmodified by default.
+
+	* TransformFunc
+		Callback invoked for every value which may return a replacement to
+		display in its place.  This allows internal representations to be
+		converted to display forms.  Values are displayed as-is by default.
+
+	* MaxStringLength
+		Maximum number of bytes of strings to display.  Truncated strings are
+		annotated with their full length.  There is no limit by default.
+
+	* WrapStrings
+		Maximum number of bytes of strings to display on each line when dumping.
+		Longer strings are split into quoted pieces joined by " +" with each
+		continuation on its own indented line.  Strings are not wrapped by default.
+
+	* RawStrings
+		Display multi-line strings which contain no backticks or control characters
+		other than tabs as raw string literals across real, indented lines when
+		dumping.  Strings are always displayed quoted by default.
+
+	* SniffBytes
+		Examines the contents of byte arrays and slices when dumping to display
+		UTF-8 text and mostly printable data as quoted strings and anything else
+		as a hexdump, annotated with the presentation chosen and why.  Bytes are
+		always hexdumped by default.
+
+	* PrettyJSONThreshold
+		Minimum length of strings which are checked for holding a JSON object
+		or array when dumping.  Those which do are displayed indented and
+		marked with "(json)".  It is disabled by default.
+
+	* ShowRunes
+		Displays strings which contain non-ASCII characters with the code
+		points of those characters.  Strings are shown quoted by default.
+
+	* IntegerBase
+		Base integers are displayed in, which is 16, 8, or 2 for hexadecimal,
+		octal, or binary with a "0x", "0o", or "0b" prefix.  Integers are
+		displayed in decimal by default.  The base may be overridden for struct
+		fields with a `spew:"hex"`, `spew:"oct"`, `spew:"bin"`, or `spew:"dec"` tag.
+
+	* TypeIntegerBases
+		Map of types to the base their integers are displayed in, which overrides
+		IntegerBase.  It is empty by default.
+
+	* PointerTypes
+		Map of uintptr types whose values hold addresses, which are displayed in
+		pointer notation such as (Handle)(0xc000012345).  Struct fields are
+		displayed this way with a `spew:"ptr"` tag.  It is empty by default.
+
+	* PointerResolver
+		Callback which looks up the value uintptr values displayed in pointer
+		notation point to so it is dumped after the address.  Addresses are not
+		followed by default.
+
+	* FloatFormat
+		Format floats are displayed in, which is one of the formats of
+		strconv.FormatFloat such as 'f' or 'e'.  It is 'g' by default.
+
+	* FloatPrecision
+		Number of digits floats are displayed with.  The smallest number of digits
+		necessary to represent the value exactly is used by default.
+
+	* MarkNonFinite
+		Encloses floats which are NaN or infinite in angle brackets, such as <NaN>,
+		so they stand out, and counts them in numeric summaries.  They are displayed
+		like other floats by default.
+
+	* ShowCharacters
+		Specifies that byte and rune values which plausibly hold a character
+		are displayed as both their number and the quoted character, such as
+		(uint8) 65 'A'.  Bytes are only shown as characters when they are
+		printable ASCII.  Characters are not shown by default.
+
+	* WarnTypedNil
+		Annotates nil pointers held by interfaces, including the arguments
+		passed to the output functions, with a warning since the interface
+		holding them is not nil.  They are not annotated by default.
+
+	* DigitSeparator
+		Specifies a separator, such as "," or "_", to insert between each
+		group of three digits of decimal integers, lengths, and capacities.
+		Digits are not grouped by default.
+
+	* SummarizeNumbers
+		Threshold above which numeric arrays and slices are displayed as a
+		summary of their minimum, maximum, and mean values along with a few
+		samples when dumping.  Numeric collections are shown in full by default.
+
+	* PackWidth
+		Maximum width, including the indentation, within which the elements of
+		numeric and boolean arrays and slices are displayed together on a single
+		line when dumping.  Elements are displayed one per line by default.
+
 Dump Usage
 
 Simply call spew.Dump with a list of variables you want to dump:
@@ -18,14 +18,22 @@ package spew
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -47,16 +55,301 @@ var (
 	cUint8tCharRE = regexp.MustCompile(`^.*\._Ctype_uint8_t$`)
 )
 
+// SpewDumper is an interface that may be implemented by types which need full
+// control over how their value is rendered by Dump and friends.  It is invoked
+// in place of the normal value output once the type information has been
+// written, with w being the destination of the dump, depth being the current
+// nesting level, and cfg being the active configuration.  Implementations
+// should indent any lines after the first with depth repetitions of
+// cfg.Indent so the output lines up with the surrounding dump.
+//
+// Nested values should be written with the passed dump function rather than
+// by calling cfg.Fdump, so they are dumped one level deeper as part of the
+// dump in progress.  This means they honor cfg.MaxDepth and are included in
+// its circular reference detection.  Like other nested values, their output
+// begins with the type information and is not followed by a newline.
+//
+// Values are only passed to DumpSpew once pointer indirection and circular
+// reference detection has been handled, and it is not called for values that
+// are beyond cfg.MaxDepth.  As with the error and Stringer interfaces, it is
+// not invoked when cfg.DisableMethods is set.
+type SpewDumper interface {
+	DumpSpew(w io.Writer, depth int, cfg *ConfigState, dump func(interface{}))
+}
+
+// SpewFielder is an interface that may be implemented by struct types to
+// contribute additional values to their dump, such as lazily computed state or
+// decoded views of raw fields.  The values returned by SpewFields are dumped
+// after the real fields, in order of their names, with each name followed by
+// "(derived)" to distinguish them.  As with the error and Stringer interfaces,
+// it is not invoked when DisableMethods is set.
+type SpewFielder interface {
+	SpewFields() map[string]interface{}
+}
+
 // dumpState contains information about the state of a dump operation.
 type dumpState struct {
 	w                io.Writer
 	depth            int
-	pointers         map[uintptr]int
+	pointers         map[visitKey]int
 	ignoreNextType   bool
 	ignoreNextIndent bool
+	path             string
+	staticType       reflect.Type
+	wrappedNil       bool
+	transforming     map[reflect.Type]bool
+	snapshotted      reflect.Type
+	nodes            int
+	start            time.Time
+	counter          *countingWriter
+	truncations      *int64
+	anonymized       map[string]int
+	types            *typeIndex
+	activeTypes      map[reflect.Type]bool
+	fieldBase        int
+	fieldPointer     bool
+	resolving        map[uintptr]bool
+	aliases          *sliceAliases
+	theme            Theme
 	cs               *ConfigState
 }
 
+// errDumpAborted is the panic value used to unwind a dump which was aborted
+// by the ProgressFunc callback.
+var errDumpAborted = errors.New("spew: dump aborted")
+
+// errMaxNodes is the panic value used to unwind a dump which visited more
+// values than the MaxNodes option allows.
+var errMaxNodes = errors.New("spew: max nodes reached")
+
+// countingWriter is an io.Writer which counts the bytes and lines written to
+// the underlying writer and tracks whether or not the last byte was a newline.
+type countingWriter struct {
+	w         io.Writer
+	n         int64
+	lines     int64
+	atNewline bool
+}
+
+// Write writes p to the underlying writer and counts the bytes and lines
+// written.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if n > 0 {
+		c.lines += int64(bytes.Count(p[:n], newlineBytes))
+		c.atNewline = p[n-1] == '\n'
+	}
+	return n, err
+}
+
+// frameIDCounter is used to generate frame identifiers when random ones are
+// not available.
+var frameIDCounter uint32
+
+// newFrameID returns an identifier for the markers around a dump which is
+// very likely to be unique, even among separate processes.
+func newFrameID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		binary.BigEndian.PutUint32(b[:], atomic.AddUint32(&frameIDCounter, 1))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// frameWriter is an io.Writer which writes output between marker lines
+// carrying an identifier unique to the frame along with the number of lines
+// written.
+type frameWriter struct {
+	countingWriter
+	id []byte
+}
+
+// beginFrame writes the marker line which begins a new frame to io.Writer w
+// and returns a frameWriter which writes to it within the frame.
+func beginFrame(w io.Writer) *frameWriter {
+	f := &frameWriter{countingWriter: countingWriter{w: w, atNewline: true},
+		id: []byte(newFrameID())}
+	w.Write(frameBeginBytes)
+	w.Write(f.id)
+	w.Write(frameCloseBytes)
+	return f
+}
+
+// end writes the marker line which ends the frame, after terminating the last
+// line written within it when necessary.
+func (f *frameWriter) end() {
+	if !f.atNewline {
+		f.Write(newlineBytes)
+	}
+	f.w.Write(frameEndBytes)
+	f.w.Write(f.id)
+	f.w.Write(frameLinesBytes)
+	printInt(f.w, f.lines, 10)
+	f.w.Write(closeParenBytes)
+	f.w.Write(frameCloseBytes)
+}
+
+// lineNumberWriter is an io.Writer which prefixes each line written to the
+// underlying writer with its line number.
+type lineNumberWriter struct {
+	w       io.Writer
+	line    int
+	midLine bool
+}
+
+// Write writes p to the underlying writer, prefixing the start of each line
+// with its number.
+func (l *lineNumberWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		if !l.midLine {
+			l.line++
+			fmt.Fprintf(l.w, "%4d", l.line)
+			if _, err := l.w.Write(lineNumberSepBytes); err != nil {
+				return written, err
+			}
+			l.midLine = true
+		}
+		end := len(p)
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			end = i + 1
+			l.midLine = false
+		}
+		n, err := l.w.Write(p[:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[end:]
+	}
+	return written, nil
+}
+
+// dumpedSlice describes the backing array of a slice which has been dumped
+// along with a label identifying it.
+type dumpedSlice struct {
+	label      string
+	start, end uintptr
+	elemType   reflect.Type
+}
+
+// sliceAliases tracks the slices which have been dumped in order to detect
+// slices which share backing arrays.
+type sliceAliases struct {
+	arg    int
+	slices []dumpedSlice
+}
+
+// annotate outputs a note when the passed slice, located at path within the
+// current argument, shares its backing array with a slice which was dumped
+// previously and then records it.  Slices which start within a previous one
+// are described by the equivalent slice expression, such as
+// "(aliases arg0.Items[2:4])".
+func (a *sliceAliases) annotate(w io.Writer, path string, v reflect.Value) {
+	elemType := v.Type().Elem()
+	size := elemType.Size()
+	if v.Cap() == 0 || size == 0 {
+		return
+	}
+	start := v.Pointer()
+	end := start + uintptr(v.Cap())*size
+
+	for _, s := range a.slices {
+		if s.elemType != elemType {
+			continue
+		}
+		switch {
+		case start >= s.start && start < s.end:
+			i := int((start - s.start) / size)
+			fmt.Fprintf(w, "(aliases %s[%d:%d]) ", s.label, i, i+v.Len())
+		case s.start >= start && s.start < end:
+			fmt.Fprintf(w, "(aliased by %s) ", s.label)
+		default:
+			continue
+		}
+		break
+	}
+
+	label := "arg" + strconv.Itoa(a.arg)
+	if path != "" && path[0] != '[' {
+		label += "."
+	}
+	a.slices = append(a.slices, dumpedSlice{label + path, start, end, elemType})
+}
+
+// tracksPaths returns whether or not the path of the current value is tracked,
+// which is only done when an option requires it.
+func (d *dumpState) tracksPaths() bool {
+	return d.cs.RedactFunc != nil || d.aliases != nil || d.cs.NodeFunc != nil
+}
+
+// reportProgress counts the value being visited and invokes the ProgressFunc
+// callback, if any, once every ProgressInterval values.  The dump is unwound
+// with errMaxNodes when more values than the MaxNodes option allows have been
+// visited and with errDumpAborted when the callback returns false.
+func (d *dumpState) reportProgress() {
+	d.nodes++
+	if d.cs.MaxNodes > 0 && d.nodes > d.cs.MaxNodes {
+		panic(errMaxNodes)
+	}
+	if d.cs.ProgressFunc == nil {
+		return
+	}
+	interval := d.cs.ProgressInterval
+	if interval <= 0 {
+		interval = 10000
+	}
+	if d.nodes%interval != 0 {
+		return
+	}
+
+	p := Progress{
+		Nodes:   d.nodes,
+		Bytes:   d.counter.n,
+		Elapsed: time.Since(d.start),
+	}
+	if !d.cs.ProgressFunc(p) {
+		panic(errDumpAborted)
+	}
+}
+
+// truncated records that a value was truncated for the Metrics option.
+func (d *dumpState) truncated() {
+	if d.truncations != nil {
+		atomic.AddInt64(d.truncations, 1)
+	}
+}
+
+// dumpRawString writes the passed string between backticks with each line
+// after the first indented one level deeper than the current depth.
+func (d *dumpState) dumpRawString(s string) {
+	indent := bytes.Repeat([]byte(d.cs.Indent), d.depth+1)
+	d.w.Write(backtickBytes)
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			d.w.Write(newlineBytes)
+			d.w.Write(indent)
+		}
+		d.w.Write([]byte(line))
+	}
+	d.w.Write(backtickBytes)
+}
+
+// dumpWrappedString writes the passed string as quoted pieces of at most
+// WrapStrings bytes joined by " +" with each continuation on its own line
+// indented one level deeper than the current depth.
+func (d *dumpState) dumpWrappedString(s string) {
+	indent := bytes.Repeat([]byte(d.cs.Indent), d.depth+1)
+	for i, piece := range splitString(s, d.cs.WrapStrings) {
+		if i > 0 {
+			d.w.Write(stringContinuationBytes)
+			d.w.Write(indent)
+		}
+		d.w.Write([]byte(strconv.Quote(piece)))
+	}
+}
+
 // indent performs indentation according to the depth level and cs.Indent
 // option.
 func (d *dumpState) indent() {
@@ -71,14 +364,79 @@ func (d *dumpState) indent() {
 // This is useful for data types like structs, arrays, slices, and maps which
 // can contain varying types packed inside an interface.
 func (d *dumpState) unpackValue(v reflect.Value) reflect.Value {
-	if v.Kind() == reflect.Interface && !v.IsNil() {
+	if v.Kind() == reflect.Interface && !v.IsNil() && !d.cs.skipsType(v.Type()) {
+		if d.cs.ShowInterfaceTypes && v.Type().NumMethod() > 0 {
+			d.staticType = v.Type()
+		}
 		v = v.Elem()
+		d.wrappedNil = d.cs.WarnTypedNil && isNilPointer(v)
 	}
 	return v
 }
 
+// handleDumper invokes the DumpSpew method on the type the passed
+// reflect.Value represents if it implements the SpewDumper interface and
+// returns whether or not it did.  Panics in DumpSpew are caught and displayed
+// the same way as panics in error and Stringer interfaces.
+func (d *dumpState) handleDumper(v reflect.Value) (handled bool) {
+	v, ok := interfaceValue(d.cs, v)
+	if !ok {
+		return false
+	}
+	dumper, ok := v.Interface().(SpewDumper)
+	if !ok {
+		return false
+	}
+
+	if (d.cs.MaxDepth != 0) && (d.depth+1 > d.cs.MaxDepth) {
+		d.truncated()
+		printMaxDepth(d.w, d.cs, maxShortBytes)
+		return true
+	}
+
+	defer catchPanic(d.w, v)
+	dumper.DumpSpew(d.w, d.depth, d.cs, d.dumpChild)
+	return true
+}
+
+// dumpChild dumps the passed value one level deeper than the value currently
+// being dumped.  It is handed to SpewDumper implementations so the values they
+// nest share the depth and circular reference detection of the dump in
+// progress.
+func (d *dumpState) dumpChild(i interface{}) {
+	if i == nil {
+		writeStyled(d.w, d.theme.TypeName, interfaceBytes)
+		d.w.Write(spaceBytes)
+		writeStyled(d.w, d.theme.Nil, nilAngleBytes)
+		return
+	}
+
+	d.depth++
+	d.ignoreNextIndent = true
+	d.dump(reflect.ValueOf(i))
+	d.depth--
+}
+
+// dumpOmitted outputs a line indicating the number of elements of a collection
+// which were omitted followed by the appropriate separator depending on
+// whether or not there are any trailing elements to follow.  It returns
+// whether or not there are.
+func (d *dumpState) dumpOmitted(omitted, tail int) bool {
+	d.indent()
+	d.truncated()
+	printOmitted(d.w, d.cs, omitted)
+	if tail == 0 {
+		d.w.Write(newlineBytes)
+		return false
+	}
+	d.w.Write(commaNewlineBytes)
+	return true
+}
+
 // dumpPtr handles formatting of pointers by indirecting them as necessary.
-func (d *dumpState) dumpPtr(v reflect.Value) {
+// The passed static type is the type of the interface the pointer was stored
+// in, if any.
+func (d *dumpState) dumpPtr(v reflect.Value, staticType reflect.Type, wrappedNil bool) {
 	// Remove pointers at or below the current depth from map used to detect
 	// circular refs.
 	for k, depth := range d.pointers {
@@ -105,12 +463,13 @@ func (d *dumpState) dumpPtr(v reflect.Value) {
 		indirects++
 		addr := ve.Pointer()
 		pointerChain = append(pointerChain, addr)
-		if pd, ok := d.pointers[addr]; ok && pd < d.depth {
+		key := newVisitKey(ve)
+		if pd, ok := d.pointers[key]; ok && pd < d.depth {
 			cycleFound = true
 			indirects--
 			break
 		}
-		d.pointers[addr] = d.depth
+		d.pointers[key] = d.depth
 
 		ve = ve.Elem()
 		if ve.Kind() == reflect.Interface {
@@ -122,20 +481,27 @@ func (d *dumpState) dumpPtr(v reflect.Value) {
 		}
 	}
 
-	// Display type information.
-	d.w.Write(openParenBytes)
-	d.w.Write(bytes.Repeat(asteriskBytes, indirects))
-	d.w.Write([]byte(ve.Type().String()))
-	d.w.Write(closeParenBytes)
+	// Display type information unless already handled elsewhere.
+	if !d.ignoreNextType {
+		beginStyle(d.w, d.theme.TypeName)
+		d.w.Write(openParenBytes)
+		printStaticType(d.w, staticType)
+		d.w.Write(bytes.Repeat(asteriskBytes, indirects))
+		d.w.Write([]byte(typeString(d.cs, ve.Type())))
+		d.w.Write(closeParenBytes)
+		endStyle(d.w, d.theme.TypeName)
+	}
 
 	// Display pointer information.
-	if !d.cs.DisablePointerAddresses && len(pointerChain) > 0 {
+	if !d.cs.DisablePointerAddresses && !d.cs.SchemaOnly && len(pointerChain) > 0 {
 		d.w.Write(openParenBytes)
 		for i, addr := range pointerChain {
 			if i > 0 {
 				d.w.Write(pointerChainBytes)
 			}
+			beginStyle(d.w, d.theme.Address)
 			printHexPtr(d.w, addr)
+			endStyle(d.w, d.theme.Address)
 		}
 		d.w.Write(closeParenBytes)
 	}
@@ -144,16 +510,109 @@ func (d *dumpState) dumpPtr(v reflect.Value) {
 	d.w.Write(openParenBytes)
 	switch {
 	case nilFound:
-		d.w.Write(nilAngleBytes)
+		writeStyled(d.w, d.theme.Nil, nilAngleBytes)
 
 	case cycleFound:
-		d.w.Write(circularBytes)
+		writeStyled(d.w, d.theme.Circular, circularBytes)
 
 	default:
 		d.ignoreNextType = true
 		d.dump(ve)
 	}
 	d.w.Write(closeParenBytes)
+
+	// Warn about nil pointers which make the interface holding them non-nil.
+	if wrappedNil && nilFound && indirects == 0 {
+		d.w.Write(typedNilWarningBytes)
+	}
+}
+
+// dumpUintptrPointer handles formatting of uintptr values which hold
+// addresses.  The value they point to is dumped after the address when the
+// PointerResolver callback is able to look it up.  The passed static type is
+// the type of the interface the value was stored in, if any.
+func (d *dumpState) dumpUintptrPointer(v reflect.Value, staticType reflect.Type) {
+	if !d.ignoreNextType {
+		beginStyle(d.w, d.theme.TypeName)
+		d.w.Write(openParenBytes)
+		printStaticType(d.w, staticType)
+		d.w.Write([]byte(typeString(d.cs, v.Type())))
+		d.w.Write(closeParenBytes)
+		endStyle(d.w, d.theme.TypeName)
+	}
+	d.ignoreNextType = false
+
+	addr := uintptr(v.Uint())
+	d.w.Write(openParenBytes)
+	beginStyle(d.w, d.theme.Address)
+	printHexPtr(d.w, addr)
+	endStyle(d.w, d.theme.Address)
+	d.w.Write(closeParenBytes)
+	if addr == 0 || d.cs.PointerResolver == nil {
+		return
+	}
+
+	target, ok := d.cs.PointerResolver(v.Type(), addr)
+	if !ok || !target.IsValid() {
+		return
+	}
+	d.w.Write(changedArrowBytes)
+	if d.resolving[addr] {
+		writeStyled(d.w, d.theme.Circular, circularBytes)
+		return
+	}
+	if d.resolving == nil {
+		d.resolving = make(map[uintptr]bool)
+	}
+	d.resolving[addr] = true
+	defer delete(d.resolving, addr)
+
+	// The resolved value does not hold addresses just because the uintptr
+	// did.
+	parentPointer := d.fieldPointer
+	d.fieldPointer = false
+	d.ignoreNextIndent = true
+	d.dump(target)
+	d.fieldPointer = parentPointer
+}
+
+// dumpWeakPointer dumps a weak.Pointer of the passed type as whether or not
+// its target has been garbage collected followed by the target while it is
+// still alive.
+func (d *dumpState) dumpWeakPointer(t reflect.Type, target reflect.Value) {
+	if !d.ignoreNextType {
+		d.w.Write(openParenBytes)
+		d.w.Write([]byte(typeString(d.cs, t)))
+		d.w.Write(closeParenBytes)
+		d.w.Write(spaceBytes)
+	}
+	d.ignoreNextType = false
+	if target.IsNil() {
+		d.w.Write(weakCollectedBytes)
+		return
+	}
+
+	d.w.Write(weakLiveBytes)
+	d.w.Write(spaceBytes)
+	d.ignoreNextIndent = true
+	d.dump(target)
+}
+
+// dumpSpareCapacity dumps the elements of the passed slice between its length
+// and capacity after a marker which separates them from the elements of the
+// slice itself.  They are not part of the value, but may hold stale data which
+// is visible to later appends or reslicing.
+func (d *dumpState) dumpSpareCapacity(v reflect.Value) {
+	d.indent()
+	d.w.Write(spareCapacityBytes)
+	d.w.Write(openBracketBytes)
+	printCount(d.w, d.cs, v.Len())
+	d.w.Write(colonBytes)
+	printCount(d.w, d.cs, v.Cap())
+	d.w.Write(closeBracketBytes)
+	d.w.Write(closeAngleBytes)
+	d.w.Write(newlineBytes)
+	d.dumpSlice(v.Slice(v.Len(), v.Cap()))
 }
 
 // dumpSlice handles formatting of arrays and slices.  Byte (uint8 under
@@ -223,7 +682,20 @@ func (d *dumpState) dumpSlice(v reflect.Value) {
 		}
 	}
 
-	// Hexdump the entire slice as needed.
+	// Hexdump the entire slice as needed.  Only the length of the slice is
+	// displayed when just the shape of values is requested.
+	if doHexDump && d.cs.SchemaOnly {
+		d.indent()
+		d.w.Write(elidedAngleBytes)
+		d.w.Write(newlineBytes)
+		return
+	}
+	if doHexDump && d.cs.Anonymize {
+		buf = make([]uint8, len(buf))
+	}
+	if doHexDump && d.cs.SniffBytes && d.dumpSniffedBytes(buf) {
+		return
+	}
 	if doHexDump {
 		indent := strings.Repeat(d.cs.Indent, d.depth)
 		str := indent + hex.Dump(buf)
@@ -233,9 +705,45 @@ func (d *dumpState) dumpSlice(v reflect.Value) {
 		return
 	}
 
-	// Recursively call dump for each item.
+	// Display a summary instead of the individual items for large numeric
+	// collections when requested.
+	if d.summarizes(v) {
+		d.dumpNumericSummary(v)
+		return
+	}
+
+	// Recursively call dump for each item while omitting those in the middle
+	// when there are more than the configured maximum.  The types of the
+	// items are not displayed when they are all the same and requested.
+	_, elideTypes := elidedElemType(d.cs, v)
+	parentPath := d.path
+	head, tail := shownElements(d.cs, numEntries)
+	omitted := numEntries - head - tail
 	for i := 0; i < numEntries; i++ {
+		if omitted > 0 && i == head {
+			if !d.dumpOmitted(omitted, tail) {
+				break
+			}
+			i += omitted
+		}
+		if d.cs.ShowIndices {
+			d.indent()
+			d.w.Write(openBracketBytes)
+			printInt(d.w, int64(i), 10)
+			d.w.Write(closeBracketBytes)
+			d.w.Write(colonSpaceBytes)
+			d.ignoreNextIndent = true
+		}
+		if d.tracksPaths() {
+			d.path = indexPath(parentPath, i)
+		}
+		if elideTypes {
+			d.indent()
+			d.ignoreNextIndent = true
+			d.ignoreNextType = true
+		}
 		d.dump(d.unpackValue(v.Index(i)))
+		d.path = parentPath
 		if i < (numEntries - 1) {
 			d.w.Write(commaNewlineBytes)
 		} else {
@@ -244,11 +752,606 @@ func (d *dumpState) dumpSlice(v reflect.Value) {
 	}
 }
 
-// dump is the main workhorse for dumping a value.  It uses the passed reflect
-// value to figure out what kind of object we are dealing with and formats it
-// appropriately.  It is a recursive function, however circular data structures
-// are detected and handled properly.
+// elidedElemType returns the type shared by all of the elements of the passed
+// array or slice and whether or not the display of their types should be
+// elided due to the ElideElementTypes option.  Elements stored in interfaces
+// must all be non-nil and hold the same type.
+func elidedElemType(cs *ConfigState, v reflect.Value) (reflect.Type, bool) {
+	if !cs.ElideElementTypes || cs.TransformFunc != nil {
+		return nil, false
+	}
+	if k := v.Kind(); (k != reflect.Array && k != reflect.Slice) || v.Len() == 0 {
+		return nil, false
+	}
+	t := v.Type().Elem()
+	if t.Kind() != reflect.Interface {
+		return t, true
+	}
+	if cs.ShowInterfaceTypes && t.NumMethod() > 0 {
+		return nil, false
+	}
+	var common reflect.Type
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.IsNil() || (common != nil && e.Elem().Type() != common) {
+			return nil, false
+		}
+		common = e.Elem().Type()
+	}
+	return common, true
+}
+
+// dumpAnonymized writes a placeholder of the same type for the passed value
+// when it is a string, number, or boolean.  It returns whether or not it did.
+// Equal strings share the same placeholder within the value being dumped.
+func (d *dumpState) dumpAnonymized(v reflect.Value) bool {
+	switch kind := v.Kind(); {
+	case kind == reflect.String:
+		if d.anonymized == nil {
+			d.anonymized = make(map[string]int)
+		}
+		n, ok := d.anonymized[v.String()]
+		if !ok {
+			n = len(d.anonymized) + 1
+			d.anonymized[v.String()] = n
+		}
+		d.w.Write([]byte(strconv.Quote(anonStringPrefix + strconv.Itoa(n))))
+
+	case kind == reflect.Bool:
+		printBool(d.w, true)
+
+	case isNumericKind(kind):
+		d.w.Write(zeroBytes)
+
+	case kind == reflect.Complex64 || kind == reflect.Complex128:
+		printComplex(d.w, d.cs, 0, 64)
+
+	default:
+		return false
+	}
+	return true
+}
+
+// dumpSniffedBytes displays the passed bytes as a quoted string when they
+// are valid UTF-8 text or mostly printable, annotated with the reason.
+// Otherwise, it writes the annotation for a hexdump and returns false so the
+// caller performs it.
+func (d *dumpState) dumpSniffedBytes(buf []byte) bool {
+	utf8Text, unprintable := sniffBytes(buf)
+	d.indent()
+	switch {
+	case utf8Text:
+		d.w.Write([]byte(strconv.Quote(string(buf))))
+		d.w.Write(sniffedUTF8Bytes)
+
+	// Up to a quarter of the bytes are escaped.
+	case unprintable*4 <= len(buf):
+		d.w.Write([]byte(strconv.Quote(string(buf))))
+		d.w.Write(sniffedTextBytes)
+		printCount(d.w, d.cs, unprintable)
+		d.w.Write(ofBytes)
+		printCount(d.w, d.cs, len(buf))
+		d.w.Write(sniffedEscapedBytes)
+
+	default:
+		d.w.Write(sniffedBinaryBytes)
+		printCount(d.w, d.cs, unprintable)
+		d.w.Write(ofBytes)
+		printCount(d.w, d.cs, len(buf))
+		d.w.Write(sniffedUnprintableBytes)
+		d.w.Write(newlineBytes)
+		return false
+	}
+	d.w.Write(newlineBytes)
+	return true
+}
+
+// dumpNilCollection handles formatting of nil maps and slices.  The passed
+// goSyntax flag specifies they are displayed as (nil) after their type rather
+// than as <nil>.
+func (d *dumpState) dumpNilCollection(goSyntax bool) {
+	if goSyntax {
+		writeStyled(d.w, d.theme.Nil, nilParenBytes)
+		return
+	}
+	writeStyled(d.w, d.theme.Nil, nilAngleBytes)
+}
+
+// summarizes returns whether the passed slice or array is displayed as a
+// summary of its elements due to the SummarizeNumbers option.
+func (d *dumpState) summarizes(v reflect.Value) bool {
+	return d.cs.SummarizeNumbers > 0 && v.Len() > d.cs.SummarizeNumbers &&
+		isNumericKind(v.Type().Elem().Kind())
+}
+
+// dumpPacked dumps the elements of the passed slice or array on a single line,
+// such as {1, 2, 3}, when they are numbers or booleans and, along with the
+// indentation, fit within the PackWidth option.  It returns whether or not it
+// did.
+func (d *dumpState) dumpPacked(v reflect.Value) bool {
+	numEntries := v.Len()
+	switch kind := v.Type().Elem().Kind(); {
+	case numEntries == 0:
+		return false
+	case kind == reflect.Uint8:
+		// Byte slices are hex dumped.
+		return false
+	case !isNumericKind(kind) && kind != reflect.Bool &&
+		kind != reflect.Complex64 && kind != reflect.Complex128:
+		return false
+	}
+
+	// Collections which would be truncated or summarized are displayed as
+	// usual, as are the elements of collections which are reported to
+	// callbacks or tracked individually.
+	if head, tail := shownElements(d.cs, numEntries); head+tail < numEntries {
+		return false
+	}
+	if d.cs.MaxDepth != 0 && d.depth+1 > d.cs.MaxDepth {
+		return false
+	}
+	if d.tracksPaths() || d.cs.ProgressFunc != nil || d.cs.MaxNodes > 0 ||
+		d.types != nil {
+
+		return false
+	}
+
+	var buf bytes.Buffer
+	buf.Write(openBraceBytes)
+	ed := dumpState{w: &buf, cs: d.cs, pointers: d.pointers, theme: d.theme,
+		fieldBase: d.fieldBase, truncations: d.truncations}
+	for i := 0; i < numEntries; i++ {
+		if i > 0 {
+			buf.Write(commaSpaceBytes)
+		}
+		ed.ignoreNextType = true
+		ed.dumpValue(v.Index(i))
+		if displayWidth(buf.Bytes())+d.depth*len(d.cs.Indent) > d.cs.PackWidth {
+			return false
+		}
+	}
+	buf.Write(closeBraceBytes)
+	if displayWidth(buf.Bytes())+d.depth*len(d.cs.Indent) > d.cs.PackWidth {
+		return false
+	}
+	d.w.Write(buf.Bytes())
+	return true
+}
+
+// derivedFields returns the map of derived values contributed by the passed
+// struct value when it implements the SpewFielder interface along with its
+// keys in sorted order.  A panic in SpewFields is reported as the value of a
+// derived field named SpewFields.
+func (d *dumpState) derivedFields(v reflect.Value) (derived reflect.Value, keys []reflect.Value) {
+	if d.cs.DisableMethods {
+		return reflect.Value{}, nil
+	}
+	iv, ok := interfaceValue(d.cs, v)
+	if !ok {
+		return reflect.Value{}, nil
+	}
+	fielder, ok := iv.Interface().(SpewFielder)
+	if !ok {
+		return reflect.Value{}, nil
+	}
+
+	fields := func() (fields map[string]interface{}) {
+		defer func() {
+			if err := recover(); err != nil {
+				fields = map[string]interface{}{
+					"SpewFields": fmt.Sprintf("%s%v%s", panicBytes, err,
+						closeParenBytes),
+				}
+			}
+		}()
+		return fielder.SpewFields()
+	}()
+	derived = reflect.ValueOf(fields)
+	keys = derived.MapKeys()
+	sortValues(keys, d.cs)
+	return derived, keys
+}
+
+// dumpStruct handles formatting of the fields of structs along with any
+// derived values contributed via the SpewFielder interface.
+func (d *dumpState) dumpStruct(v reflect.Value) {
+	fields := structFields(d.cs, v)
+	derived, keys := d.derivedFields(v)
+	numFields := len(fields) + len(keys)
+	parentPath := d.path
+
+	// Measure the widest field label when the values are aligned.
+	width := 0
+	if d.cs.AlignFields {
+		var buf bytes.Buffer
+		for _, sf := range fields {
+			buf.Reset()
+			d.writeFieldLabel(&buf, sf)
+			if n := displayWidth(buf.Bytes()); n > width {
+				width = n
+			}
+		}
+		for _, key := range keys {
+			if n := len(key.String()) + len(derivedBytes); n > width {
+				width = n
+			}
+		}
+	}
+
+	for i, sf := range fields {
+		d.indent()
+		if d.cs.AlignFields {
+			var label bytes.Buffer
+			d.writeFieldLabel(&label, sf)
+			d.w.Write(label.Bytes())
+			d.w.Write(colonSpaceBytes)
+			d.w.Write(bytes.Repeat(spaceBytes, width-displayWidth(label.Bytes())))
+		} else {
+			d.writeFieldLabel(d.w, sf)
+			d.w.Write(colonSpaceBytes)
+		}
+		d.ignoreNextIndent = true
+		if d.tracksPaths() {
+			d.path = fieldPath(parentPath, sf.path)
+		}
+		parentBase, parentPointer := d.fieldBase, d.fieldPointer
+		if base := fieldIntegerBase(sf.field); base != 0 {
+			d.fieldBase = base
+		}
+		if fieldHoldsPointer(sf.field) {
+			d.fieldPointer = true
+		}
+		d.dump(d.unpackValue(sf.value))
+		d.fieldBase, d.fieldPointer = parentBase, parentPointer
+		d.path = parentPath
+		if i < (numFields-1) || d.cs.ShowLayout {
+			d.w.Write(commaNewlineBytes)
+		} else {
+			d.w.Write(newlineBytes)
+		}
+	}
+
+	for i, key := range keys {
+		d.indent()
+		writeStyled(d.w, d.theme.FieldName, []byte(key.String()))
+		d.w.Write(derivedBytes)
+		d.w.Write(colonSpaceBytes)
+		if d.cs.AlignFields {
+			d.w.Write(bytes.Repeat(spaceBytes,
+				width-len(key.String())-len(derivedBytes)))
+		}
+		d.ignoreNextIndent = true
+		d.dump(d.unpackValue(derived.MapIndex(key)))
+		if len(fields)+i < (numFields-1) || d.cs.ShowLayout {
+			d.w.Write(commaNewlineBytes)
+		} else {
+			d.w.Write(newlineBytes)
+		}
+	}
+
+	// Display the total size of the struct along with any padding holes
+	// when requested.
+	if d.cs.ShowLayout {
+		d.indent()
+		printStructLayout(d.w, v.Type())
+		d.w.Write(newlineBytes)
+	}
+}
+
+// writeFieldLabel writes the label of the passed struct field, which is its
+// name followed by its tag and layout when requested, to Writer w.
+func (d *dumpState) writeFieldLabel(w io.Writer, sf structField) {
+	writeStyled(w, d.theme.FieldName, []byte(sf.name))
+	if tag := fieldTag(d.cs, sf.field); tag != "" {
+		w.Write(spaceBytes)
+		w.Write(backquoteBytes)
+		w.Write([]byte(tag))
+		w.Write(backquoteBytes)
+	}
+	if d.cs.ShowLayout {
+		w.Write(spaceBytes)
+		printFieldLayout(w, sf.field)
+	}
+}
+
+// renderMapKeys renders the passed map keys which are shown according to the
+// passed number of leading and trailing keys in order to align the values of
+// the map.  The rendered keys, indexed the same as the passed keys, are
+// returned along with the widest of them.  Nil is returned when any key spans
+// multiple lines, in which case the values are not able to be aligned.
+func (d *dumpState) renderMapKeys(keys []reflect.Value, head, tail int) ([][]byte, int) {
+	w, parentPath := d.w, d.path
+	defer func() {
+		d.w, d.path = w, parentPath
+	}()
+
+	rendered := make([][]byte, len(keys))
+	width := 0
+	for i, key := range keys {
+		if i >= head && i < len(keys)-tail {
+			continue
+		}
+		var buf bytes.Buffer
+		d.w = &buf
+		if d.tracksPaths() {
+			d.path = mapKeyPath(parentPath, key)
+		}
+		d.dump(d.unpackValue(key))
+		if bytes.IndexByte(buf.Bytes(), '\n') >= 0 {
+			return nil, 0
+		}
+		rendered[i] = buf.Bytes()
+		if n := displayWidth(rendered[i]); n > width {
+			width = n
+		}
+	}
+	return rendered, width
+}
+
+// dumpReflectType handles formatting of reflect.Type values.  Their method set
+// and a summary of the fields of struct types are included when the
+// ShowTypeDetails option is set.
+func (d *dumpState) dumpReflectType(t reflect.Type) {
+	if !d.ignoreNextType {
+		d.w.Write(reflectTypeBytes)
+		d.w.Write(spaceBytes)
+	}
+	d.ignoreNextType = false
+	d.w.Write([]byte(t.String()))
+	d.w.Write(spaceBytes)
+	printTypeKind(d.w, t)
+	if !d.cs.ShowTypeDetails {
+		return
+	}
+
+	var fields, methods []string
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fields = append(fields, field.Name+" "+field.Type.String())
+		}
+	}
+	for i := 0; i < t.NumMethod(); i++ {
+		methods = append(methods, methodSignature(t, t.Method(i)))
+	}
+
+	d.w.Write(spaceBytes)
+	d.w.Write(openBraceNewlineBytes)
+	d.depth++
+	if t.Kind() == reflect.Struct {
+		d.dumpTypeDetail(fieldsColonBytes, fields)
+		d.w.Write(commaNewlineBytes)
+	}
+	d.dumpTypeDetail(methodsColonBytes, methods)
+	d.w.Write(newlineBytes)
+	d.depth--
+	d.indent()
+	d.w.Write(closeBraceBytes)
+}
+
+// dumpTypeDetail outputs the passed label followed by the passed lines of
+// detail about a type enclosed in braces.
+func (d *dumpState) dumpTypeDetail(label []byte, lines []string) {
+	d.indent()
+	d.w.Write(label)
+	d.w.Write(openBraceNewlineBytes)
+	d.depth++
+	for i, line := range lines {
+		d.indent()
+		d.w.Write([]byte(line))
+		if i < len(lines)-1 {
+			d.w.Write(commaNewlineBytes)
+		} else {
+			d.w.Write(newlineBytes)
+		}
+	}
+	d.depth--
+	d.indent()
+	d.w.Write(closeBraceBytes)
+}
+
+// dumpJSON outputs the passed string indented to the current depth when it
+// holds a JSON object or array and is at least as long as the configured
+// threshold.  It returns whether or not it did.
+func (d *dumpState) dumpJSON(str string) bool {
+	if d.cs.PrettyJSONThreshold <= 0 || len(str) < d.cs.PrettyJSONThreshold {
+		return false
+	}
+	trimmed := strings.TrimSpace(str)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return false
+	}
+
+	var buf bytes.Buffer
+	prefix := strings.Repeat(d.cs.Indent, d.depth)
+	if err := json.Indent(&buf, []byte(trimmed), prefix, d.cs.Indent); err != nil {
+		return false
+	}
+	d.w.Write(jsonBytes)
+	d.w.Write(buf.Bytes())
+	return true
+}
+
+// dumpContainer handles formatting of the values held by containers such as
+// list.List and ring.Ring in the same way as slices.
+func (d *dumpState) dumpContainer(values []reflect.Value) {
+	numEntries := len(values)
+	if numEntries != 0 {
+		d.w.Write(openParenBytes)
+		d.w.Write(lenEqualsBytes)
+		printCount(d.w, d.cs, numEntries)
+		d.w.Write(closeParenBytes)
+		d.w.Write(spaceBytes)
+	}
+
+	d.w.Write(openBraceNewlineBytes)
+	d.depth++
+	if (d.cs.MaxDepth != 0) && (d.depth > d.cs.MaxDepth) {
+		d.indent()
+		d.truncated()
+		printMaxDepth(d.w, d.cs, maxNewlineBytes)
+	} else {
+		head, tail := shownElements(d.cs, numEntries)
+		omitted := numEntries - head - tail
+		for i := 0; i < numEntries; i++ {
+			if omitted > 0 && i == head {
+				if !d.dumpOmitted(omitted, tail) {
+					break
+				}
+				i += omitted
+			}
+			d.dump(d.unpackValue(values[i]))
+			if i < (numEntries - 1) {
+				d.w.Write(commaNewlineBytes)
+			} else {
+				d.w.Write(newlineBytes)
+			}
+		}
+	}
+	d.depth--
+	d.indent()
+	d.w.Write(closeBraceBytes)
+}
+
+// dumpIterator handles formatting of the elements drained from iterator
+// functions.  The elements of iter.Seq2 style iterators are displayed as
+// key/value pairs in the same way as maps.
+func (d *dumpState) dumpIterator(v reflect.Value) {
+	elems, more, panicVal := drainIterator(d.cs, v)
+	if panicVal != nil {
+		d.w.Write(panicBytes)
+		fmt.Fprintf(d.w, "%v", panicVal)
+		d.w.Write(closeParenBytes)
+		return
+	}
+	printSampled(d.w, len(elems), more)
+
+	d.w.Write(openBraceNewlineBytes)
+	d.depth++
+	if (d.cs.MaxDepth != 0) && (d.depth > d.cs.MaxDepth) {
+		d.indent()
+		d.truncated()
+		printMaxDepth(d.w, d.cs, maxNewlineBytes)
+	} else {
+		for i, elem := range elems {
+			d.dump(d.unpackValue(elem[0]))
+			if len(elem) > 1 {
+				d.w.Write(colonSpaceBytes)
+				d.ignoreNextIndent = true
+				d.dump(d.unpackValue(elem[1]))
+			}
+			if i < len(elems)-1 {
+				d.w.Write(commaNewlineBytes)
+			} else {
+				d.w.Write(newlineBytes)
+			}
+		}
+	}
+	d.depth--
+	d.indent()
+	d.w.Write(closeBraceBytes)
+}
+
+// summarySamples is the number of sample values from each end of a numeric
+// collection that are shown in its summary.
+const summarySamples = 3
+
+// dumpNumericSummary outputs the minimum, maximum, and mean values of the
+// passed array or slice of integers or floats along with a few samples from
+// the beginning and end of it.
+func (d *dumpState) dumpNumericSummary(v reflect.Value) {
+	numEntries := v.Len()
+	min, max := v.Index(0), v.Index(0)
+	sum := 0.0
+	nonFinite := 0
+	for i := 0; i < numEntries; i++ {
+		vi := v.Index(i)
+		if d.cs.MarkNonFinite && isNonFinite(numericFloat(vi)) {
+			nonFinite++
+		}
+		if valueSortLess(vi, min) {
+			min = vi
+		}
+		if valueSortLess(max, vi) {
+			max = vi
+		}
+		sum += numericFloat(vi)
+	}
+
+	d.indent()
+	d.w.Write(openAngleBytes)
+	d.w.Write(minEqualsBytes)
+	printNumeric(d.w, d.cs, min)
+	d.w.Write(spaceBytes)
+	d.w.Write(maxEqualsBytes)
+	printNumeric(d.w, d.cs, max)
+	d.w.Write(spaceBytes)
+	d.w.Write(meanEqualsBytes)
+	printFloat(d.w, d.cs, sum/float64(numEntries), 64)
+	d.w.Write(spaceBytes)
+	if nonFinite > 0 {
+		d.w.Write(nonFiniteEqualsBytes)
+		printInt(d.w, int64(nonFinite), 10)
+		d.w.Write(spaceBytes)
+	}
+	d.w.Write(samplesEqualsBytes)
+	d.w.Write(openBracketBytes)
+	for i := 0; i < numEntries; i++ {
+		if i == summarySamples && numEntries > summarySamples*2 {
+			d.w.Write(ellipsisBytes)
+			d.w.Write(spaceBytes)
+			i = numEntries - summarySamples
+		}
+		printNumeric(d.w, d.cs, v.Index(i))
+		if i < numEntries-1 {
+			d.w.Write(spaceBytes)
+		}
+	}
+	d.w.Write(closeBracketBytes)
+	d.w.Write(closeAngleBytes)
+	d.w.Write(newlineBytes)
+}
+
+// dump dumps the passed value and reports it to the NodeFunc callback, if any,
+// once it has been rendered.
 func (d *dumpState) dump(v reflect.Value) {
+	if d.cs.NodeFunc == nil && d.types == nil {
+		d.dumpValue(v)
+		return
+	}
+
+	node := Node{Path: d.path, Value: v, Depth: d.depth, Start: d.counter.n}
+	if v.IsValid() {
+		node.Type = v.Type()
+	}
+
+	// Values of the same type nested within this one are not outermost.
+	outermost := false
+	if d.types != nil && node.Type != nil && !d.activeTypes[node.Type] {
+		outermost = true
+		d.activeTypes[node.Type] = true
+		defer delete(d.activeTypes, node.Type)
+	}
+
+	d.dumpValue(v)
+	node.End = d.counter.n
+	if d.types != nil && node.Type != nil {
+		d.types.record(node.Type, node.End-node.Start, outermost)
+	}
+	if d.cs.NodeFunc != nil {
+		d.cs.NodeFunc(node)
+	}
+}
+
+// dumpValue is the main workhorse for dumping a value.  It uses the passed
+// reflect value to figure out what kind of object we are dealing with and
+// formats it appropriately.  It is a recursive function, however circular data
+// structures are detected and handled properly.
+func (d *dumpState) dumpValue(v reflect.Value) {
+	if d.cs.ProgressFunc != nil || d.cs.MaxNodes > 0 {
+		d.reportProgress()
+	}
+
 	// Handle invalid reflect values immediately.
 	kind := v.Kind()
 	if kind == reflect.Invalid {
@@ -256,54 +1359,188 @@ func (d *dumpState) dump(v reflect.Value) {
 		return
 	}
 
+	// Take ownership of the static type of the interface the value was
+	// unpacked from, if any, so it is not applied to nested values.
+	staticType, wrappedNil := d.staticType, d.wrappedNil
+	d.staticType, d.wrappedNil = nil, false
+
+	// Replace values of skipped types with a placeholder.
+	if d.cs.skipsType(v.Type()) {
+		if !d.ignoreNextType {
+			d.indent()
+			beginStyle(d.w, d.theme.TypeName)
+			d.w.Write(openParenBytes)
+			printStaticType(d.w, staticType)
+			d.w.Write([]byte(typeString(d.cs, v.Type())))
+			d.w.Write(closeParenBytes)
+			endStyle(d.w, d.theme.TypeName)
+			d.w.Write(spaceBytes)
+		} else {
+			d.ignoreNextIndent = false
+		}
+		d.ignoreNextType = false
+		d.w.Write(skippedAngleBytes)
+		return
+	}
+
+	// Substitute the replacement provided by the transform callback, if
+	// any.  The type being replaced is tracked for the duration of this
+	// call in order to prevent endless recursion.
+	if d.cs.TransformFunc != nil && !d.transforming[v.Type()] {
+		if tv, ok := transformValue(d.cs, v); ok {
+			if d.transforming == nil {
+				d.transforming = make(map[reflect.Type]bool)
+			}
+			vt := v.Type()
+			d.transforming[vt] = true
+			defer delete(d.transforming, vt)
+			v, kind = tv, tv.Kind()
+		}
+	}
+
+	// Substitute a snapshot of values which are accessed concurrently.  The
+	// type is remembered when the snapshot is a pointer so the value it
+	// points to is not snapshotted again.
+	snapshotted := d.snapshotted
+	d.snapshotted = nil
+	if v.Type() != snapshotted {
+		if sv, ok := snapshotValue(d.cs, v); ok {
+			if sv.Kind() == reflect.Ptr {
+				d.snapshotted = v.Type()
+			}
+			v, kind = sv, sv.Kind()
+		}
+	}
+
+	// Substitute the stand-in for types which implement the SpewRedactor
+	// interface.  Pointers are handled once they have been dereferenced.
+	if kind != reflect.Ptr && kind != reflect.Interface {
+		if sv, ok := redactedStandIn(d.cs, v); ok {
+			v, kind = sv, sv.Kind()
+		}
+	}
+
+	// Display reflect.Type values by their name and kind rather than the
+	// internal structure of the reflect package.
+	if t, ok := reflectTypeValue(v); ok {
+		d.indent()
+		d.dumpReflectType(t)
+		return
+	}
+
+	// Display weak pointers by whether or not their target is still alive
+	// rather than their internal handle.
+	if target, ok := weakPointerTarget(v); ok {
+		d.indent()
+		d.dumpWeakPointer(v.Type(), target)
+		return
+	}
+
 	// Handle pointers specially.
 	if kind == reflect.Ptr {
 		d.indent()
-		d.dumpPtr(v)
+		d.dumpPtr(v, staticType, wrappedNil)
 		return
 	}
 
-	// Print type information unless already handled elsewhere.
-	if !d.ignoreNextType {
+	// Display uintptr values which hold addresses in pointer notation.
+	if kind == reflect.Uintptr && !d.cs.hidesValues() &&
+		(d.fieldPointer || d.cs.PointerTypes[v.Type()]) {
+
 		d.indent()
+		d.dumpUintptrPointer(v, staticType)
+		return
+	}
+
+	// Print type information unless already handled elsewhere.  nil maps
+	// and slices are displayed in Go syntax, such as (map[string]int)(nil),
+	// when requested, so no space follows their type.
+	explicitNil := d.cs.ExplicitNilCollections &&
+		(kind == reflect.Map || kind == reflect.Slice) && v.IsNil()
+	typeShown := !d.ignoreNextType
+	if typeShown {
+		d.indent()
+		beginStyle(d.w, d.theme.TypeName)
 		d.w.Write(openParenBytes)
-		d.w.Write([]byte(v.Type().String()))
+		printStaticType(d.w, staticType)
+		d.w.Write([]byte(typeString(d.cs, v.Type())))
 		d.w.Write(closeParenBytes)
-		d.w.Write(spaceBytes)
+		endStyle(d.w, d.theme.TypeName)
+		if !explicitNil {
+			d.w.Write(spaceBytes)
+		}
+	} else {
+		// The indentation was written along with whatever preceded the
+		// value.
+		d.ignoreNextIndent = false
 	}
 	d.ignoreNextType = false
 
+	// Replace the value entirely when the redaction callback requests it.
+	// This is done prior to displaying the length so no information about
+	// the redacted value is leaked.
+	if redactValue(d.cs, d.w, d.path, v) {
+		return
+	}
+
+	// Hand over rendering entirely to types which implement the SpewDumper
+	// interface when the handle methods flag is enabled.
+	if !d.cs.DisableMethods && !d.cs.hidesValues() && kind != reflect.Interface {
+		if d.handleDumper(v) {
+			return
+		}
+	}
+
 	// Display length and capacity if the built-in len and cap functions
-	// work with the value's kind and the len/cap itself is non-zero.
+	// work with the value's kind and the len/cap itself is non-zero.  The
+	// length of maps and strings is always displayed when requested.
 	valueLen, valueCap := 0, 0
+	showLen := false
 	switch v.Kind() {
 	case reflect.Array, reflect.Slice, reflect.Chan:
 		valueLen, valueCap = v.Len(), v.Cap()
 	case reflect.Map, reflect.String:
 		valueLen = v.Len()
+		showLen = d.cs.ShowLengths
 	}
-	if valueLen != 0 || !d.cs.DisableCapacities && valueCap != 0 {
+	showLen = (showLen && !explicitNil) || valueLen != 0
+	if showLen || !d.cs.DisableCapacities && valueCap != 0 {
 		d.w.Write(openParenBytes)
-		if valueLen != 0 {
+		if showLen {
 			d.w.Write(lenEqualsBytes)
-			printInt(d.w, int64(valueLen), 10)
+			printCount(d.w, d.cs, valueLen)
 		}
 		if !d.cs.DisableCapacities && valueCap != 0 {
-			if valueLen != 0 {
+			if showLen {
 				d.w.Write(spaceBytes)
 			}
 			d.w.Write(capEqualsBytes)
-			printInt(d.w, int64(valueCap), 10)
+			printCount(d.w, d.cs, valueCap)
+		}
+		if t, ok := elidedElemType(d.cs, v); ok && t != v.Type().Elem() {
+			d.w.Write(spaceBytes)
+			d.w.Write(elemEqualsBytes)
+			d.w.Write([]byte(typeString(d.cs, t)))
 		}
 		d.w.Write(closeParenBytes)
 		d.w.Write(spaceBytes)
 	}
 
+	// Elide scalar values when only the shape of values is requested.
+	if d.cs.SchemaOnly && isLeafKind(kind) {
+		d.w.Write(elidedAngleBytes)
+		return
+	}
+	if d.cs.Anonymize && d.dumpAnonymized(v) {
+		return
+	}
+
 	// Call Stringer/error interfaces if they exist and the handle methods flag
-	// is enabled
-	if !d.cs.DisableMethods {
+	// is enabled.  Multi-line results are indented one level deeper than the
+	// current depth so they stay within the tree.
+	if !d.cs.DisableMethods && !d.cs.hidesValues() {
 		if (kind != reflect.Invalid) && (kind != reflect.Interface) {
-			if handled := handleMethods(d.cs, d.w, v); handled {
+			if handled := handleMethods(d.cs, d.w, v, d.depth+1); handled {
 				return
 			}
 		}
@@ -318,51 +1555,137 @@ func (d *dumpState) dump(v reflect.Value) {
 		printBool(d.w, v.Bool())
 
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
-		printInt(d.w, v.Int(), 10)
+		if names, ok := flagNames(v); ok {
+			d.w.Write([]byte(names))
+			break
+		}
+		name, isEnum := enumName(v)
+		if isEnum {
+			d.w.Write([]byte(name))
+			d.w.Write(openParenBytes)
+		}
+		beginStyle(d.w, d.theme.Number)
+		printInteger(d.w, d.cs, v, integerBase(d.cs, v.Type(), d.fieldBase))
+		endStyle(d.w, d.theme.Number)
+		if isEnum {
+			d.w.Write(closeParenBytes)
+		}
+		printCharacter(d.w, d.cs, v)
 
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
-		printUint(d.w, v.Uint(), 10)
+		if names, ok := flagNames(v); ok {
+			d.w.Write([]byte(names))
+			break
+		}
+		name, isEnum := enumName(v)
+		if isEnum {
+			d.w.Write([]byte(name))
+			d.w.Write(openParenBytes)
+		}
+		beginStyle(d.w, d.theme.Number)
+		printInteger(d.w, d.cs, v, integerBase(d.cs, v.Type(), d.fieldBase))
+		endStyle(d.w, d.theme.Number)
+		if isEnum {
+			d.w.Write(closeParenBytes)
+		}
+		printCharacter(d.w, d.cs, v)
 
 	case reflect.Float32:
-		printFloat(d.w, v.Float(), 32)
+		beginStyle(d.w, d.theme.Number)
+		printFloat(d.w, d.cs, v.Float(), 32)
+		endStyle(d.w, d.theme.Number)
 
 	case reflect.Float64:
-		printFloat(d.w, v.Float(), 64)
+		beginStyle(d.w, d.theme.Number)
+		printFloat(d.w, d.cs, v.Float(), 64)
+		endStyle(d.w, d.theme.Number)
 
 	case reflect.Complex64:
-		printComplex(d.w, v.Complex(), 32)
+		beginStyle(d.w, d.theme.Number)
+		printComplex(d.w, d.cs, v.Complex(), 32)
+		endStyle(d.w, d.theme.Number)
 
 	case reflect.Complex128:
-		printComplex(d.w, v.Complex(), 64)
+		beginStyle(d.w, d.theme.Number)
+		printComplex(d.w, d.cs, v.Complex(), 64)
+		endStyle(d.w, d.theme.Number)
 
 	case reflect.Slice:
 		if v.IsNil() {
-			d.w.Write(nilAngleBytes)
+			d.dumpNilCollection(explicitNil && typeShown)
 			break
 		}
+
+		// Slices which contain themselves, such as through an interface,
+		// are circular references.
+		if v.Len() > 0 {
+			key := newVisitKey(v)
+			if pd, ok := d.pointers[key]; ok && pd < d.depth {
+				writeStyled(d.w, d.theme.Circular, circularBytes)
+				break
+			}
+			d.pointers[key] = d.depth
+			defer delete(d.pointers, key)
+		}
+		if d.aliases != nil {
+			d.aliases.annotate(d.w, d.path, v)
+		}
 		fallthrough
 
 	case reflect.Array:
+		if d.cs.PackWidth > 0 && !d.summarizes(v) && d.dumpPacked(v) {
+			break
+		}
+		if d.cs.ExplicitNilCollections && v.Len() == 0 &&
+			!(d.cs.ShowSpareCapacity && v.Cap() > 0) {
+
+			d.w.Write(emptyBracesBytes)
+			break
+		}
 		d.w.Write(openBraceNewlineBytes)
 		d.depth++
 		if (d.cs.MaxDepth != 0) && (d.depth > d.cs.MaxDepth) {
 			d.indent()
-			d.w.Write(maxNewlineBytes)
+			d.truncated()
+			printMaxDepth(d.w, d.cs, maxNewlineBytes)
 		} else {
 			d.dumpSlice(v)
+			if d.cs.ShowSpareCapacity && kind == reflect.Slice &&
+				v.Cap() > v.Len() {
+
+				d.dumpSpareCapacity(v)
+			}
 		}
 		d.depth--
 		d.indent()
 		d.w.Write(closeBraceBytes)
 
 	case reflect.String:
-		d.w.Write([]byte(strconv.Quote(v.String())))
+		str, truncated := truncateString(d.cs, v.String())
+		if !truncated && d.dumpJSON(str) {
+			break
+		}
+		beginStyle(d.w, d.theme.String)
+		if d.cs.ShowRunes && !isASCII(str) {
+			printRunes(d.w, str)
+		} else if d.cs.RawStrings && canRawQuote(str) {
+			d.dumpRawString(str)
+		} else if d.cs.WrapStrings > 0 && len(str) > d.cs.WrapStrings {
+			d.dumpWrappedString(str)
+		} else {
+			d.w.Write([]byte(strconv.Quote(str)))
+		}
+		endStyle(d.w, d.theme.String)
+		if truncated {
+			d.truncated()
+			printTruncatedLen(d.w, d.cs, v.Len())
+		}
 
 	case reflect.Interface:
 		// The only time we should get here is for nil interfaces due to
 		// unpackValue calls.
 		if v.IsNil() {
-			d.w.Write(nilAngleBytes)
+			writeStyled(d.w, d.theme.Nil, nilAngleBytes)
 		}
 
 	case reflect.Ptr:
@@ -372,7 +1695,11 @@ func (d *dumpState) dump(v reflect.Value) {
 	case reflect.Map:
 		// nil maps should be indicated as different than empty maps
 		if v.IsNil() {
-			d.w.Write(nilAngleBytes)
+			d.dumpNilCollection(explicitNil && typeShown)
+			break
+		}
+		if d.cs.ExplicitNilCollections && v.Len() == 0 {
+			d.w.Write(emptyBracesBytes)
 			break
 		}
 
@@ -380,61 +1707,104 @@ func (d *dumpState) dump(v reflect.Value) {
 		d.depth++
 		if (d.cs.MaxDepth != 0) && (d.depth > d.cs.MaxDepth) {
 			d.indent()
-			d.w.Write(maxNewlineBytes)
+			d.truncated()
+			printMaxDepth(d.w, d.cs, maxNewlineBytes)
 		} else {
-			numEntries := v.Len()
 			keys := v.MapKeys()
-			if d.cs.SortKeys {
+			if d.cs.SortKeys || (d.cs.SampleMaps > 0 && d.cs.SampleSeed != 0) {
 				sortValues(keys, d.cs)
 			}
-			for i, key := range keys {
-				d.dump(d.unpackValue(key))
-				d.w.Write(colonSpaceBytes)
+			keys, sampled := sampleKeys(d.cs, keys)
+			numEntries := len(keys)
+			parentPath := d.path
+			head, tail := shownElements(d.cs, numEntries)
+			omitted := numEntries - head - tail
+			var alignedKeys [][]byte
+			width := 0
+			if d.cs.AlignMapValues {
+				alignedKeys, width = d.renderMapKeys(keys, head, tail)
+			}
+			for i := 0; i < numEntries; i++ {
+				if omitted > 0 && i == head {
+					if !d.dumpOmitted(omitted, tail) {
+						break
+					}
+					i += omitted
+				}
+				key := keys[i]
+				if alignedKeys != nil {
+					d.w.Write(alignedKeys[i])
+					d.w.Write(colonSpaceBytes)
+					d.w.Write(bytes.Repeat(spaceBytes,
+						width-displayWidth(alignedKeys[i])))
+				} else {
+					if d.tracksPaths() {
+						d.path = mapKeyPath(parentPath, key)
+					}
+					d.dump(d.unpackValue(key))
+					d.w.Write(colonSpaceBytes)
+				}
 				d.ignoreNextIndent = true
+				if d.tracksPaths() {
+					d.path = keyPath(parentPath, key)
+				}
 				d.dump(d.unpackValue(v.MapIndex(key)))
-				if i < (numEntries - 1) {
+				d.path = parentPath
+				if i < (numEntries-1) || sampled {
 					d.w.Write(commaNewlineBytes)
 				} else {
 					d.w.Write(newlineBytes)
 				}
 			}
+			if sampled {
+				d.indent()
+				d.truncated()
+				printSampledEntries(d.w, d.cs, numEntries, v.Len())
+				d.w.Write(newlineBytes)
+			}
 		}
 		d.depth--
 		d.indent()
 		d.w.Write(closeBraceBytes)
 
 	case reflect.Struct:
+		if !d.cs.DisableContainerTraversal {
+			if values, ok := containerValues(v); ok {
+				d.dumpContainer(values)
+				break
+			}
+		}
 		d.w.Write(openBraceNewlineBytes)
 		d.depth++
 		if (d.cs.MaxDepth != 0) && (d.depth > d.cs.MaxDepth) {
 			d.indent()
-			d.w.Write(maxNewlineBytes)
+			d.truncated()
+			printMaxDepth(d.w, d.cs, maxNewlineBytes)
 		} else {
-			vt := v.Type()
-			numFields := v.NumField()
-			for i := 0; i < numFields; i++ {
-				d.indent()
-				vtf := vt.Field(i)
-				d.w.Write([]byte(vtf.Name))
-				d.w.Write(colonSpaceBytes)
-				d.ignoreNextIndent = true
-				d.dump(d.unpackValue(v.Field(i)))
-				if i < (numFields - 1) {
-					d.w.Write(commaNewlineBytes)
-				} else {
-					d.w.Write(newlineBytes)
-				}
-			}
+			d.dumpStruct(v)
 		}
 		d.depth--
 		d.indent()
 		d.w.Write(closeBraceBytes)
 
 	case reflect.Uintptr:
+		beginStyle(d.w, d.theme.Address)
 		printHexPtr(d.w, uintptr(v.Uint()))
+		endStyle(d.w, d.theme.Address)
+
+	case reflect.Func:
+		if d.cs.MaxIteratorElements > 0 && !v.IsNil() && iteratorArity(v.Type()) > 0 {
+			d.dumpIterator(v)
+			break
+		}
+		beginStyle(d.w, d.theme.Address)
+		printHexPtr(d.w, v.Pointer())
+		endStyle(d.w, d.theme.Address)
 
-	case reflect.UnsafePointer, reflect.Chan, reflect.Func:
+	case reflect.UnsafePointer, reflect.Chan:
+		beginStyle(d.w, d.theme.Address)
 		printHexPtr(d.w, v.Pointer())
+		endStyle(d.w, d.theme.Address)
 
 	// There were not any other types at the time this code was written, but
 	// fall back to letting the default fmt package handle it in case any new
@@ -451,33 +1821,264 @@ func (d *dumpState) dump(v reflect.Value) {
 // fdump is a helper function to consolidate the logic from the various public
 // methods which take varying writers and config states.
 func fdump(cs *ConfigState, w io.Writer, a ...interface{}) {
-	for _, arg := range a {
-		if arg == nil {
-			w.Write(interfaceBytes)
-			w.Write(spaceBytes)
-			w.Write(nilAngleBytes)
-			w.Write(newlineBytes)
-			continue
+	fdumpLabeled(cs, w, nil, a)
+}
+
+// fdumpNamed dumps the values of the passed alternating labels and values
+// with each prefixed by its label.  A trailing value without a label is
+// dumped without one.
+func fdumpNamed(cs *ConfigState, w io.Writer, labelsAndValues []interface{}) {
+	labels := make([]string, 0, (len(labelsAndValues)+1)/2)
+	values := make([]interface{}, 0, cap(labels))
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		if i+1 == len(labelsAndValues) {
+			labels = append(labels, "")
+			values = append(values, labelsAndValues[i])
+			break
 		}
+		labels = append(labels, fmt.Sprint(labelsAndValues[i]))
+		values = append(values, labelsAndValues[i+1])
+	}
+	fdumpLabeled(cs, w, labels, values)
+}
 
-		d := dumpState{w: w, cs: cs}
-		d.pointers = make(map[uintptr]int)
-		d.dump(reflect.ValueOf(arg))
-		d.w.Write(newlineBytes)
+// fdumpLabeled dumps the passed arguments to io.Writer w with each prefixed by
+// the label at the same position in labels, if any, while applying the
+// options which affect the call as a whole.
+func fdumpLabeled(cs *ConfigState, w io.Writer, labels []string, a []interface{}) {
+	if NoopBuild || outputDisabled(cs) {
+		return
+	}
+	if cs.Metrics == nil {
+		fdumpArgs(cs, w, labels, a, nil)
+		return
 	}
+
+	// Record metrics about the call once it completes, even if a value
+	// panics.
+	start := time.Now()
+	counter := &countingWriter{w: w}
+	var truncations int64
+	defer func() {
+		cs.Metrics.RecordDump(DumpMetrics{
+			Caller:      callerOutsidePackage(cs),
+			Bytes:       counter.n,
+			Duration:    time.Since(start),
+			Truncations: int(atomic.LoadInt64(&truncations)),
+		})
+	}()
+	fdumpArgs(cs, counter, labels, a, &truncations)
+}
+
+// fdumpArgs dumps the passed arguments to io.Writer w with each prefixed by
+// the label at the same position in labels, if any.  The number of truncated
+// values is added to truncations when it is not nil.
+func fdumpArgs(cs *ConfigState, w io.Writer, labels []string, a []interface{}, truncations *int64) {
+	if cs.FrameDumps {
+		frame := beginFrame(w)
+		defer frame.end()
+		w = frame
+	}
+	if cs.LineNumbers {
+		w = &lineNumberWriter{w: w}
+	}
+	if cs.ShowFormatVersion {
+		w.Write(formatVersionBytes)
+		printInt(w, int64(cs.formatVersion()), 10)
+		w.Write(newlineBytes)
+	}
+
+	// The types of the values dumped are tracked across all of the arguments
+	// when requested and the index is appended once they have been dumped.
+	var types *typeIndex
+	if cs.ShowTypeIndex {
+		types = &typeIndex{}
+		defer func() {
+			types.write(w, cs)
+		}()
+	}
+
+	if cs.ParallelDump && len(a) > 1 {
+		fdumpParallel(cs, w, labels, a, truncations, types)
+		return
+	}
+
+	// Slices which share backing arrays are detected across all of the
+	// arguments when requested.
+	var aliases *sliceAliases
+	if cs.ShowSliceAliases {
+		aliases = &sliceAliases{}
+	}
+	for i, arg := range a {
+		if aliases != nil {
+			aliases.arg = i
+		}
+		if !fdumpOutput(cs, w, argLabel(labels, i), arg, aliases, truncations, types) {
+			return
+		}
+	}
+}
+
+// argLabel returns the label at the passed position in labels, if any.
+func argLabel(labels []string, i int) string {
+	if i < len(labels) {
+		return labels[i]
+	}
+	return ""
+}
+
+// fdumpOutput dumps a single top-level argument to io.Writer w, prefixed by
+// the passed label unless it is empty, while applying the Scrubbers and
+// OutputFunc options.  It returns false when the dump was aborted.
+func fdumpOutput(cs *ConfigState, w io.Writer, label string, arg interface{}, aliases *sliceAliases, truncations *int64, types *typeIndex) bool {
+	// Capture the output for the argument so it can be post-processed when
+	// requested.
+	if cs.needsPostProcessing() {
+		var buf bytes.Buffer
+		writeLabel(&buf, label)
+		ok := fdumpArg(cs, &buf, arg, aliases, truncations, types)
+		w.Write([]byte(cs.postProcess(buf.String())))
+		return ok
+	}
+
+	writeLabel(w, label)
+	return fdumpArg(cs, w, arg, aliases, truncations, types)
+}
+
+// writeLabel writes the passed label of a top-level argument to io.Writer w
+// unless it is empty.
+func writeLabel(w io.Writer, label string) {
+	if label != "" {
+		w.Write([]byte(label))
+		w.Write(colonSpaceBytes)
+	}
+}
+
+// fdumpParallel dumps the passed arguments concurrently into separate buffers
+// and then writes them to io.Writer w in order.  Panics while dumping an
+// argument are re-raised in the calling goroutine once all of the others have
+// finished, so they behave the same as they do when dumping sequentially.
+// Slices which share backing arrays are only detected within each argument.
+func fdumpParallel(cs *ConfigState, w io.Writer, labels []string, a []interface{}, truncations *int64, types *typeIndex) {
+	bufs := make([]bytes.Buffer, len(a))
+	panics := make([]interface{}, len(a))
+	completed := make([]bool, len(a))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, arg := range a {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, arg interface{}) {
+			defer func() {
+				panics[i] = recover()
+				<-sem
+				wg.Done()
+			}()
+			var aliases *sliceAliases
+			if cs.ShowSliceAliases {
+				aliases = &sliceAliases{arg: i}
+			}
+			completed[i] = fdumpOutput(cs, &bufs[i], argLabel(labels, i), arg,
+				aliases, truncations, types)
+		}(i, arg)
+	}
+	wg.Wait()
+
+	for i := range bufs {
+		if panics[i] != nil {
+			panic(panics[i])
+		}
+		w.Write(bufs[i].Bytes())
+		if !completed[i] {
+			return
+		}
+	}
+}
+
+// fdumpArg dumps a single top-level argument to io.Writer w.  The passed slice
+// aliases, if any, are used to annotate slices which share backing arrays.  It
+// returns false when the dump was aborted by the ProgressFunc callback.
+func fdumpArg(cs *ConfigState, w io.Writer, arg interface{}, aliases *sliceAliases, truncations *int64, types *typeIndex) (completed bool) {
+	if arg == nil {
+		var theme Theme
+		if cs.Theme != nil {
+			theme = *cs.Theme
+		}
+		writeStyled(w, theme.TypeName, interfaceBytes)
+		w.Write(spaceBytes)
+		writeStyled(w, theme.Nil, nilAngleBytes)
+		w.Write(newlineBytes)
+		return true
+	}
+
+	d := dumpState{w: w, cs: cs, aliases: aliases, truncations: truncations,
+		types: types}
+	if cs.Theme != nil {
+		d.theme = *cs.Theme
+	}
+	d.pointers = make(map[visitKey]int)
+	if types != nil {
+		d.activeTypes = make(map[reflect.Type]bool)
+	}
+	v := reflect.ValueOf(arg)
+	d.wrappedNil = cs.WarnTypedNil && isNilPointer(v)
+	return d.dumpLine(v)
+}
+
+// dumpLine dumps the passed value followed by a newline.  It sets up the
+// counting of the values visited and bytes written needed by the NodeFunc,
+// ProgressFunc, MaxNodes, and ShowTypeIndex options, and outputs the marker
+// for the MaxNodes option or an aborted dump in place of the rest of the
+// value when the traversal is stopped.  It returns false when the dump was
+// aborted by the ProgressFunc callback.
+func (d *dumpState) dumpLine(v reflect.Value) (completed bool) {
+	w, cs := d.w, d.cs
+	if cs.ProgressFunc != nil || cs.MaxNodes > 0 || cs.NodeFunc != nil ||
+		d.types != nil {
+
+		d.counter = &countingWriter{w: w}
+		d.w = d.counter
+	}
+	if cs.ProgressFunc != nil || cs.MaxNodes > 0 {
+		d.start = time.Now()
+		defer func() {
+			if err := recover(); err != nil {
+				if err != errDumpAborted && err != errMaxNodes {
+					panic(err)
+				}
+				if !d.counter.atNewline {
+					w.Write(newlineBytes)
+				}
+				if err == errMaxNodes {
+					d.truncated()
+					w.Write(truncationMarker(cs, maxNodesBytes, "MaxNodes",
+						cs.MaxNodes))
+					w.Write(newlineBytes)
+					completed = true
+					return
+				}
+				w.Write(truncationMarker(cs, abortedBytes, "Nodes", d.nodes))
+				w.Write(newlineBytes)
+				completed = false
+			}
+		}()
+	}
+	d.dump(v)
+	d.w.Write(newlineBytes)
+	return true
 }
 
 // Fdump formats and displays the passed arguments to io.Writer w.  It formats
 // exactly the same as Dump.
 func Fdump(w io.Writer, a ...interface{}) {
-	fdump(&Config, w, a...)
+	fdump(globalConfig(), w, a...)
 }
 
 // Sdump returns a string with the passed arguments formatted exactly the same
 // as Dump.
 func Sdump(a ...interface{}) string {
 	var buf bytes.Buffer
-	fdump(&Config, &buf, a...)
+	fdump(globalConfig(), &buf, a...)
 	return buf.String()
 }
 
@@ -505,5 +2106,58 @@ See Fdump if you would prefer dumping to an arbitrary io.Writer or Sdump to
 get the formatted result as a string.
 */
 func Dump(a ...interface{}) {
-	fdump(&Config, os.Stdout, a...)
+	fdump(globalConfig(), os.Stdout, a...)
+}
+
+// DumpIf displays the passed parameters to standard out exactly the same as
+// Dump when cond is true.  No formatting work is performed when it is false,
+// so permanent diagnostic dumps guarded by feature flags cost nothing while
+// they are disabled.
+func DumpIf(cond bool, a ...interface{}) {
+	if cond {
+		fdump(globalConfig(), os.Stdout, a...)
+	}
+}
+
+// DumpWhen displays the passed parameters to standard out exactly the same as
+// Dump when pred returns true.  The predicate is only invoked once, and no
+// formatting work is performed when it returns false.
+func DumpWhen(pred func() bool, a ...interface{}) {
+	if pred() {
+		fdump(globalConfig(), os.Stdout, a...)
+	}
+}
+
+// FdumpNamed formats and displays the values of the passed alternating labels
+// and values to io.Writer w with each prefixed by its label.  See DumpNamed
+// for details.
+func FdumpNamed(w io.Writer, labelsAndValues ...interface{}) {
+	fdumpNamed(globalConfig(), w, labelsAndValues)
+}
+
+// SdumpNamed returns a string with the values of the passed alternating labels
+// and values formatted the same as DumpNamed.
+func SdumpNamed(labelsAndValues ...interface{}) string {
+	var buf bytes.Buffer
+	fdumpNamed(globalConfig(), &buf, labelsAndValues)
+	return buf.String()
+}
+
+// DumpNamed displays the values of the passed alternating labels and values
+// to standard out exactly the same as Dump, except each is prefixed by its
+// label, so the dumps of several values in one call remain attributable:
+//
+//	spew.DumpNamed("request", req, "response", resp)
+//
+// produces output such as:
+//
+//	request: (*http.Request)(0xc000118000)({
+//	...
+//	response: (*http.Response)(0xc000176000)({
+//	...
+//
+// Labels which are not strings are formatted with fmt.Sprint, and a trailing
+// value without a label is displayed without one.
+func DumpNamed(labelsAndValues ...interface{}) {
+	fdumpNamed(globalConfig(), os.Stdout, labelsAndValues)
 }
@@ -1,3 +1,6 @@
+//go:build !spew_noop
+// +build !spew_noop
+
 /*
  * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
  *
@@ -57,13 +60,21 @@ base test element are also tested to ensure proper indirection across all types.
 - Structs that are circular through cross referencing
 - Structs that are indirectly circular
 - Type that panics in its Stringer interface
+- Type that implements the SpewDumper interface
+- Type that implements the SpewFielder interface
 */
 
 package spew_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
 	"testing"
 	"unsafe"
 
@@ -956,6 +967,66 @@ func addErrorDumpTests() {
 	addDumpTest(nv, "(*"+vt+")(<nil>)\n")
 }
 
+func addDumperDumpTests() {
+	// Type that has a custom SpewDumper interface.
+	v := dumper{"one", "two"}
+	nv := (*dumper)(nil)
+	pv := &v
+	vAddr := fmt.Sprintf("%p", pv)
+	pvAddr := fmt.Sprintf("%p", &pv)
+	vt := "spew_test.dumper"
+	vs := "[\n - one\n - two\n]"
+	addDumpTest(v, "("+vt+") "+vs+"\n")
+	addDumpTest(pv, "(*"+vt+")("+vAddr+")("+vs+")\n")
+	addDumpTest(&pv, "(**"+vt+")("+pvAddr+"->"+vAddr+")("+vs+")\n")
+	addDumpTest(nv, "(*"+vt+")(<nil>)\n")
+
+	// Nested values are indented according to their depth.
+	type s struct {
+		D dumper
+	}
+	v2 := s{dumper{"one"}}
+	v2t := "spew_test.s"
+	v2s := "{\n D: (" + vt + ") [\n  - one\n ]\n}"
+	addDumpTest(v2, "("+v2t+") "+v2s+"\n")
+
+	// Nested values dumped through the callback take part in the circular
+	// reference detection of the dump in progress.
+	v3 := &nestingDumper{}
+	v3.child = v3
+	v3Addr := fmt.Sprintf("%p", v3)
+	v3t := "*spew_test.nestingDumper"
+	v3s := "(" + v3t + ")(" + v3Addr + ")(<\n (" + v3t + ")(" + v3Addr +
+		")(<already shown>)\n>)"
+	addDumpTest(v3, v3s+"\n")
+	v4 := &nestingDumper{}
+	v4Addr := fmt.Sprintf("%p", v4)
+	v4s := "(" + v3t + ")(" + v4Addr + ")(<\n (interface {}) <nil>\n>)"
+	addDumpTest(v4, v4s+"\n")
+}
+
+func addFielderDumpTests() {
+	// Type that contributes derived values via the SpewFielder interface.
+	v := fielder{"ab"}
+	nv := (*fielder)(nil)
+	pv := &v
+	vAddr := fmt.Sprintf("%p", pv)
+	pvAddr := fmt.Sprintf("%p", &pv)
+	vt := "spew_test.fielder"
+	vs := "{\n Raw: (string) (len=2) \"ab\",\n Len (derived): (int) 2,\n" +
+		" Upper (derived): (string) (len=2) \"AB\"\n}"
+	addDumpTest(v, "("+vt+") "+vs+"\n")
+	addDumpTest(pv, "(*"+vt+")("+vAddr+")("+vs+")\n")
+	addDumpTest(&pv, "(**"+vt+")("+pvAddr+"->"+vAddr+")("+vs+")\n")
+	addDumpTest(nv, "(*"+vt+")(<nil>)\n")
+
+	// Panics in SpewFields are reported as a derived value.
+	v2 := fielder{"panic"}
+	v2s := "{\n Raw: (string) (len=5) \"panic\",\n" +
+		" SpewFields (derived): (string) (len=15) \"(PANIC=bad raw)\"\n}"
+	addDumpTest(v2, "("+vt+") "+v2s+"\n")
+}
+
 // TestDump executes all of the tests described by dumpTests.
 func TestDump(t *testing.T) {
 	// Setup tests.
@@ -977,6 +1048,8 @@ func TestDump(t *testing.T) {
 	addCircularDumpTests()
 	addPanicDumpTests()
 	addErrorDumpTests()
+	addDumperDumpTests()
+	addFielderDumpTests()
 	addCgoDumpTests()
 
 	t.Logf("Running %d tests", len(dumpTests))
@@ -1040,3 +1113,497 @@ func TestDumpSortedKeys(t *testing.T) {
 	}
 
 }
+
+// TestDumpParallel ensures dumping multiple arguments concurrently produces
+// the same output as dumping them sequentially.
+func TestDumpParallel(t *testing.T) {
+	var args []interface{}
+	for i := 0; i < 20; i++ {
+		m := map[string][]int{"a": {i}, "b": {i, i * 2}}
+		args = append(args, i, "str", m, &m, nil)
+	}
+
+	cfg := spew.ConfigState{Indent: " ", SortKeys: true}
+	want := cfg.Sdump(args...)
+	cfg.ParallelDump = true
+	if got := cfg.Sdump(args...); got != want {
+		t.Errorf("Parallel dump mismatch:\n got: %s\nwant: %s", got, want)
+	}
+
+	// Panics while dumping an argument are raised in the caller.
+	cfg.OutputFunc = func(out string) string {
+		if strings.Contains(out, "str") {
+			panic("output")
+		}
+		return out
+	}
+	defer func() {
+		if err := recover(); err != "output" {
+			t.Errorf("unexpected recover value: %v", err)
+		}
+	}()
+	cfg.Sdump(args...)
+	t.Errorf("Parallel dump did not panic")
+}
+
+// TestDumpProgress ensures the progress callback is invoked at the configured
+// interval and is able to abort the dump.
+func TestDumpProgress(t *testing.T) {
+	var reports []spew.Progress
+	cfg := spew.ConfigState{
+		Indent:           " ",
+		ProgressInterval: 2,
+		ProgressFunc: func(p spew.Progress) bool {
+			reports = append(reports, p)
+			return true
+		},
+	}
+	want := "([]int) (len=5 cap=5) {\n (int) 1,\n (int) 2,\n (int) 3,\n" +
+		" (int) 4,\n (int) 5\n}\n"
+	if got := cfg.Sdump([]int{1, 2, 3, 4, 5}); got != want {
+		t.Errorf("Progress dump mismatch:\n got: %q\nwant: %q", got, want)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("unexpected number of progress reports: %d", len(reports))
+	}
+	for i, p := range reports {
+		if p.Nodes != (i+1)*2 || p.Bytes == 0 {
+			t.Errorf("unexpected progress report #%d: %+v", i, p)
+		}
+	}
+
+	// Returning false aborts the dump, including any remaining arguments.
+	cfg.ProgressFunc = func(p spew.Progress) bool { return p.Nodes < 4 }
+	want = "([]int) (len=5 cap=5) {\n (int) 1,\n (int) 2,\n<dump aborted>\n"
+	if got := cfg.Sdump([]int{1, 2, 3, 4, 5}, "remaining"); got != want {
+		t.Errorf("Aborted dump mismatch:\n got: %q\nwant: %q", got, want)
+	}
+
+	// The marker names the number of values visited when requested.
+	cfg.ExplainTruncation = true
+	want = "([]int) (len=5 cap=5) {\n (int) 1,\n (int) 2,\n<dump aborted, Nodes=4>\n"
+	if got := cfg.Sdump([]int{1, 2, 3, 4, 5}); got != want {
+		t.Errorf("Aborted dump mismatch:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+// TestDumpSliceAliases ensures slices which share backing arrays are annotated
+// as intended.
+func TestDumpSliceAliases(t *testing.T) {
+	type aliasTester struct {
+		Items []int
+		Tail  []int
+		Other []int
+	}
+	items := []int{1, 2, 3, 4, 5}
+	at := aliasTester{items[1:3], items[3:], []int{6}}
+
+	cfg := spew.ConfigState{DisableCapacities: true, ShowSliceAliases: true}
+	want := "(spew_test.aliasTester) {\n" +
+		"Items: ([]int) (len=2) {\n(int) 2,\n(int) 3\n},\n" +
+		"Tail: ([]int) (len=2) (aliases arg0.Items[2:4]) {\n(int) 4,\n(int) 5\n},\n" +
+		"Other: ([]int) (len=1) {\n(int) 6\n}\n}\n" +
+		"([]int) (len=1) (aliases arg0.Items[1:2]) {\n(int) 3\n}\n" +
+		"([]int) (len=5) (aliased by arg0.Items) {\n(int) 1,\n(int) 2,\n" +
+		"(int) 3,\n(int) 4,\n(int) 5\n}\n"
+	if got := cfg.Sdump(at, items[2:3], items); got != want {
+		t.Errorf("Slice aliases mismatch:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// TestDumpTheme ensures the styles of a theme are applied to the elements of
+// a dump as intended.
+func TestDumpTheme(t *testing.T) {
+	type themeTester struct {
+		S string
+		N int
+		P *int
+		M map[string]int
+	}
+	theme := &spew.Theme{TypeName: "1", FieldName: "2", String: "3",
+		Number: "4", Nil: "5", Address: "6", Circular: "7"}
+	cfg := spew.ConfigState{Indent: " ", DisablePointerAddresses: true,
+		Theme: theme}
+
+	style := func(s, text string) string {
+		return "\x1b[" + s + "m" + text + "\x1b[0m"
+	}
+	want := style("1", "(spew_test.themeTester)") + " {\n" +
+		" " + style("2", "S") + ": " + style("1", "(string)") + " (len=1) " +
+		style("3", `"a"`) + ",\n" +
+		" " + style("2", "N") + ": " + style("1", "(int)") + " " + style("4", "1") +
+		",\n" +
+		" " + style("2", "P") + ": " + style("1", "(*int)") + "(" +
+		style("5", "<nil>") + "),\n" +
+		" " + style("2", "M") + ": " + style("1", "(map[string]int)") + " " +
+		style("5", "<nil>") + "\n}\n" +
+		style("1", "(interface {})") + " " + style("5", "<nil>") + "\n"
+	if got := cfg.Sdump(themeTester{S: "a", N: 1}, nil); got != want {
+		t.Errorf("Theme mismatch:\n got: %q\nwant: %q", got, want)
+	}
+
+	// Addresses and circular references.
+	type node struct{ Next *node }
+	n := &node{}
+	n.Next = n
+	cfg.DisablePointerAddresses = false
+	got := cfg.Sdump(n)
+	for _, s := range []string{"\x1b[6m0x", style("7", "<already shown>")} {
+		if !strings.Contains(got, s) {
+			t.Errorf("Theme: %q not found in %q", s, got)
+		}
+	}
+}
+
+// TestDumpPredefinedThemes ensures the predefined themes style each element as
+// intended and that elements with the empty style are left unstyled.
+func TestDumpPredefinedThemes(t *testing.T) {
+	type themeTester struct {
+		S string
+		N int
+		P *int
+	}
+	v := themeTester{S: "a", N: 1}
+	style := func(s spew.Style, text string) string {
+		if s == "" {
+			return text
+		}
+		return "\x1b[" + string(s) + "m" + text + "\x1b[0m"
+	}
+
+	tests := []struct {
+		name  string
+		theme *spew.Theme
+	}{
+		{"dark", spew.ThemeDark},
+		{"light", spew.ThemeLight},
+		{"monochrome bold", spew.ThemeMonochromeBold},
+		{"empty", &spew.Theme{}},
+	}
+	for _, test := range tests {
+		th := test.theme
+		cfg := spew.ConfigState{Indent: " ", DisablePointerAddresses: true,
+			Theme: th}
+		want := style(th.TypeName, "(spew_test.themeTester)") + " {\n" +
+			" " + style(th.FieldName, "S") + ": " +
+			style(th.TypeName, "(string)") + " (len=1) " +
+			style(th.String, `"a"`) + ",\n" +
+			" " + style(th.FieldName, "N") + ": " + style(th.TypeName, "(int)") +
+			" " + style(th.Number, "1") + ",\n" +
+			" " + style(th.FieldName, "P") + ": " +
+			style(th.TypeName, "(*int)") + "(" + style(th.Nil, "<nil>") +
+			")\n}\n"
+		if got := cfg.Sdump(v); got != want {
+			t.Errorf("%s:\n got: %q\nwant: %q", test.name, got, want)
+		}
+	}
+}
+
+// TestDumpNodeFunc ensures the node callback is invoked for every rendered
+// value with the span of its text.
+func TestDumpNodeFunc(t *testing.T) {
+	type nodeTester struct {
+		A int
+		B []string
+	}
+	var nodes []spew.Node
+	cfg := spew.ConfigState{Indent: " ", NodeFunc: func(n spew.Node) {
+		nodes = append(nodes, n)
+	}}
+	out := cfg.Sdump(nodeTester{1, []string{"x"}})
+
+	want := []struct {
+		path  string
+		depth int
+		text  string
+	}{
+		{"A", 1, "(int) 1"},
+		{"B[0]", 2, "  (string) (len=1) \"x\""},
+		{"B", 1, "([]string) (len=1 cap=1) {\n  (string) (len=1) \"x\"\n }"},
+		{"", 0, out[:len(out)-1]},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("unexpected number of nodes: %d", len(nodes))
+	}
+	for i, n := range nodes {
+		text := out[n.Start:n.End]
+		if n.Path != want[i].path || n.Depth != want[i].depth ||
+			text != want[i].text {
+
+			t.Errorf("Node #%d mismatch: path %q depth %d text %q", i,
+				n.Path, n.Depth, text)
+		}
+	}
+}
+
+// TestDumpFormatVersion ensures the format version header is written as
+// intended.
+func TestDumpFormatVersion(t *testing.T) {
+	tests := []struct {
+		version int
+		want    string
+	}{
+		{0, "spew-format-version: 1\n(int) 1\n(int) 2\n"},
+		{spew.FormatVersion1, "spew-format-version: 1\n(int) 1\n(int) 2\n"},
+		{spew.LatestFormatVersion + 1, "spew-format-version: 1\n(int) 1\n(int) 2\n"},
+	}
+	for i, test := range tests {
+		cfg := spew.ConfigState{FormatVersion: test.version,
+			ShowFormatVersion: true}
+		if got := cfg.Sdump(1, 2); got != test.want {
+			t.Errorf("Format version #%d mismatch:\n got: %q\nwant: %q", i,
+				got, test.want)
+		}
+	}
+}
+
+// metricsRecorder records the metrics passed to it.
+type metricsRecorder []spew.DumpMetrics
+
+func (m *metricsRecorder) RecordDump(dm spew.DumpMetrics) {
+	*m = append(*m, dm)
+}
+
+// TestDumpMetrics ensures the Metrics option is passed the call site, the
+// number of bytes written, and the number of truncations of each call.
+func TestDumpMetrics(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		var rec metricsRecorder
+		cfg := spew.ConfigState{Indent: " ", MaxDepth: 1, MaxStringLength: 2,
+			ParallelDump: parallel, Metrics: &rec}
+		var buf bytes.Buffer
+		_, file, line, _ := runtime.Caller(0)
+		cfg.Fdump(&buf, "abcd", [][]int{{1}, {2}})
+		cfg.Dump()
+
+		if len(rec) != 2 {
+			t.Fatalf("parallel %v: got %d calls, want 2", parallel, len(rec))
+		}
+		want := spew.DumpMetrics{
+			Caller:      fmt.Sprintf("%s:%d", file, line+1),
+			Bytes:       int64(buf.Len()),
+			Duration:    rec[0].Duration,
+			Truncations: 3,
+		}
+		if rec[0] != want {
+			t.Errorf("parallel %v: got %+v, want %+v", parallel, rec[0], want)
+		}
+		if rec[1].Bytes != 0 || rec[1].Truncations != 0 {
+			t.Errorf("parallel %v: got %+v for empty call", parallel, rec[1])
+		}
+	}
+}
+
+// dumpWrapper is a helper which wraps Fdump in order to test the CallerSkip
+// option.
+func dumpWrapper(cs *spew.ConfigState, a ...interface{}) {
+	cs.Fdump(ioutil.Discard, a...)
+}
+
+// TestDumpCallerSkip ensures the CallerSkip option skips the frames of
+// wrappers when reporting call sites.
+func TestDumpCallerSkip(t *testing.T) {
+	var rec metricsRecorder
+	cfg := spew.ConfigState{Metrics: &rec, CallerSkip: 1}
+	_, file, line, _ := runtime.Caller(0)
+	dumpWrapper(&cfg, 1)
+	if want := fmt.Sprintf("%s:%d", file, line+1); rec[0].Caller != want {
+		t.Errorf("Caller: got %q, want %q", rec[0].Caller, want)
+	}
+}
+
+// TestDumpTypeIndex ensures the index of types appended with the ShowTypeIndex
+// option counts the values of each type and the bytes they were rendered as.
+func TestDumpTypeIndex(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	n := &node{Name: "a", Next: &node{Name: "bc"}}
+	for _, parallel := range []bool{false, true} {
+		cs := spew.ConfigState{Indent: " ", DisablePointerAddresses: true,
+			ShowTypeIndex: true, ParallelDump: parallel}
+		got := cs.Sdump(n, 1)
+		want := "(*spew_test.node)({\n" +
+			" Name: (string) (len=1) \"a\",\n" +
+			" Next: (*spew_test.node)({\n" +
+			"  Name: (string) (len=2) \"bc\",\n" +
+			"  Next: (*spew_test.node)(<nil>)\n" +
+			" })\n" +
+			"})\n" +
+			"(int) 1\n" +
+			"Type index:\n" +
+			" (*spew_test.node) count=3 bytes=146\n" +
+			" (spew_test.node) count=2 bytes=127\n" +
+			" (string) count=2 bytes=41\n" +
+			" (int) count=1 bytes=7\n"
+		if got != want {
+			t.Errorf("parallel %v: got:\n%s\nwant:\n%s", parallel, got, want)
+		}
+	}
+}
+
+// TestDumpFrames ensures the FrameDumps option surrounds the output of each
+// call with markers carrying a unique identifier and the number of lines.
+func TestDumpFrames(t *testing.T) {
+	cs := spew.ConfigState{Indent: " ", FrameDumps: true}
+	re := regexp.MustCompile(`^--- SPEW BEGIN id=([0-9a-f]{8}) ---\n` +
+		`\(\[\]int\) \(len=1 cap=1\) \{\n \(int\) 1\n\}\n\(int\) 2\n` +
+		`--- SPEW END id=([0-9a-f]{8}) \(lines=4\) ---\n$`)
+	ids := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		got := cs.Sdump([]int{1}, 2)
+		m := re.FindStringSubmatch(got)
+		if m == nil {
+			t.Fatalf("unexpected output:\n%s", got)
+		}
+		if m[1] != m[2] {
+			t.Errorf("mismatched ids %q and %q", m[1], m[2])
+		}
+		ids[m[1]] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("frame ids are not unique: %v", ids)
+	}
+}
+
+// TestDumpSampleMaps ensures the SampleMaps option displays the requested
+// number of entries of larger maps followed by the total and that the same
+// entries are selected each time when SampleSeed is set.
+func TestDumpSampleMaps(t *testing.T) {
+	m := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		m[i] = i
+	}
+	for _, seed := range []int64{0, 42} {
+		cs := spew.ConfigState{Indent: " ", SampleMaps: 3, SampleSeed: seed}
+		got := cs.Sdump(m)
+		if n := strings.Count(got, "(int) "); n != 6 {
+			t.Errorf("seed %d: got %d ints, want 6:\n%s", seed, n, got)
+		}
+		if !strings.HasSuffix(got, " <sampled 3 of 100 entries>\n}\n") {
+			t.Errorf("seed %d: missing sample marker:\n%s", seed, got)
+		}
+		if seed != 0 {
+			if again := cs.Sdump(m); again != got {
+				t.Errorf("seed %d: unstable sample:\n%s\nvs:\n%s", seed,
+					got, again)
+			}
+		}
+	}
+
+	cs := spew.ConfigState{Indent: " ", SampleMaps: 3, SortKeys: true}
+	want := "(map[int]int) (len=2) {\n (int) 0: (int) 0,\n (int) 1: (int) 1\n}\n"
+	if got := cs.Sdump(map[int]int{0: 0, 1: 1}); got != want {
+		t.Errorf("small map: got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestDumpUintptrPointers ensures uintptr values which hold addresses are
+// displayed in pointer notation and followed with the PointerResolver
+// callback.
+func TestDumpUintptrPointers(t *testing.T) {
+	type handle uintptr
+	type window struct {
+		Title string
+		Self  handle
+	}
+	type call struct {
+		Arg  uintptr `spew:"ptr"`
+		Code uintptr
+	}
+	w := &window{Title: "a", Self: 0x10}
+	cs := spew.ConfigState{
+		Indent:       " ",
+		PointerTypes: map[reflect.Type]bool{reflect.TypeOf(handle(0)): true},
+		PointerResolver: func(t reflect.Type, addr uintptr) (reflect.Value, bool) {
+			if addr != 0x10 {
+				return reflect.Value{}, false
+			}
+			return reflect.ValueOf(*w), true
+		},
+	}
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{call{}, "(spew_test.call) {\n Arg: (uintptr)(<nil>),\n Code: (uintptr) <nil>\n}\n"},
+		{call{Arg: 0x20, Code: 0x20}, "(spew_test.call) {\n" +
+			" Arg: (uintptr)(0x20),\n Code: (uintptr) 0x20\n}\n"},
+		{handle(0x10), "(spew_test.handle)(0x10) → (spew_test.window) {\n" +
+			" Title: (string) (len=1) \"a\",\n" +
+			" Self: (spew_test.handle)(0x10) → <already shown>\n}\n"},
+	}
+	for i, test := range tests {
+		if got := cs.Sdump(test.in); got != test.want {
+			t.Errorf("#%d got:\n%s\nwant:\n%s", i, got, test.want)
+		}
+	}
+}
+
+// TestDumpUnexportedInterfaces ensures the dynamic values of interfaces held
+// by unexported struct fields are dumped in full, with or without access to
+// the unsafe package.
+func TestDumpUnexportedInterfaces(t *testing.T) {
+	type holder struct {
+		err error
+		any interface{}
+		nil interface{}
+	}
+	h := holder{err: errors.New("boom"), any: map[string]int{"a": 1}}
+	cs := spew.ConfigState{Indent: " ", DisableMethods: true,
+		DisablePointerAddresses: true}
+	want := "(spew_test.holder) {\n" +
+		" err: (*errors.errorString)({\n" +
+		"  s: (string) (len=4) \"boom\"\n" +
+		" }),\n" +
+		" any: (map[string]int) (len=1) {\n" +
+		"  (string) (len=1) \"a\": (int) 1\n" +
+		" },\n" +
+		" nil: (interface {}) <nil>\n" +
+		"}\n"
+	if got := cs.Sdump(h); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestDumpMaxNodes ensures the MaxNodes option stops the traversal of each
+// argument once the limit is reached while still dumping later arguments.
+func TestDumpMaxNodes(t *testing.T) {
+	cs := spew.ConfigState{Indent: " ", MaxNodes: 3}
+	got := cs.Sdump([]int{1, 2, 3, 4}, 5)
+	want := "([]int) (len=4 cap=4) {\n" +
+		" (int) 1,\n" +
+		" (int) 2,\n" +
+		"<max nodes reached>\n" +
+		"(int) 5\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	cs.ExplainTruncation = true
+	got = cs.Sdump(map[int]int{1: 1, 2: 2})
+	if !strings.HasSuffix(got, "<max nodes reached, MaxNodes=3>\n") {
+		t.Errorf("missing explained marker:\n%s", got)
+	}
+}
+
+// TestDumperMaxDepth ensures values nested by SpewDumper implementations are
+// dumped one level deeper and honor MaxDepth.
+func TestDumperMaxDepth(t *testing.T) {
+	cs := spew.ConfigState{Indent: " ", MaxDepth: 2,
+		DisablePointerAddresses: true}
+	v := &nestingDumper{[]int{1}}
+	want := "(*spew_test.nestingDumper)(<\n ([]int) (len=1 cap=1) {\n" +
+		"  (int) 1\n }\n>)\n"
+	if got := cs.Sdump(v); got != want {
+		t.Errorf("MaxDepth 2:\n got: %q\nwant: %q", got, want)
+	}
+
+	cs.MaxDepth = 1
+	want = "(*spew_test.nestingDumper)(<\n ([]int) (len=1 cap=1) {\n" +
+		"  <max depth reached>\n }\n>)\n"
+	if got := cs.Sdump(v); got != want {
+		t.Errorf("MaxDepth 1:\n got: %q\nwant: %q", got, want)
+	}
+}
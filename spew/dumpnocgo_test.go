@@ -14,9 +14,9 @@
 
 // NOTE: Due to the following build constraints, this file will only be compiled
 // when either cgo is not supported or "-tags testcgo" is not added to the go
-// test command line.  This file intentionally does not setup any cgo tests in
-// this scenario.
-// +build !cgo !testcgo
+// test command line, unless "-tags spew_noop" is added.  This file
+// intentionally does not setup any cgo tests in this scenario.
+// +build !cgo,!spew_noop !testcgo,!spew_noop
 
 package spew_test
 
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// enumsMtx protects enums.
+	enumsMtx sync.RWMutex
+
+	// enums houses the registered names of constant values by type.
+	enums = map[reflect.Type]map[int64]string{}
+
+	// flags houses the registered names of bitmask flags by type along with
+	// the flags sorted by value.
+	flags = map[reflect.Type]flagTable{}
+)
+
+// flagTable houses the names of the flags of a bitmask type sorted by value.
+type flagTable struct {
+	values []uint64
+	names  map[uint64]string
+}
+
+// RegisterEnum registers the passed names of the constant values of the
+// passed integer type so values of it are displayed symbolically along with
+// their number, such as StateClosed(3), even when the type does not implement
+// the Stringer interface, as is common for constants from third-party
+// packages.  Values without a registered name are displayed as usual.  The
+// values of unsigned types are converted to int64 to index names.
+//
+// Registering a type which already exists replaces its names, while passing
+// nil names removes it.  The Stringer and error interfaces take precedence
+// over registered names unless methods are disabled.  It is safe for
+// concurrent use.
+//
+// The EnumNames function produces names for constants declared with iota:
+//
+//	spew.RegisterEnum(reflect.TypeOf(StateOpen),
+//		spew.EnumNames(0, "StateOpen", "StateClosing", "StateClosed"))
+func RegisterEnum(t reflect.Type, names map[int64]string) {
+	enumsMtx.Lock()
+	defer enumsMtx.Unlock()
+	if names == nil {
+		delete(enums, t)
+		return
+	}
+	copied := make(map[int64]string, len(names))
+	for value, name := range names {
+		copied[value] = name
+	}
+	enums[t] = copied
+}
+
+// RegisterFlags registers the passed names of the flags of the passed integer
+// bitmask type so values of it are displayed as the OR of the names of the
+// flags which are set followed by the value in hexadecimal, such as
+// O_RDWR|O_CREATE (0x42), rather than a bare integer.  Bits without a
+// registered name are included as a hexadecimal remainder, and a name
+// registered for zero is used when no bits are set.  The values of signed
+// types are converted to uint64 to index names.
+//
+// Registering a type which already exists replaces its names, while passing
+// nil names removes it.  As with RegisterEnum, the Stringer and error
+// interfaces take precedence over registered names, and registered flags take
+// precedence over registered constants of the same type.  It is safe for
+// concurrent use.
+func RegisterFlags(t reflect.Type, names map[uint64]string) {
+	enumsMtx.Lock()
+	defer enumsMtx.Unlock()
+	if names == nil {
+		delete(flags, t)
+		return
+	}
+	table := flagTable{names: make(map[uint64]string, len(names))}
+	for value, name := range names {
+		table.values = append(table.values, value)
+		table.names[value] = name
+	}
+	sort.Slice(table.values, func(i, j int) bool {
+		return table.values[i] < table.values[j]
+	})
+	flags[t] = table
+}
+
+// EnumNames returns a table for RegisterEnum which maps consecutive values,
+// beginning with first, to the passed names in order, as is produced by a
+// block of constants declared with iota.  Empty names are skipped so gaps in
+// the block may be preserved.
+func EnumNames(first int64, names ...string) map[int64]string {
+	table := make(map[int64]string, len(names))
+	for i, name := range names {
+		if name != "" {
+			table[first+int64(i)] = name
+		}
+	}
+	return table
+}
+
+// enumName returns the name registered with RegisterEnum for the passed
+// integer value along with whether or not one exists.
+func enumName(v reflect.Value) (string, bool) {
+	enumsMtx.RLock()
+	defer enumsMtx.RUnlock()
+	names, ok := enums[v.Type()]
+	if !ok {
+		return "", false
+	}
+
+	var value int64
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		value = v.Int()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uint, reflect.Uintptr:
+		value = int64(v.Uint())
+	default:
+		return "", false
+	}
+	name, ok := names[value]
+	return name, ok
+}
+
+// flagNames returns the passed integer value as the OR of the names of its
+// flags registered with RegisterFlags followed by the value in hexadecimal
+// along with whether or not flags are registered for its type.
+func flagNames(v reflect.Value) (string, bool) {
+	enumsMtx.RLock()
+	defer enumsMtx.RUnlock()
+	table, ok := flags[v.Type()]
+	if !ok {
+		return "", false
+	}
+
+	var value uint64
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		// Only the bits of the type are of interest for negative values.
+		value = uint64(v.Int()) & (1<<(8*v.Type().Size()) - 1)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uint, reflect.Uintptr:
+		value = v.Uint()
+	default:
+		return "", false
+	}
+
+	var names []string
+	remaining := value
+	for _, flag := range table.values {
+		if flag != 0 && value&flag == flag {
+			names = append(names, table.names[flag])
+			remaining &^= flag
+		}
+	}
+	if remaining != 0 {
+		names = append(names, "0x"+strconv.FormatUint(remaining, 16))
+	}
+	if len(names) == 0 {
+		name, ok := table.names[0]
+		if !ok {
+			name = "0"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, "|") + " (0x" + strconv.FormatUint(value, 16) +
+		")", true
+}
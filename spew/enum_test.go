@@ -0,0 +1,111 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// enumState is a named integer type without a Stringer used to test the
+// registered names of constant values.
+type enumState uint8
+
+// enumStringer is a named integer type with a Stringer used to ensure it takes
+// precedence over registered names.
+type enumStringer int
+
+func (e enumStringer) String() string {
+	return "stringer"
+}
+
+// TestRegisterEnum ensures integers of registered types are displayed
+// symbolically when a name is registered for their value.
+func TestRegisterEnum(t *testing.T) {
+	stateType := reflect.TypeOf(enumState(0))
+	stringerType := reflect.TypeOf(enumStringer(0))
+	spew.RegisterEnum(stateType, spew.EnumNames(1, "StateOpen", "",
+		"StateClosed"))
+	spew.RegisterEnum(stringerType, map[int64]string{1: "One"})
+	defer spew.RegisterEnum(stateType, nil)
+	defer spew.RegisterEnum(stringerType, nil)
+
+	cs := spew.ConfigState{Indent: " "}
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{cs.Sdump(enumState(3)), "(spew_test.enumState) StateClosed(3)\n"},
+		{cs.Sdump(enumState(2)), "(spew_test.enumState) 2\n"},
+		{cs.Sprint([]enumState{1, 4}), "[StateOpen(1) 4]"},
+		{cs.Sdump(uint8(1)), "(uint8) 1\n"},
+		{cs.Sdump(enumStringer(1)), "(spew_test.enumStringer) stringer\n"},
+		{fmt.Sprint(spew.NewFormatter(enumStringer(1))), "stringer"},
+	}
+	for i, test := range tests {
+		if test.got != test.want {
+			t.Errorf("#%d: got %q, want %q", i, test.got, test.want)
+		}
+	}
+
+	spew.RegisterEnum(stateType, nil)
+	if got, want := cs.Sdump(enumState(3)), "(spew_test.enumState) 3\n"; got != want {
+		t.Errorf("after removal: got %q, want %q", got, want)
+	}
+}
+
+// enumFlags is a named integer bitmask type used to test the registered names
+// of flags.
+type enumFlags int32
+
+// TestRegisterFlags ensures integers of registered bitmask types are displayed
+// as the OR of the names of their flags.
+func TestRegisterFlags(t *testing.T) {
+	flagsType := reflect.TypeOf(enumFlags(0))
+	spew.RegisterFlags(flagsType, map[uint64]string{0x40: "O_CREATE",
+		0x2: "O_RDWR", 0x1: "O_WRONLY"})
+	defer spew.RegisterFlags(flagsType, nil)
+
+	cs := spew.ConfigState{Indent: " "}
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{cs.Sdump(enumFlags(0x42)),
+			"(spew_test.enumFlags) O_RDWR|O_CREATE (0x42)\n"},
+		{cs.Sprint(enumFlags(0x101)), "O_WRONLY|0x100 (0x101)"},
+		{cs.Sprint(enumFlags(0)), "0 (0x0)"},
+		{cs.Sprint(enumFlags(-1)),
+			"O_WRONLY|O_RDWR|O_CREATE|0xffffffbc (0xffffffff)"},
+	}
+	for i, test := range tests {
+		if test.got != test.want {
+			t.Errorf("#%d: got %q, want %q", i, test.got, test.want)
+		}
+	}
+
+	spew.RegisterFlags(flagsType, map[uint64]string{0: "O_RDONLY"})
+	if got, want := cs.Sprint(enumFlags(0)), "O_RDONLY (0x0)"; got != want {
+		t.Errorf("zero name: got %q, want %q", got, want)
+	}
+}
@@ -35,11 +35,30 @@ type formatState struct {
 	value          interface{}
 	fs             fmt.State
 	depth          int
-	pointers       map[uintptr]int
+	pointers       map[visitKey]int
 	ignoreNextType bool
+	path           string
+	staticType     reflect.Type
+	wrappedNil     bool
+	transforming   map[reflect.Type]bool
+	snapshotted    reflect.Type
+	fieldBase      int
 	cs             *ConfigState
 }
 
+// bufferedState wraps a fmt.State in order to capture the output written to it
+// while still providing access to the flags, width, and precision.
+type bufferedState struct {
+	fmt.State
+	buf bytes.Buffer
+}
+
+// Write captures the passed bytes.  It is part of the io.Writer interface
+// implementation.
+func (b *bufferedState) Write(p []byte) (n int, err error) {
+	return b.buf.Write(p)
+}
+
 // buildDefaultFormat recreates the original format string without precision
 // and width information to pass in to fmt.Sprintf in the case of an
 // unrecognized type.  Unless new types are added to the language, this
@@ -94,15 +113,125 @@ func (f *formatState) constructOrigFormat(verb rune) (format string) {
 func (f *formatState) unpackValue(v reflect.Value) reflect.Value {
 	if v.Kind() == reflect.Interface {
 		f.ignoreNextType = false
-		if !v.IsNil() {
+		if !v.IsNil() && !f.cs.skipsType(v.Type()) {
+			if f.cs.ShowInterfaceTypes && v.Type().NumMethod() > 0 {
+				f.staticType = v.Type()
+			}
 			v = v.Elem()
+			f.wrappedNil = f.cs.WarnTypedNil && isNilPointer(v)
 		}
 	}
 	return v
 }
 
+// handleTypeFormatter invokes the function registered for the type of the
+// passed reflect.Value in the TypeFormatters configuration option, if any, and
+// returns whether or not it did.
+func (f *formatState) handleTypeFormatter(v reflect.Value) (handled bool) {
+	fn, ok := f.cs.TypeFormatters[v.Type()]
+	if !ok {
+		return false
+	}
+
+	if !v.CanInterface() {
+		v = unsafeReflectValue(v)
+	}
+	defer catchPanic(f.fs, v)
+	fn(f.fs, v)
+	return true
+}
+
+// formatContainer handles formatting of the values held by containers such as
+// list.List and ring.Ring in the same way as slices.
+func (f *formatState) formatContainer(values []reflect.Value) {
+	f.fs.Write(openBracketBytes)
+	f.depth++
+	if (f.cs.MaxDepth != 0) && (f.depth > f.cs.MaxDepth) {
+		printMaxDepth(f.fs, f.cs, maxShortBytes)
+	} else {
+		numEntries := len(values)
+		head, tail := shownElements(f.cs, numEntries)
+		omitted := numEntries - head - tail
+		for i := 0; i < numEntries; i++ {
+			if i > 0 {
+				f.fs.Write(spaceBytes)
+			}
+			if omitted > 0 && i == head {
+				if !f.formatOmitted(omitted, tail) {
+					break
+				}
+				i += omitted
+			}
+			f.ignoreNextType = true
+			f.format(f.unpackValue(values[i]))
+		}
+	}
+	f.depth--
+	f.fs.Write(closeBracketBytes)
+}
+
+// formatIterator handles formatting of the elements drained from iterator
+// functions.  The elements of iter.Seq2 style iterators are displayed as
+// key:value pairs in the same way as maps.  A trailing "..." indicates the
+// iterator had more elements.
+func (f *formatState) formatIterator(v reflect.Value) {
+	elems, more, panicVal := drainIterator(f.cs, v)
+	if panicVal != nil {
+		f.fs.Write(panicBytes)
+		fmt.Fprintf(f.fs, "%v", panicVal)
+		f.fs.Write(closeParenBytes)
+		return
+	}
+
+	f.fs.Write(openBracketBytes)
+	f.depth++
+	if (f.cs.MaxDepth != 0) && (f.depth > f.cs.MaxDepth) {
+		printMaxDepth(f.fs, f.cs, maxShortBytes)
+	} else {
+		for i, elem := range elems {
+			if i > 0 {
+				f.fs.Write(spaceBytes)
+			}
+			f.ignoreNextType = len(elem) > 1
+			f.format(f.unpackValue(elem[0]))
+			if len(elem) > 1 {
+				f.fs.Write(colonBytes)
+				f.ignoreNextType = true
+				f.format(f.unpackValue(elem[1]))
+			}
+		}
+		if more {
+			if len(elems) > 0 {
+				f.fs.Write(spaceBytes)
+			}
+			f.fs.Write(ellipsisBytes)
+		}
+	}
+	f.depth--
+	f.fs.Write(closeBracketBytes)
+}
+
+// formatOmitted outputs an indication of the number of elements of a
+// collection which were omitted followed by a separator when there are
+// trailing elements to follow.  It returns whether or not there are.
+func (f *formatState) formatOmitted(omitted, tail int) bool {
+	printOmitted(f.fs, f.cs, omitted)
+	if tail == 0 {
+		return false
+	}
+	f.fs.Write(spaceBytes)
+	return true
+}
+
 // formatPtr handles formatting of pointers by indirecting them as necessary.
-func (f *formatState) formatPtr(v reflect.Value) {
+// The passed static type is the type of the interface the pointer was stored
+// in, if any.
+func (f *formatState) formatPtr(v reflect.Value, staticType reflect.Type, wrappedNil bool) {
+	// Warn about nil pointers which make the interface holding them non-nil.
+	if wrappedNil && v.IsNil() {
+		defer f.fs.Write(typedNilWarningBytes)
+	}
+
 	// Display nil if top level pointer is nil.
 	showTypes := f.fs.Flag('#')
 	if v.IsNil() && (!showTypes || f.ignoreNextType) {
@@ -136,12 +265,13 @@ func (f *formatState) formatPtr(v reflect.Value) {
 		indirects++
 		addr := ve.Pointer()
 		pointerChain = append(pointerChain, addr)
-		if pd, ok := f.pointers[addr]; ok && pd < f.depth {
+		key := newVisitKey(ve)
+		if pd, ok := f.pointers[key]; ok && pd < f.depth {
 			cycleFound = true
 			indirects--
 			break
 		}
-		f.pointers[addr] = f.depth
+		f.pointers[key] = f.depth
 
 		ve = ve.Elem()
 		if ve.Kind() == reflect.Interface {
@@ -156,8 +286,9 @@ func (f *formatState) formatPtr(v reflect.Value) {
 	// Display type or indirection level depending on flags.
 	if showTypes && !f.ignoreNextType {
 		f.fs.Write(openParenBytes)
+		printStaticType(f.fs, staticType)
 		f.fs.Write(bytes.Repeat(asteriskBytes, indirects))
-		f.fs.Write([]byte(ve.Type().String()))
+		f.fs.Write([]byte(typeString(f.cs, ve.Type())))
 		f.fs.Write(closeParenBytes)
 	} else {
 		if nilFound || cycleFound {
@@ -194,6 +325,21 @@ func (f *formatState) formatPtr(v reflect.Value) {
 	}
 }
 
+// formatNilCollection handles formatting of nil maps and slices.  They are
+// displayed in Go syntax, such as map[string]int(nil), when the
+// ExplicitNilCollections option is set, with the type omitted when the passed
+// typeShown flag indicates it was already displayed.
+func (f *formatState) formatNilCollection(v reflect.Value, typeShown bool) {
+	if !f.cs.ExplicitNilCollections {
+		f.fs.Write(nilAngleBytes)
+		return
+	}
+	if !typeShown {
+		f.fs.Write([]byte(typeString(f.cs, v.Type())))
+	}
+	f.fs.Write(nilParenBytes)
+}
+
 // format is the main workhorse for providing the Formatter interface.  It
 // uses the passed reflect value to figure out what kind of object we are
 // dealing with and formats it appropriately.  It is a recursive function,
@@ -206,25 +352,121 @@ func (f *formatState) format(v reflect.Value) {
 		return
 	}
 
+	// Take ownership of the static type of the interface the value was
+	// unpacked from, if any, so it is not applied to nested values.
+	staticType, wrappedNil := f.staticType, f.wrappedNil
+	f.staticType, f.wrappedNil = nil, false
+
+	// Replace values of skipped types with a placeholder.
+	if f.cs.skipsType(v.Type()) {
+		if f.fs.Flag('#') && !f.ignoreNextType {
+			f.fs.Write(openParenBytes)
+			printStaticType(f.fs, staticType)
+			f.fs.Write([]byte(typeString(f.cs, v.Type())))
+			f.fs.Write(closeParenBytes)
+		}
+		f.ignoreNextType = false
+		f.fs.Write(skippedAngleBytes)
+		return
+	}
+
+	// Substitute the replacement provided by the transform callback, if
+	// any.  The type being replaced is tracked for the duration of this
+	// call in order to prevent endless recursion.
+	if f.cs.TransformFunc != nil && !f.transforming[v.Type()] {
+		if tv, ok := transformValue(f.cs, v); ok {
+			if f.transforming == nil {
+				f.transforming = make(map[reflect.Type]bool)
+			}
+			vt := v.Type()
+			f.transforming[vt] = true
+			defer delete(f.transforming, vt)
+			v, kind = tv, tv.Kind()
+		}
+	}
+
+	// Substitute a snapshot of values which are accessed concurrently.  The
+	// type is remembered when the snapshot is a pointer so the value it
+	// points to is not snapshotted again.
+	snapshotted := f.snapshotted
+	f.snapshotted = nil
+	if v.Type() != snapshotted {
+		if sv, ok := snapshotValue(f.cs, v); ok {
+			if sv.Kind() == reflect.Ptr {
+				f.snapshotted = v.Type()
+			}
+			v, kind = sv, sv.Kind()
+		}
+	}
+
+	// Display reflect.Type values by their name rather than the internal
+	// structure of the reflect package.
+	if t, ok := reflectTypeValue(v); ok {
+		if f.fs.Flag('#') {
+			f.fs.Write(reflectTypeBytes)
+		}
+		f.fs.Write([]byte(t.String()))
+		return
+	}
+
+	// Display weak pointers by whether or not their target is still alive
+	// rather than their internal handle.
+	if target, ok := weakPointerTarget(v); ok {
+		if f.fs.Flag('#') && !f.ignoreNextType {
+			f.fs.Write(openParenBytes)
+			f.fs.Write([]byte(typeString(f.cs, v.Type())))
+			f.fs.Write(closeParenBytes)
+		}
+		f.ignoreNextType = false
+		if target.IsNil() {
+			f.fs.Write(weakCollectedBytes)
+			return
+		}
+		f.fs.Write(weakLiveBytes)
+		f.fs.Write(spaceBytes)
+		f.format(target)
+		return
+	}
+
+	// Substitute the stand-in for types which implement the SpewRedactor
+	// interface.  Pointers are handled once they have been dereferenced.
+	if kind != reflect.Ptr && kind != reflect.Interface {
+		if sv, ok := redactedStandIn(f.cs, v); ok {
+			v, kind = sv, sv.Kind()
+		}
+	}
+
 	// Handle pointers specially.
 	if kind == reflect.Ptr {
-		f.formatPtr(v)
+		f.formatPtr(v, staticType, wrappedNil)
 		return
 	}
 
 	// Print type information unless already handled elsewhere.
-	if !f.ignoreNextType && f.fs.Flag('#') {
+	typeShown := !f.ignoreNextType && f.fs.Flag('#')
+	if typeShown {
 		f.fs.Write(openParenBytes)
-		f.fs.Write([]byte(v.Type().String()))
+		printStaticType(f.fs, staticType)
+		f.fs.Write([]byte(typeString(f.cs, v.Type())))
 		f.fs.Write(closeParenBytes)
 	}
 	f.ignoreNextType = false
 
+	// Replace the value entirely when the redaction callback requests it.
+	if redactValue(f.cs, f.fs, f.path, v) {
+		return
+	}
+
+	// Use the registered formatter function for the type if there is one.
+	if f.handleTypeFormatter(v) {
+		return
+	}
+
 	// Call Stringer/error interfaces if they exist and the handle methods
 	// flag is enabled.
 	if !f.cs.DisableMethods {
 		if (kind != reflect.Invalid) && (kind != reflect.Interface) {
-			if handled := handleMethods(f.cs, f.fs, v); handled {
+			if handled := handleMethods(f.cs, f.fs, v, 0); handled {
 				return
 			}
 		}
@@ -239,50 +481,109 @@ func (f *formatState) format(v reflect.Value) {
 		printBool(f.fs, v.Bool())
 
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
-		printInt(f.fs, v.Int(), 10)
+		if names, ok := flagNames(v); ok {
+			f.fs.Write([]byte(names))
+			break
+		}
+		name, isEnum := enumName(v)
+		if isEnum {
+			f.fs.Write([]byte(name))
+			f.fs.Write(openParenBytes)
+		}
+		printInteger(f.fs, f.cs, v, integerBase(f.cs, v.Type(), f.fieldBase))
+		if isEnum {
+			f.fs.Write(closeParenBytes)
+		}
+		printCharacter(f.fs, f.cs, v)
 
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
-		printUint(f.fs, v.Uint(), 10)
+		if names, ok := flagNames(v); ok {
+			f.fs.Write([]byte(names))
+			break
+		}
+		name, isEnum := enumName(v)
+		if isEnum {
+			f.fs.Write([]byte(name))
+			f.fs.Write(openParenBytes)
+		}
+		printInteger(f.fs, f.cs, v, integerBase(f.cs, v.Type(), f.fieldBase))
+		if isEnum {
+			f.fs.Write(closeParenBytes)
+		}
+		printCharacter(f.fs, f.cs, v)
 
 	case reflect.Float32:
-		printFloat(f.fs, v.Float(), 32)
+		printFloat(f.fs, f.cs, v.Float(), 32)
 
 	case reflect.Float64:
-		printFloat(f.fs, v.Float(), 64)
+		printFloat(f.fs, f.cs, v.Float(), 64)
 
 	case reflect.Complex64:
-		printComplex(f.fs, v.Complex(), 32)
+		printComplex(f.fs, f.cs, v.Complex(), 32)
 
 	case reflect.Complex128:
-		printComplex(f.fs, v.Complex(), 64)
+		printComplex(f.fs, f.cs, v.Complex(), 64)
 
 	case reflect.Slice:
 		if v.IsNil() {
-			f.fs.Write(nilAngleBytes)
+			f.formatNilCollection(v, typeShown)
 			break
 		}
+
+		// Slices which contain themselves, such as through an interface,
+		// are circular references.
+		if v.Len() > 0 {
+			key := newVisitKey(v)
+			if pd, ok := f.pointers[key]; ok && pd < f.depth {
+				f.fs.Write(circularBytes)
+				break
+			}
+			f.pointers[key] = f.depth
+			defer delete(f.pointers, key)
+		}
 		fallthrough
 
 	case reflect.Array:
 		f.fs.Write(openBracketBytes)
 		f.depth++
 		if (f.cs.MaxDepth != 0) && (f.depth > f.cs.MaxDepth) {
-			f.fs.Write(maxShortBytes)
+			printMaxDepth(f.fs, f.cs, maxShortBytes)
 		} else {
 			numEntries := v.Len()
+			parentPath := f.path
+			head, tail := shownElements(f.cs, numEntries)
+			omitted := numEntries - head - tail
 			for i := 0; i < numEntries; i++ {
 				if i > 0 {
 					f.fs.Write(spaceBytes)
 				}
+				if omitted > 0 && i == head {
+					if !f.formatOmitted(omitted, tail) {
+						break
+					}
+					i += omitted
+				}
 				f.ignoreNextType = true
+				if f.cs.RedactFunc != nil {
+					f.path = indexPath(parentPath, i)
+				}
 				f.format(f.unpackValue(v.Index(i)))
+				f.path = parentPath
 			}
 		}
 		f.depth--
 		f.fs.Write(closeBracketBytes)
 
 	case reflect.String:
-		f.fs.Write([]byte(v.String()))
+		str, truncated := truncateString(f.cs, v.String())
+		if f.cs.ShowRunes && !isASCII(str) {
+			printRunes(f.fs, str)
+		} else {
+			f.fs.Write([]byte(str))
+		}
+		if truncated {
+			printTruncatedLen(f.fs, f.cs, v.Len())
+		}
 
 	case reflect.Interface:
 		// The only time we should get here is for nil interfaces due to
@@ -298,41 +599,66 @@ func (f *formatState) format(v reflect.Value) {
 	case reflect.Map:
 		// nil maps should be indicated as different than empty maps
 		if v.IsNil() {
-			f.fs.Write(nilAngleBytes)
+			f.formatNilCollection(v, typeShown)
 			break
 		}
 
 		f.fs.Write(openMapBytes)
 		f.depth++
 		if (f.cs.MaxDepth != 0) && (f.depth > f.cs.MaxDepth) {
-			f.fs.Write(maxShortBytes)
+			printMaxDepth(f.fs, f.cs, maxShortBytes)
 		} else {
 			keys := v.MapKeys()
 			if f.cs.SortKeys {
 				sortValues(keys, f.cs)
 			}
-			for i, key := range keys {
+			numEntries := len(keys)
+			parentPath := f.path
+			head, tail := shownElements(f.cs, numEntries)
+			omitted := numEntries - head - tail
+			for i := 0; i < numEntries; i++ {
 				if i > 0 {
 					f.fs.Write(spaceBytes)
 				}
+				if omitted > 0 && i == head {
+					if !f.formatOmitted(omitted, tail) {
+						break
+					}
+					i += omitted
+				}
+				key := keys[i]
 				f.ignoreNextType = true
+				if f.cs.RedactFunc != nil {
+					f.path = mapKeyPath(parentPath, key)
+				}
 				f.format(f.unpackValue(key))
 				f.fs.Write(colonBytes)
 				f.ignoreNextType = true
+				if f.cs.RedactFunc != nil {
+					f.path = keyPath(parentPath, key)
+				}
 				f.format(f.unpackValue(v.MapIndex(key)))
+				f.path = parentPath
 			}
 		}
 		f.depth--
 		f.fs.Write(closeMapBytes)
 
 	case reflect.Struct:
+		if !f.cs.DisableContainerTraversal {
+			if values, ok := containerValues(v); ok {
+				f.formatContainer(values)
+				break
+			}
+		}
 		numFields := v.NumField()
 		f.fs.Write(openBraceBytes)
 		f.depth++
 		if (f.cs.MaxDepth != 0) && (f.depth > f.cs.MaxDepth) {
-			f.fs.Write(maxShortBytes)
+			printMaxDepth(f.fs, f.cs, maxShortBytes)
 		} else {
 			vt := v.Type()
+			parentPath := f.path
 			for i := 0; i < numFields; i++ {
 				if i > 0 {
 					f.fs.Write(spaceBytes)
@@ -342,7 +668,16 @@ func (f *formatState) format(v reflect.Value) {
 					f.fs.Write([]byte(vtf.Name))
 					f.fs.Write(colonBytes)
 				}
+				if f.cs.RedactFunc != nil {
+					f.path = fieldPath(parentPath, vtf.Name)
+				}
+				parentBase := f.fieldBase
+				if base := fieldIntegerBase(vtf); base != 0 {
+					f.fieldBase = base
+				}
 				f.format(f.unpackValue(v.Field(i)))
+				f.fieldBase = parentBase
+				f.path = parentPath
 			}
 		}
 		f.depth--
@@ -351,7 +686,14 @@ func (f *formatState) format(v reflect.Value) {
 	case reflect.Uintptr:
 		printHexPtr(f.fs, uintptr(v.Uint()))
 
-	case reflect.UnsafePointer, reflect.Chan, reflect.Func:
+	case reflect.Func:
+		if f.cs.MaxIteratorElements > 0 && !v.IsNil() && iteratorArity(v.Type()) > 0 {
+			f.formatIterator(v)
+			break
+		}
+		printHexPtr(f.fs, v.Pointer())
+
+	case reflect.UnsafePointer, reflect.Chan:
 		printHexPtr(f.fs, v.Pointer())
 
 	// There were not any other types at the time this code was written, but
@@ -369,6 +711,9 @@ func (f *formatState) format(v reflect.Value) {
 // Format satisfies the fmt.Formatter interface. See NewFormatter for usage
 // details.
 func (f *formatState) Format(fs fmt.State, verb rune) {
+	if NoopBuild || outputDisabled(f.cs) {
+		return
+	}
 	f.fs = fs
 
 	// Use standard formatting for verbs that are not v.
@@ -378,22 +723,33 @@ func (f *formatState) Format(fs fmt.State, verb rune) {
 		return
 	}
 
+	// Capture the output so it can be post-processed when requested.
+	if f.cs.needsPostProcessing() {
+		bs := &bufferedState{State: fs}
+		f.fs = bs
+		defer func() {
+			fs.Write([]byte(f.cs.postProcess(bs.buf.String())))
+		}()
+	}
+
 	if f.value == nil {
-		if fs.Flag('#') {
-			fs.Write(interfaceBytes)
+		if f.fs.Flag('#') {
+			f.fs.Write(interfaceBytes)
 		}
-		fs.Write(nilAngleBytes)
+		f.fs.Write(nilAngleBytes)
 		return
 	}
 
-	f.format(reflect.ValueOf(f.value))
+	v := reflect.ValueOf(f.value)
+	f.wrappedNil = f.cs.WarnTypedNil && isNilPointer(v)
+	f.format(v)
 }
 
 // newFormatter is a helper function to consolidate the logic from the various
 // public methods which take varying config states.
 func newFormatter(cs *ConfigState, v interface{}) fmt.Formatter {
 	fs := &formatState{value: v, cs: cs}
-	fs.pointers = make(map[uintptr]int)
+	fs.pointers = make(map[visitKey]int)
 	return fs
 }
 
@@ -415,5 +771,5 @@ use of the custom formatter by calling one of the convenience functions such as
 Printf, Println, or Fprintf.
 */
 func NewFormatter(v interface{}) fmt.Formatter {
-	return newFormatter(&Config, v)
+	return newFormatter(globalConfig(), v)
 }
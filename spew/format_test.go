@@ -1,3 +1,6 @@
+//go:build !spew_noop
+// +build !spew_noop
+
 /*
  * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
  *
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+// funcMap returns the template functions which use the ConfigState returned by
+// the passed function at the time they are invoked.
+func funcMap(config func() *ConfigState) map[string]interface{} {
+	return map[string]interface{}{
+		"sdump": func(a ...interface{}) string {
+			return config().Sdump(a...)
+		},
+		"sdumpCompact": func(a ...interface{}) string {
+			cs, _ := ProfileConfig(ProfileCompact)
+			return cs.Sdump(a...)
+		},
+		"sprint": func(a ...interface{}) string {
+			return config().Sprint(a...)
+		},
+		"sprintf": func(format string, a ...interface{}) string {
+			return config().Sprintf(format, a...)
+		},
+	}
+}
+
+// FuncMap returns functions for use in text/template and html/template
+// templates which format values with the configuration in c, so templates for
+// reports or generated code are able to embed dumps without rendering them in
+// Go code first.  The returned map may be passed to the Funcs method of a
+// template directly.  It provides the following functions:
+//
+//	sdump         formats values exactly the same as Sdump
+//	sdumpCompact  formats values the same as Sdump with the ProfileCompact
+//	              profile
+//	sprint        formats values exactly the same as Sprint
+//	sprintf       formats values exactly the same as Sprintf
+//
+// For example:
+//
+//	tmpl := template.Must(template.New("report").Funcs(cs.FuncMap()).
+//		Parse(`State: {{sdump .State}}`))
+func (c *ConfigState) FuncMap() map[string]interface{} {
+	return funcMap(func() *ConfigState { return c })
+}
+
+// FuncMap returns functions for use in text/template and html/template
+// templates which format values with the global Config.  See
+// ConfigState.FuncMap for details.
+func FuncMap() map[string]interface{} {
+	return funcMap(globalConfig)
+}
@@ -0,0 +1,60 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestFuncMap ensures the template functions format values as intended in
+// both text and HTML templates.
+func TestFuncMap(t *testing.T) {
+	data := struct {
+		N int
+		S []string
+	}{1, []string{"<a>"}}
+	cs := spew.ConfigState{Indent: " ", DisableCapacities: true}
+
+	var buf strings.Builder
+	tmpl := template.Must(template.New("text").Funcs(cs.FuncMap()).Parse(
+		`{{sdump .N}}{{sprint .S}} {{sprintf "%+v" .N}} {{sdumpCompact .S}}`))
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	want := "(int) 1\n[<a>] 1 ([]string) (len=1) {\n (string) (len=3) \"<a>\"\n}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("text template mismatch:\n got: %q\nwant: %q", got, want)
+	}
+
+	buf.Reset()
+	htmpl := htmltemplate.Must(htmltemplate.New("html").Funcs(spew.FuncMap()).
+		Parse(`<pre>{{sprint .S}}</pre>`))
+	if err := htmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, want := buf.String(), "<pre>[&lt;a&gt;]</pre>"; got != want {
+		t.Errorf("html template mismatch:\n got: %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// Option is a functional option which modifies the configuration used by the
+// generic DumpT family of functions.  The options are applied in order to a
+// copy of the global Config, so they never affect other callers.
+type Option func(cs *ConfigState)
+
+// WithConfig returns an Option which replaces the configuration being built
+// with a copy of the passed ConfigState.  It is typically the first option.
+func WithConfig(c *ConfigState) Option {
+	return func(cs *ConfigState) {
+		*cs = *c
+	}
+}
+
+// WithIndent returns an Option which sets the Indent configuration option.
+func WithIndent(indent string) Option {
+	return func(cs *ConfigState) {
+		cs.Indent = indent
+	}
+}
+
+// WithMaxDepth returns an Option which sets the MaxDepth configuration option.
+func WithMaxDepth(depth int) Option {
+	return func(cs *ConfigState) {
+		cs.MaxDepth = depth
+	}
+}
+
+// WithSortKeys returns an Option which sets the SortKeys configuration option.
+func WithSortKeys() Option {
+	return func(cs *ConfigState) {
+		cs.SortKeys = true
+	}
+}
+
+// WithoutMethods returns an Option which sets the DisableMethods
+// configuration option.
+func WithoutMethods() Option {
+	return func(cs *ConfigState) {
+		cs.DisableMethods = true
+	}
+}
+
+// WithoutPointerAddresses returns an Option which sets the
+// DisablePointerAddresses configuration option.
+func WithoutPointerAddresses() Option {
+	return func(cs *ConfigState) {
+		cs.DisablePointerAddresses = true
+	}
+}
+
+// WithoutCapacities returns an Option which sets the DisableCapacities
+// configuration option.
+func WithoutCapacities() Option {
+	return func(cs *ConfigState) {
+		cs.DisableCapacities = true
+	}
+}
+
+// typeOf returns the reflect.Type of the type parameter T, which, unlike
+// reflect.TypeOf, works for interface types as well.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// typeMatches returns whether or not the passed value should be handled by a
+// handler registered for the passed type.  Handlers for interface types apply
+// to every type which implements the interface.
+func typeMatches(t reflect.Type, v reflect.Value) bool {
+	if t.Kind() == reflect.Interface {
+		return v.Kind() != reflect.Interface && v.Type().Implements(t)
+	}
+	return v.Type() == t
+}
+
+// WithTransform returns an Option which registers a typed function that is
+// called with every value of type T, or every value that implements T when T
+// is an interface, and whose result is displayed in its place.  It is layered
+// on top of the TransformFunc configuration option, so any previously
+// configured function still applies to other types.
+//
+// Values which can't be converted to T, such as unexported struct fields when
+// the unsafe package is not available, are displayed normally.
+func WithTransform[T any](fn func(v T) interface{}) Option {
+	t := typeOf[T]()
+	return func(cs *ConfigState) {
+		prev := cs.TransformFunc
+		cs.TransformFunc = func(v reflect.Value) (reflect.Value, bool) {
+			if typeMatches(t, v) && v.CanInterface() {
+				return reflect.ValueOf(fn(v.Interface().(T))), true
+			}
+			if prev != nil {
+				return prev(v)
+			}
+			return reflect.Value{}, false
+		}
+	}
+}
+
+// WithFormatter returns an Option which registers a typed function that
+// produces the inline representation of values of type T for the custom
+// formatter.  It adds an entry to a copy of the TypeFormatters configuration
+// option, so T must be a concrete type.
+func WithFormatter[T any](fn func(fs fmt.State, v T)) Option {
+	t := typeOf[T]()
+	return func(cs *ConfigState) {
+		formatters := make(map[reflect.Type]func(fmt.State, reflect.Value),
+			len(cs.TypeFormatters)+1)
+		for k, f := range cs.TypeFormatters {
+			formatters[k] = f
+		}
+		formatters[t] = func(fs fmt.State, v reflect.Value) {
+			if !v.CanInterface() {
+				fmt.Fprint(fs, v)
+				return
+			}
+			fn(fs, v.Interface().(T))
+		}
+		cs.TypeFormatters = formatters
+	}
+}
+
+// configWith returns a copy of the global Config with the passed options
+// applied.
+func configWith(opts []Option) *ConfigState {
+	cs := *globalConfig()
+	for _, opt := range opts {
+		opt(&cs)
+	}
+	return &cs
+}
+
+// FdumpT formats and displays the passed value to io.Writer w exactly the same
+// as Fdump, using a copy of the global Config modified by the passed options.
+func FdumpT[T any](w io.Writer, v T, opts ...Option) {
+	fdump(configWith(opts), w, v)
+}
+
+// SdumpT returns a string with the passed value formatted exactly the same as
+// DumpT.
+func SdumpT[T any](v T, opts ...Option) string {
+	var buf bytes.Buffer
+	fdump(configWith(opts), &buf, v)
+	return buf.String()
+}
+
+// DumpT displays the passed value to standard out exactly the same as Dump,
+// using a copy of the global Config modified by the passed options.  Typed
+// handlers may be registered with WithTransform and WithFormatter without the
+// need for interface{} assertions or reflect.Type lookups at the call site:
+//
+//	spew.DumpT(req, spew.WithMaxDepth(2),
+//		spew.WithTransform(func(t time.Time) interface{} {
+//			return t.Format(time.RFC3339)
+//		}))
+func DumpT[T any](v T, opts ...Option) {
+	fdump(configWith(opts), os.Stdout, v)
+}
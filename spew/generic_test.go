@@ -0,0 +1,98 @@
+//go:build go1.18 && !spew_noop
+// +build go1.18,!spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// genericTester is used to test typed handlers registered via the options
+// for the generic functions.
+type genericTester struct {
+	Name  string
+	Count int
+	Err   error
+}
+
+// TestDumpT ensures the generic dump functions apply their options and typed
+// handlers as intended.
+func TestDumpT(t *testing.T) {
+	v := genericTester{Name: "a", Count: 2, Err: customError(3)}
+
+	tests := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{"no options", func() string {
+			return spew.SdumpT(1)
+		}, "(int) 1\n"},
+		{"indent", func() string {
+			return spew.SdumpT(v, spew.WithIndent("\t"), spew.WithoutMethods())
+		}, "(spew_test.genericTester) {\n" +
+			"\tName: (string) (len=1) \"a\",\n" +
+			"\tCount: (int) 2,\n" +
+			"\tErr: (spew_test.customError) 3\n}\n"},
+		{"concrete transform", func() string {
+			return spew.SdumpT(v, spew.WithTransform(func(n int) interface{} {
+				return fmt.Sprintf("#%d", n)
+			}))
+		}, "(spew_test.genericTester) {\n" +
+			" Name: (string) (len=1) \"a\",\n" +
+			" Count: (string) (len=2) \"#2\",\n" +
+			" Err: (spew_test.customError) error: 3\n}\n"},
+		{"interface transform", func() string {
+			return spew.SdumpT(v, spew.WithTransform(func(err error) interface{} {
+				return "failed"
+			}))
+		}, "(spew_test.genericTester) {\n" +
+			" Name: (string) (len=1) \"a\",\n" +
+			" Count: (int) 2,\n" +
+			" Err: (string) (len=6) \"failed\"\n}\n"},
+		{"config", func() string {
+			cs := &spew.ConfigState{Indent: "-", MaxDepth: 1}
+			return spew.SdumpT([][]int{{1}}, spew.WithConfig(cs))
+		}, "([][]int) (len=1 cap=1) {\n" +
+			"-([]int) (len=1 cap=1) {\n--<max depth reached>\n-}\n}\n"},
+		{"formatter", func() string {
+			cs := spew.ConfigState{}
+			spew.WithFormatter(func(fs fmt.State, n int) {
+				fmt.Fprintf(fs, "<%d>", n)
+			})(&cs)
+			return cs.Sprintf("%v", []int{1, 2})
+		}, "[<1> <2>]"},
+	}
+
+	for _, test := range tests {
+		if got := test.got(); got != test.want {
+			t.Errorf("%s:\n got: %q\nwant: %q", test.name, got, test.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	spew.FdumpT(&buf, "x", spew.WithSortKeys())
+	if got, want := buf.String(), "(string) (len=1) \"x\"\n"; got != want {
+		t.Errorf("FdumpT:\n got: %q\nwant: %q", got, want)
+	}
+}
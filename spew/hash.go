@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"hash/fnv"
+	"reflect"
+)
+
+// HashOptions specifies which parts of a value DeepHash ignores.  The zero
+// value hashes everything Dump would display.
+type HashOptions struct {
+	// IgnoreAddresses specifies that the addresses of pointers, functions,
+	// channels, and unsafe pointers do not affect the hash, so equal values
+	// at different locations in memory, or in different processes, hash
+	// the same.  Pointers are always followed regardless.
+	IgnoreAddresses bool
+
+	// IgnoreUnexported specifies that unexported struct fields do not
+	// affect the hash.
+	IgnoreUnexported bool
+
+	// IgnorePaths are the paths of values which do not affect the hash,
+	// such as timestamps or request identifiers.  They are in the same
+	// form as the paths passed to the RedactFunc option, for example
+	// "Users[2].LastSeen" or "Env[HOME]".
+	IgnorePaths []string
+}
+
+// DeepHash returns a stable 64-bit hash of the passed value which is computed
+// by walking it the same way as NewBaseline without rendering it as a whole.
+// Values which Dump would display identically, other than any parts which are
+// ignored according to opts, hash the same, which is useful for deduplicating
+// dumps, caching, and checking whether or not a value changed.  A nil opts
+// hashes everything Dump would display.
+//
+// Map keys are always sorted, so the order entries were inserted does not
+// affect the hash.  The options of c which affect how leaf values are
+// formatted, such as MaxStringLength, affect the hash as well.
+func (c *ConfigState) DeepHash(a interface{}, opts *HashOptions) uint64 {
+	if opts == nil {
+		opts = &HashOptions{}
+	}
+	h := fnv.New64a()
+	l := newLeafWalker(c)
+	l.ignoreUnexported = opts.IgnoreUnexported
+	l.pointerAddrs = !opts.IgnoreAddresses && !c.DisablePointerAddresses
+	l.hideAddrs = opts.IgnoreAddresses
+	if len(opts.IgnorePaths) > 0 {
+		l.ignorePaths = make(map[string]bool, len(opts.IgnorePaths))
+		for _, path := range opts.IgnorePaths {
+			l.ignorePaths[path] = true
+		}
+	}
+	l.visit = func(path, text string) {
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		h.Write([]byte(text))
+		h.Write([]byte{0})
+	}
+	l.walk("", reflect.ValueOf(a))
+	return h.Sum64()
+}
+
+// DeepHash returns a stable 64-bit hash of the passed value using the global
+// Config.  See ConfigState.DeepHash for details.
+func DeepHash(a interface{}, opts *HashOptions) uint64 {
+	return globalConfig().DeepHash(a, opts)
+}
@@ -0,0 +1,113 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// hashUser is used to test deep hashes.
+type hashUser struct {
+	Name     string
+	Tags     map[string]int
+	Friend   *hashUser
+	OnChange func()
+	lastSeen int
+}
+
+// TestDeepHash ensures deep hashes are stable and ignore the requested parts
+// of values.
+func TestDeepHash(t *testing.T) {
+	cs := spew.ConfigState{Indent: " "}
+	newUser := func() *hashUser {
+		return &hashUser{Name: "a", Tags: map[string]int{"x": 1, "y": 2},
+			Friend: &hashUser{Name: "b"}}
+	}
+	u1, u2 := newUser(), newUser()
+	u1.Friend.Friend = u1
+	u2.Friend.Friend = u2
+
+	noAddrs := &spew.HashOptions{IgnoreAddresses: true}
+	if cs.DeepHash(u1, noAddrs) != cs.DeepHash(u2, noAddrs) {
+		t.Error("equal values at different addresses hash differently")
+	}
+	if cs.DeepHash(u1, nil) == cs.DeepHash(u2, nil) {
+		t.Error("addresses do not affect the hash")
+	}
+	if cs.DeepHash(u1, nil) != cs.DeepHash(u1, nil) {
+		t.Error("hash is not stable")
+	}
+
+	tests := []struct {
+		name   string
+		modify func(u *hashUser)
+		opts   *spew.HashOptions
+		same   bool
+	}{
+		{"field", func(u *hashUser) { u.Name = "c" }, noAddrs, false},
+		{"map entry", func(u *hashUser) { u.Tags["z"] = 3 }, noAddrs, false},
+		{"nested", func(u *hashUser) { u.Friend.Name = "c" }, noAddrs, false},
+		{"func", func(u *hashUser) { u.OnChange = func() {} }, noAddrs, false},
+		{"unexported", func(u *hashUser) { u.lastSeen = 1 }, noAddrs, false},
+		{"ignored unexported", func(u *hashUser) { u.lastSeen = 1 },
+			&spew.HashOptions{IgnoreAddresses: true, IgnoreUnexported: true},
+			true},
+		{"ignored paths", func(u *hashUser) {
+			u.Friend.Name = "c"
+			u.Tags["x"] = 5
+		}, &spew.HashOptions{IgnoreAddresses: true,
+			IgnorePaths: []string{"Friend.Name", "Tags[x]"}}, true},
+	}
+	for _, test := range tests {
+		u := newUser()
+		before := cs.DeepHash(u, test.opts)
+		test.modify(u)
+		if same := cs.DeepHash(u, test.opts) == before; same != test.same {
+			t.Errorf("%s: got same hash %v, want %v", test.name, same,
+				test.same)
+		}
+	}
+}
+
+// TestDeepHashDumpCallbacks ensures deep hashes are able to be computed with
+// configurations which observe or bound whole dumps, and that those options
+// do not affect the hash.
+func TestDeepHashDumpCallbacks(t *testing.T) {
+	u := &hashUser{Name: "a", Tags: map[string]int{"x": 1}}
+	var cs spew.ConfigState
+	want := cs.DeepHash(u, nil)
+
+	tests := []struct {
+		name string
+		cs   spew.ConfigState
+	}{
+		{"NodeFunc", spew.ConfigState{NodeFunc: func(spew.Node) {}}},
+		{"ProgressFunc", spew.ConfigState{ProgressInterval: 1,
+			ProgressFunc: func(spew.Progress) bool { return true }}},
+		{"MaxNodes", spew.ConfigState{MaxNodes: 1}},
+	}
+	for _, test := range tests {
+		if got := test.cs.DeepHash(u, nil); got != want {
+			t.Errorf("%s: got %x, want %x", test.name, got, want)
+		}
+	}
+}
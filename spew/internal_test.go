@@ -1,3 +1,6 @@
+//go:build !spew_noop
+// +build !spew_noop
+
 /*
  * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
  *
@@ -82,3 +85,53 @@ func TestInvalidReflectValue(t *testing.T) {
 func SortValues(values []reflect.Value, cs *ConfigState) {
 	sortValues(values, cs)
 }
+
+// TestTypeIndex ensures the type index counts every recorded value, only
+// counts the bytes of outermost values, and orders the types by their bytes
+// and then their names.
+func TestTypeIndex(t *testing.T) {
+	type record struct {
+		typ       reflect.Type
+		n         int64
+		outermost bool
+	}
+	intType, strType := reflect.TypeOf(0), reflect.TypeOf("")
+	sliceType := reflect.TypeOf([]int(nil))
+
+	tests := []struct {
+		name    string
+		cs      ConfigState
+		records []record
+		want    string
+	}{
+		{"empty", ConfigState{Indent: " "}, nil, "Type index:\n"},
+		{"by bytes", ConfigState{Indent: " "}, []record{
+			{intType, 7, true}, {strType, 20, true}, {intType, 7, true},
+		}, "Type index:\n (string) count=1 bytes=20\n" +
+			" (int) count=2 bytes=14\n"},
+		{"ties by name", ConfigState{Indent: " "}, []record{
+			{strType, 5, true}, {intType, 5, true},
+		}, "Type index:\n (int) count=1 bytes=5\n" +
+			" (string) count=1 bytes=5\n"},
+		{"nested", ConfigState{Indent: " "}, []record{
+			{sliceType, 30, true}, {sliceType, 10, false},
+			{intType, 7, true},
+		}, "Type index:\n ([]int) count=2 bytes=30\n" +
+			" (int) count=1 bytes=7\n"},
+		{"digit separator", ConfigState{Indent: "-", DigitSeparator: ","},
+			[]record{{intType, 1234567, true}},
+			"Type index:\n-(int) count=1 bytes=1,234,567\n"},
+	}
+
+	for _, test := range tests {
+		var ti typeIndex
+		for _, r := range test.records {
+			ti.record(r.typ, r.n, r.outermost)
+		}
+		var buf bytes.Buffer
+		ti.write(&buf, &test.cs)
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s:\n got: %q\nwant: %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+)
+
+// jsonNode is a single node of a JSON Lines dump.
+type jsonNode struct {
+	Path  string      `json:"path"`
+	Type  string      `json:"type"`
+	Kind  string      `json:"kind"`
+	Value interface{} `json:"value,omitempty"`
+	Len   *int        `json:"len,omitempty"`
+	Depth int         `json:"depth"`
+}
+
+// jsonLinesState contains information about the state of a JSON Lines dump.
+type jsonLinesState struct {
+	cs       *ConfigState
+	enc      *json.Encoder
+	pointers map[uintptr]bool
+	err      error
+}
+
+// emit writes the passed node unless a previous write failed.
+func (j *jsonLinesState) emit(node jsonNode) {
+	if j.err == nil {
+		j.err = j.enc.Encode(node)
+	}
+}
+
+// methodString returns the result of the error or Stringer interface of the
+// passed value when it implements one and methods are enabled.
+func (j *jsonLinesState) methodString(v reflect.Value) (s string, found bool) {
+	if j.cs.DisableMethods {
+		return "", false
+	}
+	iv, ok := interfaceValue(j.cs, v)
+	if !ok || !iv.CanInterface() {
+		return "", false
+	}
+
+	defer func() {
+		if err := recover(); err != nil {
+			s = fmt.Sprintf("%s%v%s", panicBytes, err, closeParenBytes)
+			found = true
+		}
+	}()
+	return methodText(j.cs, iv.Interface())
+}
+
+// walk emits the node for the passed value located at path followed by the
+// nodes for the values it contains.
+func (j *jsonLinesState) walk(path string, v reflect.Value, depth int) {
+	if j.err != nil {
+		return
+	}
+
+	// Follow pointers and interfaces while detecting circular references.
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			j.emit(jsonNode{Path: path, Type: typeString(j.cs, v.Type()),
+				Kind: v.Kind().String(), Depth: depth})
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			if s, ok := j.methodString(v); ok {
+				j.emit(jsonNode{Path: path, Type: typeString(j.cs, v.Type()),
+					Kind: v.Kind().String(), Value: s, Depth: depth})
+				return
+			}
+			addr := v.Pointer()
+			if j.pointers[addr] {
+				j.emit(jsonNode{Path: path, Type: typeString(j.cs, v.Type()),
+					Kind: v.Kind().String(), Value: string(circularBytes),
+					Depth: depth})
+				return
+			}
+			j.pointers[addr] = true
+			defer delete(j.pointers, addr)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		j.emit(jsonNode{Path: path, Kind: v.Kind().String(), Depth: depth})
+		return
+	}
+
+	node := jsonNode{Path: path, Type: typeString(j.cs, v.Type()),
+		Kind: v.Kind().String(), Depth: depth}
+	if s, ok := j.methodString(v); ok {
+		node.Value = s
+		j.emit(node)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		node.Value = v.Bool()
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		node.Value = v.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		node.Value = v.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		// JSON is unable to represent NaN and infinities as numbers.
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			node.Value = fmt.Sprint(f)
+		} else {
+			node.Value = f
+		}
+
+	case reflect.Complex64, reflect.Complex128:
+		node.Value = fmt.Sprint(v.Complex())
+
+	case reflect.String:
+		node.Value = v.String()
+
+	case reflect.Uintptr:
+		node.Value = fmt.Sprintf("%#x", v.Uint())
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if !v.IsNil() {
+			node.Value = fmt.Sprintf("%#x", v.Pointer())
+		}
+
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		j.walkComposite(node, v, depth)
+		return
+	}
+	j.emit(node)
+}
+
+// walkComposite emits the node for the passed slice, array, map, or struct
+// followed by the nodes for its elements.  The paths of elements are those of
+// the RedactFunc option.  Byte slices and arrays are emitted as a single node
+// with the bytes in hexadecimal since they are typically opaque data.
+func (j *jsonLinesState) walkComposite(node jsonNode, v reflect.Value, depth int) {
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil() {
+		j.emit(node)
+		return
+	}
+	var n int
+	if v.Kind() != reflect.Struct {
+		n = v.Len()
+		node.Len = &n
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) &&
+		v.Type().Elem().Kind() == reflect.Uint8 {
+		buf := make([]byte, n)
+		for i := range buf {
+			buf[i] = byte(v.Index(i).Uint())
+		}
+		node.Value = fmt.Sprintf("%x", buf)
+		j.emit(node)
+		return
+	}
+	if j.cs.MaxDepth != 0 && depth >= j.cs.MaxDepth {
+		node.Value = string(truncationMarker(j.cs, maxShortBytes, "MaxDepth",
+			j.cs.MaxDepth))
+		j.emit(node)
+		return
+	}
+	j.emit(node)
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < n; i++ {
+			j.walk(indexPath(node.Path, i), v.Index(i), depth+1)
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		if j.cs.SortKeys {
+			sortValues(keys, j.cs)
+		}
+		for _, k := range keys {
+			j.walk(keyPath(node.Path, k), v.MapIndex(k), depth+1)
+		}
+
+	case reflect.Struct:
+		for _, sf := range structFields(j.cs, v) {
+			j.walk(fieldPath(node.Path, sf.path), sf.value, depth+1)
+		}
+	}
+}
+
+// FdumpJSONLines writes the passed arguments to io.Writer w as a stream of
+// JSON Lines, with one JSON object per value visited:
+//
+//	{"path":"Items[0].Name","type":"string","kind":"string","value":"a","depth":2}
+//
+// Each object contains the path of the value, which is in the same form as
+// the paths passed to the RedactFunc option and is empty for the arguments
+// themselves, its type, kind, and depth.  Scalar values, and the text of
+// values which implement the error or Stringer interfaces, are included as
+// the value, while slices, arrays, and maps include their length and are
+// followed by the objects for their elements.  Pointers and interfaces are
+// followed.  Since each value is written as soon as it is visited, tools are
+// able to filter and aggregate enormous dumps without materializing all of
+// their text.
+//
+// The MaxDepth, DisableMethods, DisablePointerMethods, SortKeys, SpewKeys,
+// and FlattenEmbedded options are honored.  The first error encountered
+// while writing is returned.  Nothing is written while output is disabled.
+func (c *ConfigState) FdumpJSONLines(w io.Writer, a ...interface{}) error {
+	if NoopBuild || outputDisabled(c) {
+		return nil
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, arg := range a {
+		j := jsonLinesState{cs: c, enc: enc, pointers: make(map[uintptr]bool)}
+		j.walk("", reflect.ValueOf(arg), 0)
+		if j.err != nil {
+			return j.err
+		}
+	}
+	return nil
+}
+
+// DumpJSONLines writes the passed arguments to standard out as a stream of
+// JSON Lines.  See FdumpJSONLines for details.
+func (c *ConfigState) DumpJSONLines(a ...interface{}) error {
+	return c.FdumpJSONLines(os.Stdout, a...)
+}
+
+// FdumpJSONLines writes the passed arguments to io.Writer w as a stream of
+// JSON Lines using the global Config.  See ConfigState.FdumpJSONLines for
+// details.
+func FdumpJSONLines(w io.Writer, a ...interface{}) error {
+	return globalConfig().FdumpJSONLines(w, a...)
+}
+
+// DumpJSONLines writes the passed arguments to standard out as a stream of
+// JSON Lines using the global Config.  See ConfigState.FdumpJSONLines for
+// details.
+func DumpJSONLines(a ...interface{}) error {
+	return globalConfig().FdumpJSONLines(os.Stdout, a...)
+}
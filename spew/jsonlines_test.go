@@ -0,0 +1,101 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// failingWriter is an io.Writer which always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// TestDumpJSONLines ensures values are written as a stream of JSON Lines as
+// intended.
+func TestDumpJSONLines(t *testing.T) {
+	type item struct {
+		Name  string
+		Score float64
+	}
+	type jsonTester struct {
+		Items []item
+		Tags  map[string]int
+		Raw   []byte
+		Err   error
+		Next  *jsonTester
+	}
+	v := &jsonTester{
+		Items: []item{{"a", math.Inf(1)}},
+		Tags:  map[string]int{"y": 2, "x": 1},
+		Raw:   []byte{0xde, 0xad},
+		Err:   customError(5),
+	}
+	v.Next = v
+
+	cfg := spew.ConfigState{SortKeys: true}
+	var buf bytes.Buffer
+	if err := cfg.FdumpJSONLines(&buf, v, 1); err != nil {
+		t.Fatalf("FdumpJSONLines: %v", err)
+	}
+	want := `{"path":"","type":"spew_test.jsonTester","kind":"struct","depth":0}
+{"path":"Items","type":"[]spew_test.item","kind":"slice","len":1,"depth":1}
+{"path":"Items[0]","type":"spew_test.item","kind":"struct","depth":2}
+{"path":"Items[0].Name","type":"string","kind":"string","value":"a","depth":3}
+{"path":"Items[0].Score","type":"float64","kind":"float64","value":"+Inf","depth":3}
+{"path":"Tags","type":"map[string]int","kind":"map","len":2,"depth":1}
+{"path":"Tags[x]","type":"int","kind":"int","value":1,"depth":2}
+{"path":"Tags[y]","type":"int","kind":"int","value":2,"depth":2}
+{"path":"Raw","type":"[]uint8","kind":"slice","value":"dead","len":2,"depth":1}
+{"path":"Err","type":"spew_test.customError","kind":"int","value":"error: 5","depth":1}
+{"path":"Next","type":"*spew_test.jsonTester","kind":"ptr","value":"<already shown>","depth":1}
+{"path":"","type":"int","kind":"int","value":1,"depth":0}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("JSON Lines mismatch:\n got: %s\nwant: %s", got, want)
+	}
+
+	// The first error while writing is returned.
+	if err := cfg.FdumpJSONLines(failingWriter{}, v); err == nil {
+		t.Errorf("FdumpJSONLines: expected error")
+	}
+
+	// Nothing is written while output is disabled.
+	buf.Reset()
+	cfg.Disabled = true
+	if err := cfg.FdumpJSONLines(&buf, v); err != nil || buf.Len() != 0 {
+		t.Errorf("FdumpJSONLines with Disabled: got %q, %v", buf.String(),
+			err)
+	}
+	cfg.Disabled = false
+	defer spew.Enable()
+	spew.Disable()
+	if err := cfg.FdumpJSONLines(&buf, v); err != nil || buf.Len() != 0 {
+		t.Errorf("FdumpJSONLines while disabled: got %q, %v", buf.String(),
+			err)
+	}
+}
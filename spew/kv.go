@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// badKey is the key used for a trailing value without a key, which matches
+// the convention of the log/slog package.
+const badKey = "!BADKEY"
+
+// kvKey returns the passed key with the characters which would make it
+// ambiguous in a key=value pair replaced by underscores.
+func kvKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// kvValue returns the passed value quoted when it is empty or contains
+// characters which would make it ambiguous in a key=value pair.
+func kvValue(value string) string {
+	needsQuotes := value == "" || strings.IndexFunc(value, func(r rune) bool {
+		return r == '=' || r == '"' || !unicode.IsPrint(r) || unicode.IsSpace(r)
+	}) >= 0
+	if needsQuotes {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// KV returns a single line of key=value pairs for the passed alternating keys
+// and values, which is the logfmt style parsed by many log pipelines:
+//
+//	spew.KV("user", u, "order", o)
+//
+// produces output such as:
+//
+//	user="{alice 7}" order="{42 [<*>{a 1}]}"
+//
+// The values are formatted the same as the %v verb of the custom formatter,
+// so pointers are followed, and quoted when they contain
+// spaces, equals signs, quotes, or characters which are not printable, such
+// as newlines.  Keys which are not strings are formatted with fmt.Sprint, and
+// the characters of keys which would be quoted are replaced by underscores.
+// A trailing value without a key uses the key !BADKEY, the same as the
+// log/slog package.
+func (c *ConfigState) KV(keysAndValues ...interface{}) string {
+	if NoopBuild || outputDisabled(c) {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i > 0 {
+			buf.Write(spaceBytes)
+		}
+		key, value := badKey, keysAndValues[i]
+		if i+1 < len(keysAndValues) {
+			key, value = fmt.Sprint(keysAndValues[i]), keysAndValues[i+1]
+		}
+		buf.WriteString(kvKey(key))
+		buf.WriteByte('=')
+		buf.WriteString(kvValue(fmt.Sprintf("%v", newFormatter(c, value))))
+	}
+	return buf.String()
+}
+
+// KV returns a single line of key=value pairs for the passed alternating keys
+// and values using the global Config.  See ConfigState.KV for details.
+func KV(keysAndValues ...interface{}) string {
+	return globalConfig().KV(keysAndValues...)
+}
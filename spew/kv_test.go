@@ -0,0 +1,58 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestKV ensures KV produces a single line of key=value pairs with values
+// quoted when needed.
+func TestKV(t *testing.T) {
+	type item struct {
+		SKU string
+		Qty int
+	}
+	type order struct {
+		ID    int
+		Items []*item
+	}
+	o := order{42, []*item{{"a", 1}}}
+	cs := spew.ConfigState{Indent: " "}
+
+	tests := []struct {
+		in   []interface{}
+		want string
+	}{
+		{[]interface{}{"order", o}, `order="{42 [<*>{a 1}]}"`},
+		{[]interface{}{"n", 5, "ok", true}, "n=5 ok=true"},
+		{[]interface{}{"s", "two\nlines", "empty", ""}, `s="two\nlines" empty=""`},
+		{[]interface{}{"a key", 1, 2, "x=y"}, `a_key=1 2="x=y"`},
+		{[]interface{}{"k", nil, "trailing"}, "k=<nil> !BADKEY=trailing"},
+		{nil, ""},
+	}
+	for i, test := range tests {
+		if got := cs.KV(test.in...); got != test.want {
+			t.Errorf("#%d: got %s, want %s", i, got, test.want)
+		}
+	}
+}
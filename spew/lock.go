@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErrConfigMutated is the error reported when the global Config is modified
+// after it has been locked with LockConfig or LockConfigFunc.
+var ErrConfigMutated = errors.New("spew: global Config modified after it was locked")
+
+var (
+	// configLockMtx protects the lock state of the global Config.
+	configLockMtx sync.Mutex
+
+	// lockedConfig is a private copy of the global Config when it was locked,
+	// which the top-level functions use in its place while locked.  It is nil
+	// when the global Config is not locked.
+	lockedConfig *ConfigState
+
+	// observedConfig is the global Config as of the last time it was checked
+	// for modifications, so each modification is only reported once.
+	observedConfig ConfigState
+
+	// configWarnFunc is the callback invoked when the global Config is
+	// found to be modified.  The modification is ignored when it is set,
+	// and a panic is raised otherwise.
+	configWarnFunc func(err error)
+)
+
+// LockConfig locks the global Config so that subsequent modifications of it
+// cause a panic with ErrConfigMutated.  This prevents libraries from silently
+// changing the appearance of the output of the top-level functions.  Since
+// Config is a plain variable, modifications are detected the next time one of
+// the top-level functions is called rather than when they happen.
+func LockConfig() {
+	LockConfigFunc(nil)
+}
+
+// LockConfigFunc locks the global Config in the same way as LockConfig, except
+// that modifications are ignored by the top-level functions, which continue
+// to use the configuration as it was when locked, and reported to the passed
+// callback with ErrConfigMutated instead of causing a panic.  A nil callback
+// is equivalent to calling LockConfig.
+func LockConfigFunc(warn func(err error)) {
+	configLockMtx.Lock()
+	cs := Config
+	lockedConfig = &cs
+	observedConfig = Config
+	configWarnFunc = warn
+	configLockMtx.Unlock()
+}
+
+// UnlockConfig undoes the effect of LockConfig and LockConfigFunc.  It is
+// primarily intended for tests.
+func UnlockConfig() {
+	configLockMtx.Lock()
+	lockedConfig = nil
+	observedConfig = ConfigState{}
+	configWarnFunc = nil
+	configLockMtx.Unlock()
+}
+
+// globalConfig returns the configuration used by the top-level functions,
+// which is the global Config unless it is locked, after ensuring it has not
+// been modified while locked.
+func globalConfig() *ConfigState {
+	configLockMtx.Lock()
+	locked := lockedConfig
+	if locked == nil {
+		configLockMtx.Unlock()
+		return &Config
+	}
+	if configEqual(&Config, &observedConfig) {
+		configLockMtx.Unlock()
+		return locked
+	}
+
+	warn := configWarnFunc
+	if warn == nil {
+		configLockMtx.Unlock()
+		panic(ErrConfigMutated)
+	}
+	observedConfig = Config
+	configLockMtx.Unlock()
+	warn(ErrConfigMutated)
+	return locked
+}
+
+// configEqual returns whether or not the passed configurations are the same.
+// Options of reference types, such as functions, maps, and slices, are
+// compared by identity rather than by their contents so the check remains
+// cheap enough to perform on every call of the top-level functions.
+func configEqual(a, b *ConfigState) bool {
+	av, bv := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for i := 0; i < av.NumField(); i++ {
+		af, bf := av.Field(i), bv.Field(i)
+		var equal bool
+		switch af.Kind() {
+		case reflect.Bool:
+			equal = af.Bool() == bf.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			equal = af.Int() == bf.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64, reflect.Uintptr:
+			equal = af.Uint() == bf.Uint()
+		case reflect.Float32, reflect.Float64:
+			equal = af.Float() == bf.Float()
+		case reflect.String:
+			equal = af.String() == bf.String()
+		case reflect.Func, reflect.Map, reflect.Ptr:
+			equal = af.Pointer() == bf.Pointer()
+		case reflect.Slice:
+			equal = af.Pointer() == bf.Pointer() && af.Len() == bf.Len()
+		default:
+			equal = reflect.DeepEqual(af.Interface(), bf.Interface())
+		}
+		if !equal {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestLockConfig ensures modifications of the global Config after it is locked
+// are detected as intended.
+func TestLockConfig(t *testing.T) {
+	orig := spew.Config
+	defer func() {
+		spew.UnlockConfig()
+		spew.Config = orig
+	}()
+
+	// Unmodified configurations may be used as usual.
+	spew.LockConfig()
+	if got, want := spew.Sdump(1), "(int) 1\n"; got != want {
+		t.Errorf("Sdump:\n got: %q\nwant: %q", got, want)
+	}
+
+	// Modifications cause a panic.
+	spew.Config.Indent = "\t"
+	func() {
+		defer func() {
+			if err := recover(); err != spew.ErrConfigMutated {
+				t.Errorf("unexpected recover value: %v", err)
+			}
+		}()
+		spew.Sdump(1)
+		t.Errorf("modification did not panic")
+	}()
+
+	// Modifications are ignored and reported to the callback once, and the
+	// global Config is left as modified.
+	var warnings int
+	spew.Config = orig
+	spew.LockConfigFunc(func(err error) { warnings++ })
+	spew.Config.Indent = "\t"
+	spew.Config.TransformFunc = nil
+	want := "([]int) (len=1 cap=1) {\n (int) 1\n}\n"
+	if got := spew.Sdump([]int{1}); got != want {
+		t.Errorf("Sdump:\n got: %q\nwant: %q", got, want)
+	}
+	if warnings != 1 || spew.Config.Indent != "\t" {
+		t.Errorf("unexpected warnings %d, Indent %q", warnings,
+			spew.Config.Indent)
+	}
+	spew.Sdump([]int{1})
+	if warnings != 1 {
+		t.Errorf("unexpected warnings: %d", warnings)
+	}
+	spew.Config.Indent = "  "
+	spew.Sdump([]int{1})
+	if warnings != 2 {
+		t.Errorf("unexpected warnings: %d", warnings)
+	}
+
+	// The locked configuration decides whether or not output is disabled
+	// for the top-level print functions as well.
+	spew.Config.Disabled = true
+	if got := spew.Sprint(1); got != "1" {
+		t.Errorf("Sprint with the Disabled option modified: got %q, "+
+			"want %q", got, "1")
+	}
+	spew.Config.Disabled = false
+
+	// The configuration may be modified once unlocked.
+	spew.UnlockConfig()
+	spew.Config.Indent = "\t"
+	if got, want := spew.Sdump([]int{1}), "([]int) (len=1 cap=1) {\n\t(int) 1\n}\n"; got != want {
+		t.Errorf("Sdump:\n got: %q\nwant: %q", got, want)
+	}
+}
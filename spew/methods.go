@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+)
+
+// fdumpMethods outputs the method set of each of the passed values to
+// io.Writer w.
+func fdumpMethods(cs *ConfigState, w io.Writer, a ...interface{}) {
+	if NoopBuild || outputDisabled(cs) {
+		return
+	}
+
+	for _, arg := range a {
+		if arg == nil {
+			w.Write(interfaceBytes)
+			w.Write(spaceBytes)
+			w.Write(nilAngleBytes)
+			w.Write(newlineBytes)
+			continue
+		}
+
+		t := reflect.TypeOf(arg)
+		base := t
+		if t.Kind() == reflect.Ptr {
+			base = t.Elem()
+		}
+
+		// The method set of a pointer is a superset of the method set of
+		// the type it points to, so the methods which are only in the
+		// former are those with pointer receivers.  They are also invoked
+		// on non-pointer values unless pointer methods are disabled.
+		valueMethods := make(map[string]bool)
+		for i := 0; i < base.NumMethod(); i++ {
+			valueMethods[base.Method(i).Name] = true
+		}
+		var lines []string
+		methodSet := reflect.PtrTo(base)
+		if base.Kind() == reflect.Interface {
+			methodSet = base
+		}
+		for i := 0; i < methodSet.NumMethod(); i++ {
+			m := methodSet.Method(i)
+			line := methodSignature(methodSet, m)
+			if !valueMethods[m.Name] {
+				if t.Kind() != reflect.Ptr && (cs.DisablePointerMethods || UnsafeDisabled) {
+					continue
+				}
+				line += string(pointerReceiverBytes)
+			}
+			lines = append(lines, line)
+		}
+
+		w.Write(openParenBytes)
+		io.WriteString(w, t.String())
+		w.Write(closeParenBytes)
+		w.Write(methodsSuffixBytes)
+		if len(lines) > 0 {
+			w.Write(openParenBytes)
+			w.Write(lenEqualsBytes)
+			printInt(w, int64(len(lines)), 10)
+			w.Write(closeParenBytes)
+			w.Write(spaceBytes)
+		}
+		w.Write(openBraceNewlineBytes)
+		for i, line := range lines {
+			io.WriteString(w, cs.Indent)
+			io.WriteString(w, line)
+			if i < len(lines)-1 {
+				w.Write(commaNewlineBytes)
+			} else {
+				w.Write(newlineBytes)
+			}
+		}
+		w.Write(closeBraceBytes)
+		w.Write(newlineBytes)
+	}
+}
+
+// FdumpMethods outputs the method set of each of the passed values to
+// io.Writer w.  See DumpMethods for details.
+func (c *ConfigState) FdumpMethods(w io.Writer, a ...interface{}) {
+	fdumpMethods(c, w, a...)
+}
+
+// DumpMethods outputs the method set of each of the passed values to standard
+// out.  See the top-level DumpMethods for details.
+func (c *ConfigState) DumpMethods(a ...interface{}) {
+	fdumpMethods(c, os.Stdout, a...)
+}
+
+// SdumpMethods returns a string with the method set of each of the passed
+// values formatted exactly the same as DumpMethods.
+func (c *ConfigState) SdumpMethods(a ...interface{}) string {
+	var buf bytes.Buffer
+	fdumpMethods(c, &buf, a...)
+	return buf.String()
+}
+
+// FdumpMethods outputs the method set of each of the passed values to
+// io.Writer w.  See DumpMethods for details.
+func FdumpMethods(w io.Writer, a ...interface{}) {
+	fdumpMethods(globalConfig(), w, a...)
+}
+
+// SdumpMethods returns a string with the method set of each of the passed
+// values formatted exactly the same as DumpMethods.
+func SdumpMethods(a ...interface{}) string {
+	var buf bytes.Buffer
+	fdumpMethods(globalConfig(), &buf, a...)
+	return buf.String()
+}
+
+/*
+DumpMethods outputs the method set of each of the passed values to standard
+out as a quick aid for debugging why a value does or does not satisfy an
+interface.  Each method is listed with its signature, and the methods which
+have a pointer receiver are flagged since they are not in the method set of
+non-pointer values.  For example:
+
+	(main.Temp) methods (len=2) {
+	 Set(float64) (pointer receiver),
+	 String() string
+	}
+
+Pointer receiver methods are listed for non-pointer values as well since
+spew invokes them, except when the DisablePointerMethods option is set or the
+unsafe package is not available.
+*/
+func DumpMethods(a ...interface{}) {
+	fdumpMethods(globalConfig(), os.Stdout, a...)
+}
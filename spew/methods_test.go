@@ -0,0 +1,63 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestDumpMethods ensures the method sets of values are listed with pointer
+// receiver methods flagged as intended.
+func TestDumpMethods(t *testing.T) {
+	ps := pstringer("x")
+	cs := spew.ConfigState{Indent: " ", DisablePointerMethods: true}
+
+	tests := []struct {
+		cs   *spew.ConfigState
+		in   interface{}
+		want string
+	}{
+		{&cs, customError(1), "(spew_test.customError) methods (len=1) {\n" +
+			" Error() string\n}\n"},
+		{&cs, &ps, "(*spew_test.pstringer) methods (len=1) {\n" +
+			" String() string (pointer receiver)\n}\n"},
+		{&cs, ps, "(spew_test.pstringer) methods {\n}\n"},
+		{&cs, 1, "(int) methods {\n}\n"},
+		{&cs, nil, "(interface {}) <nil>\n"},
+		{&spew.ConfigState{Disabled: true}, customError(1), ""},
+	}
+	for i, test := range tests {
+		if got := test.cs.SdumpMethods(test.in); got != test.want {
+			t.Errorf("SdumpMethods #%d\n got: %q\nwant: %q", i, got, test.want)
+		}
+	}
+
+	// Pointer receiver methods are listed for non-pointer values when spew
+	// is able to invoke them.
+	if !spew.UnsafeDisabled {
+		want := "(spew_test.pstringer) methods (len=1) {\n" +
+			" String() string (pointer receiver)\n}\n"
+		if got := spew.SdumpMethods(ps); got != want {
+			t.Errorf("SdumpMethods\n got: %q\nwant: %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"io"
+	"runtime/debug"
+)
+
+// DumpPanic displays the passed panic value recovered by the caller, the
+// passed context values, and the stack of the current goroutine to io.Writer
+// w in the same style as Dump.  It is intended for use by deferred functions
+// which need to handle the panic themselves, such as re-panicking:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			c.DumpPanic(os.Stderr, r, req, state)
+//			panic(r)
+//		}
+//	}()
+func (c *ConfigState) DumpPanic(w io.Writer, r interface{}, contextVals ...interface{}) {
+	if NoopBuild || outputDisabled(c) {
+		return
+	}
+
+	w.Write(panicHeaderBytes)
+	fdump(c, w, r)
+	if len(contextVals) > 0 {
+		w.Write(contextHeaderBytes)
+		fdump(c, w, contextVals...)
+	}
+	w.Write(stackHeaderBytes)
+	w.Write(debug.Stack())
+}
+
+// RecoverDump recovers from a panic, if any, and displays the panic value, the
+// passed context values, and the stack of the panicking goroutine to
+// io.Writer w in the same style as Dump.  It must be deferred directly for
+// the recovery to take effect:
+//
+//	defer c.RecoverDump(os.Stderr, req, state)
+//
+// The panic is not propagated, even while output is disabled.  See DumpPanic
+// to handle it differently.
+func (c *ConfigState) RecoverDump(w io.Writer, contextVals ...interface{}) {
+	if r := recover(); r != nil {
+		c.DumpPanic(w, r, contextVals...)
+	}
+}
+
+// DumpPanic displays the passed panic value recovered by the caller, the
+// passed context values, and the stack of the current goroutine to io.Writer
+// w using the global Config.  See ConfigState.DumpPanic for details.
+func DumpPanic(w io.Writer, r interface{}, contextVals ...interface{}) {
+	globalConfig().DumpPanic(w, r, contextVals...)
+}
+
+// RecoverDump recovers from a panic, if any, and displays the panic value, the
+// passed context values, and the stack of the panicking goroutine to
+// io.Writer w in the same style as Dump.  Unlike the standard panic output,
+// this includes the local state which led to the panic.  It must be deferred
+// directly for the recovery to take effect:
+//
+//	defer spew.RecoverDump(os.Stderr, req, state)
+//
+// The panic is not propagated.  See DumpPanic to handle it differently.
+func RecoverDump(w io.Writer, contextVals ...interface{}) {
+	if r := recover(); r != nil {
+		globalConfig().DumpPanic(w, r, contextVals...)
+	}
+}
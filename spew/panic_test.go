@@ -0,0 +1,79 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestRecoverDump ensures RecoverDump recovers from panics and displays the
+// panic value, the context values, and the stack.
+func TestRecoverDump(t *testing.T) {
+	var buf bytes.Buffer
+	func() {
+		defer spew.RecoverDump(&buf, 1, "ctx")
+		panic("boom")
+	}()
+
+	got := buf.String()
+	want := "panic: (string) (len=4) \"boom\"\n" +
+		"\ncontext:\n(int) 1\n(string) (len=3) \"ctx\"\n" +
+		"\nstack:\n"
+	if !strings.HasPrefix(got, want) {
+		t.Fatalf("RecoverDump:\n got: %q\nwant prefix: %q", got, want)
+	}
+	if !strings.Contains(got, "TestRecoverDump") {
+		t.Errorf("RecoverDump stack missing caller:\n%s", got)
+	}
+
+	// Nothing is written without a panic.
+	buf.Reset()
+	cs := spew.ConfigState{}
+	func() {
+		defer cs.RecoverDump(&buf)
+	}()
+	if buf.Len() != 0 {
+		t.Errorf("RecoverDump wrote output without a panic: %q", buf.String())
+	}
+
+	// DumpPanic omits the context section when there are no values.
+	buf.Reset()
+	cs.DumpPanic(&buf, 5)
+	if got, want := buf.String(), "panic: (int) 5\n\nstack:\n"; !strings.HasPrefix(got, want) {
+		t.Errorf("DumpPanic:\n got: %q\nwant prefix: %q", got, want)
+	}
+
+	// The panic is recovered without writing anything while output is
+	// disabled.
+	buf.Reset()
+	defer spew.Enable()
+	spew.Disable()
+	func() {
+		defer spew.RecoverDump(&buf, 1)
+		panic("boom")
+	}()
+	if buf.Len() != 0 {
+		t.Errorf("RecoverDump wrote output while disabled: %q", buf.String())
+	}
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Printer is an immutable, reusable printer which is configured by chaining
+// builder methods instead of mutating ConfigState fields directly.  Every
+// builder method returns a new Printer, so a Printer may be shared between
+// goroutines and used as the base for other printers without affecting them.
+//
+// For example:
+//
+//	p := spew.NewPrinter().MaxDepth(3).SortKeys().NoAddresses().To(w)
+//	p.Dump(myVar)
+//	p.Printf("myVar: %v\n", myVar)
+type Printer struct {
+	cs      ConfigState
+	w       io.Writer
+	repeats *repeatTracker
+
+	// verbosity is the verbosity level of the printer when hasVerbosity
+	// is set.  The global verbosity level is used otherwise.
+	verbosity    Level
+	hasVerbosity bool
+}
+
+// repeatTracker tracks the most recent output of Dump for each call site in
+// order to collapse repeated dumps.
+type repeatTracker struct {
+	mu    sync.Mutex
+	sites map[string]*repeatedDump
+}
+
+// repeatedDump is the most recent output of Dump for a call site along with
+// the number of times it has been repeated since it was written.
+type repeatedDump struct {
+	out   string
+	count int
+}
+
+// write writes the passed output of Dump from the passed call site to
+// io.Writer w unless it is identical to the previous output from the call
+// site, in which case it is only counted.  The number of repeats of the
+// previous output, if any, is written first.
+func (r *repeatTracker) write(w io.Writer, caller, out string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	site := r.sites[caller]
+	if site != nil && site.out == out {
+		site.count++
+		return
+	}
+	if site != nil {
+		writeRepeated(w, site.count)
+	}
+	r.sites[caller] = &repeatedDump{out: out}
+	io.WriteString(w, out)
+}
+
+// flush writes the number of repeats of the previous output for every call
+// site and forgets them.
+func (r *repeatTracker) flush(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	callers := make([]string, 0, len(r.sites))
+	for caller := range r.sites {
+		callers = append(callers, caller)
+	}
+	sort.Strings(callers)
+	for _, caller := range callers {
+		writeRepeated(w, r.sites[caller].count)
+	}
+	r.sites = make(map[string]*repeatedDump)
+}
+
+// writeRepeated writes the marker indicating the previous dump was repeated
+// count times when it is non-zero.
+func writeRepeated(w io.Writer, count int) {
+	if count > 0 {
+		fmt.Fprintf(w, "<previous dump repeated %d times>\n", count)
+	}
+}
+
+// NewPrinter returns a Printer which writes to standard out using the same
+// defaults as the global Config has before it is modified.
+func NewPrinter() *Printer {
+	return &Printer{cs: ConfigState{Indent: " "}, w: os.Stdout}
+}
+
+// NewPrinterFromConfig returns a Printer which writes to standard out using a
+// copy of the passed configuration.  When passed the global Config while it is
+// locked by LockConfig, the locked copy is used instead.
+func NewPrinterFromConfig(c *ConfigState) *Printer {
+	if c == &Config {
+		c = globalConfig()
+	}
+	return &Printer{cs: *c, w: os.Stdout}
+}
+
+// with returns a copy of the printer with the passed function applied to the
+// copy's configuration.
+func (p *Printer) with(fn func(cs *ConfigState)) *Printer {
+	np := *p
+	fn(&np.cs)
+	return &np
+}
+
+// Config returns a copy of the configuration used by the printer.
+func (p *Printer) Config() *ConfigState {
+	cs := p.cs
+	return &cs
+}
+
+// To returns a copy of the printer which writes to the passed io.Writer.
+func (p *Printer) To(w io.Writer) *Printer {
+	np := *p
+	np.w = w
+	return &np
+}
+
+// Indent returns a copy of the printer with the Indent option set.
+func (p *Printer) Indent(indent string) *Printer {
+	return p.with(func(cs *ConfigState) { cs.Indent = indent })
+}
+
+// MaxDepth returns a copy of the printer with the MaxDepth option set.
+func (p *Printer) MaxDepth(depth int) *Printer {
+	return p.with(func(cs *ConfigState) { cs.MaxDepth = depth })
+}
+
+// SortKeys returns a copy of the printer with the SortKeys option set.
+func (p *Printer) SortKeys() *Printer {
+	return p.with(func(cs *ConfigState) { cs.SortKeys = true })
+}
+
+// SpewKeys returns a copy of the printer with the SpewKeys option set.
+func (p *Printer) SpewKeys() *Printer {
+	return p.with(func(cs *ConfigState) { cs.SpewKeys = true })
+}
+
+// NoMethods returns a copy of the printer with the DisableMethods option set.
+func (p *Printer) NoMethods() *Printer {
+	return p.with(func(cs *ConfigState) { cs.DisableMethods = true })
+}
+
+// NoPointerMethods returns a copy of the printer with the
+// DisablePointerMethods option set.
+func (p *Printer) NoPointerMethods() *Printer {
+	return p.with(func(cs *ConfigState) { cs.DisablePointerMethods = true })
+}
+
+// NoAddresses returns a copy of the printer with the DisablePointerAddresses
+// option set.
+func (p *Printer) NoAddresses() *Printer {
+	return p.with(func(cs *ConfigState) { cs.DisablePointerAddresses = true })
+}
+
+// NoCapacities returns a copy of the printer with the DisableCapacities
+// option set.
+func (p *Printer) NoCapacities() *Printer {
+	return p.with(func(cs *ConfigState) { cs.DisableCapacities = true })
+}
+
+// ContinueOnMethod returns a copy of the printer with the ContinueOnMethod
+// option set.
+func (p *Printer) ContinueOnMethod() *Printer {
+	return p.with(func(cs *ConfigState) { cs.ContinueOnMethod = true })
+}
+
+// CollapseRepeats returns a copy of the printer which collapses consecutive
+// identical dumps from the same call site, similar to the deduplication done
+// by syslog, so tight loops do not flood the output.  Repeated dumps are only
+// counted, and the marker "<previous dump repeated N times>" is written when
+// the call site next produces different output or FlushRepeats is called.
+// Printers derived from the returned one share its record of the dumps.
+func (p *Printer) CollapseRepeats() *Printer {
+	np := *p
+	np.repeats = &repeatTracker{sites: make(map[string]*repeatedDump)}
+	return &np
+}
+
+// FlushRepeats writes the number of times the previous dump of each call site
+// has been repeated, if any, for printers which collapse repeated dumps.
+func (p *Printer) FlushRepeats() {
+	if p.repeats != nil {
+		p.repeats.flush(p.w)
+	}
+}
+
+// Verbosity returns a copy of the printer with its own verbosity level, which
+// V compares against instead of the global level set by SetVerbosity.
+func (p *Printer) Verbosity(level Level) *Printer {
+	np := *p
+	np.verbosity, np.hasVerbosity = level, true
+	return &np
+}
+
+// V reports whether or not the passed verbosity level is enabled for the
+// printer.  The result provides output functions which write to the printer
+// when it is, and do nothing otherwise:
+//
+//	p.V(2).Dump(myVar)
+func (p *Printer) V(level Level) Verbose {
+	threshold := Verbosity()
+	if p.hasVerbosity {
+		threshold = p.verbosity
+	}
+	return Verbose{enabled: level <= threshold, p: p}
+}
+
+// Configure returns a copy of the printer with the passed function applied to
+// its configuration.  It provides access to the options which do not have a
+// dedicated builder method.
+func (p *Printer) Configure(fn func(cs *ConfigState)) *Printer {
+	return p.with(fn)
+}
+
+// Dump displays the passed parameters to the printer's io.Writer in the same
+// style as Dump.
+func (p *Printer) Dump(a ...interface{}) {
+	if NoopBuild || outputDisabled(&p.cs) {
+		return
+	}
+	if p.repeats == nil {
+		fdump(&p.cs, p.w, a...)
+		return
+	}
+
+	var buf bytes.Buffer
+	fdump(&p.cs, &buf, a...)
+	p.repeats.write(p.w, callerOutsidePackage(&p.cs), buf.String())
+}
+
+// Sdump returns a string with the passed arguments formatted exactly the same
+// as Dump.
+func (p *Printer) Sdump(a ...interface{}) string {
+	return p.cs.Sdump(a...)
+}
+
+// Print formats the passed arguments to the printer's io.Writer in the same
+// style as Print.  It returns the number of bytes written and any write error
+// encountered.
+func (p *Printer) Print(a ...interface{}) (n int, err error) {
+	return p.cs.Fprint(p.w, a...)
+}
+
+// Printf formats the passed arguments to the printer's io.Writer according to
+// the format specifier in the same style as Printf.  It returns the number of
+// bytes written and any write error encountered.
+func (p *Printer) Printf(format string, a ...interface{}) (n int, err error) {
+	return p.cs.Fprintf(p.w, format, a...)
+}
+
+// Println formats the passed arguments to the printer's io.Writer in the same
+// style as Println.  It returns the number of bytes written and any write
+// error encountered.
+func (p *Printer) Println(a ...interface{}) (n int, err error) {
+	return p.cs.Fprintln(p.w, a...)
+}
+
+// Sprintf returns a string with the passed arguments formatted according to
+// the format specifier in the same style as Sprintf.
+func (p *Printer) Sprintf(format string, a ...interface{}) string {
+	return p.cs.Sprintf(format, a...)
+}
@@ -0,0 +1,91 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestPrinter ensures the Printer builder methods configure the output as
+// intended and do not affect the printers they are derived from.
+func TestPrinter(t *testing.T) {
+	var buf bytes.Buffer
+	base := spew.NewPrinter().Indent("\t").To(&buf)
+	sorted := base.SortKeys().NoCapacities()
+	shallow := sorted.MaxDepth(1).NoAddresses()
+
+	m := map[string]int{"b": 2, "a": 1}
+	sorted.Dump(m)
+	want := "(map[string]int) (len=2) {\n" +
+		"\t(string) (len=1) \"a\": (int) 1,\n" +
+		"\t(string) (len=1) \"b\": (int) 2\n}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Dump:\n got: %q\nwant: %q", got, want)
+	}
+
+	buf.Reset()
+	sorted.Printf("%v", m)
+	if got, want := buf.String(), "map[a:1 b:2]"; got != want {
+		t.Errorf("Printf:\n got: %q\nwant: %q", got, want)
+	}
+
+	s := []interface{}{[]int{1}}
+	got := shallow.Sdump(s)
+	want = "([]interface {}) (len=1) {\n" +
+		"\t([]int) (len=1) {\n\t\t<max depth reached>\n\t}\n}\n"
+	if got != want {
+		t.Errorf("Sdump:\n got: %q\nwant: %q", got, want)
+	}
+
+	// The base printer must not have been modified by the derived ones.
+	cs := base.Config()
+	if cs.SortKeys || cs.DisableCapacities || cs.MaxDepth != 0 {
+		t.Errorf("base printer modified: %+v", cs)
+	}
+	if got, want := base.Sprintf("%v", []int{1}), "[1]"; got != want {
+		t.Errorf("Sprintf:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+// TestPrinterCollapseRepeats ensures consecutive identical dumps from the same
+// call site are collapsed.
+func TestPrinterCollapseRepeats(t *testing.T) {
+	var buf bytes.Buffer
+	p := spew.NewPrinter().To(&buf).CollapseRepeats()
+	for _, v := range []int{1, 1, 1, 2, 2} {
+		p.Dump(v)
+	}
+	p.Dump(1)
+	p.Dump(1)
+	p.FlushRepeats()
+
+	want := "(int) 1\n" +
+		"<previous dump repeated 2 times>\n" +
+		"(int) 2\n" +
+		"(int) 1\n" +
+		"(int) 1\n" +
+		"<previous dump repeated 1 times>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Dump:\n got: %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Profile identifies a named configuration preset which may be selected with
+// ConfigState.UseProfile so teams are able to standardize the appearance of
+// dumps across services with a single identifier.
+type Profile string
+
+const (
+	// ProfileCompact produces short output suitable for interactive
+	// debugging.  Pointer addresses and capacities are hidden, and the
+	// depth, number of elements, and length of strings are limited.
+	ProfileCompact Profile = "compact"
+
+	// ProfileTest produces deterministic output suitable for diffing in
+	// tests.  Pointer addresses and capacities are hidden and map keys are
+	// sorted.
+	ProfileTest Profile = "test"
+
+	// ProfileVerbose produces output with as much type information as
+	// possible, including static interface types, the underlying kinds of
+	// named types, struct tags, and slice indices.
+	ProfileVerbose Profile = "verbose"
+
+	// ProfileProduction produces bounded output which is safe to write to
+	// logs.  The depth, number of elements, and length of strings are
+	// limited, map keys are sorted, and the output of methods is escaped.
+	ProfileProduction Profile = "production"
+)
+
+var (
+	// profilesMtx protects profiles.
+	profilesMtx sync.RWMutex
+
+	// profiles houses the registered profiles by name.
+	profiles = map[Profile]ConfigState{
+		ProfileCompact: {
+			Indent:                  " ",
+			MaxDepth:                5,
+			DisablePointerAddresses: true,
+			DisableCapacities:       true,
+			MaxElements:             20,
+			MaxStringLength:         200,
+		},
+		ProfileTest: {
+			Indent:                  " ",
+			DisablePointerAddresses: true,
+			DisableCapacities:       true,
+			SortKeys:                true,
+			SpewKeys:                true,
+		},
+		ProfileVerbose: {
+			Indent:              "  ",
+			ContinueOnMethod:    true,
+			ShowUnderlyingTypes: true,
+			ShowInterfaceTypes:  true,
+			ShowTags:            true,
+			ShowIndices:         true,
+		},
+		ProfileProduction: {
+			Indent:                  " ",
+			MaxDepth:                10,
+			DisablePointerAddresses: true,
+			SortKeys:                true,
+			MaxElements:             100,
+			MaxStringLength:         1024,
+			EscapeMethodOutput:      true,
+		},
+	}
+)
+
+// RegisterProfile registers the passed configuration under the passed name so
+// it may be selected with ConfigState.UseProfile.  Registering a name which
+// already exists, including the names of the built-in profiles, replaces the
+// existing profile.  It is safe for concurrent use.
+func RegisterProfile(name Profile, cs ConfigState) {
+	profilesMtx.Lock()
+	profiles[name] = cs
+	profilesMtx.Unlock()
+}
+
+// ProfileConfig returns a copy of the configuration registered under the
+// passed name along with whether or not one exists.
+func ProfileConfig(name Profile) (*ConfigState, bool) {
+	profilesMtx.RLock()
+	cs, ok := profiles[name]
+	profilesMtx.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return &cs, true
+}
+
+// UseProfile replaces all of the options of c with those of the profile
+// registered under the passed name.  An error is returned, and c is left
+// unmodified, when no such profile exists.
+//
+// For example, to standardize the output of the top-level functions:
+//
+//	spew.Config.UseProfile(spew.ProfileProduction)
+func (c *ConfigState) UseProfile(name Profile) error {
+	cs, ok := ProfileConfig(name)
+	if !ok {
+		return fmt.Errorf("spew: unknown profile %q", string(name))
+	}
+	*c = *cs
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestProfiles ensures the built-in and registered profiles are selectable as
+// intended.
+func TestProfiles(t *testing.T) {
+	var cs spew.ConfigState
+	if err := cs.UseProfile(spew.ProfileTest); err != nil {
+		t.Fatalf("UseProfile: unexpected error: %v", err)
+	}
+	m := map[string]int{"b": 2, "a": 1}
+	want := "(map[string]int) (len=2) {\n" +
+		" (string) (len=1) \"a\": (int) 1,\n" +
+		" (string) (len=1) \"b\": (int) 2\n}\n"
+	if got := cs.Sdump(m); got != want {
+		t.Errorf("ProfileTest:\n got: %q\nwant: %q", got, want)
+	}
+
+	for _, name := range []spew.Profile{spew.ProfileCompact,
+		spew.ProfileVerbose, spew.ProfileProduction} {
+
+		if _, ok := spew.ProfileConfig(name); !ok {
+			t.Errorf("built-in profile %q missing", name)
+		}
+	}
+
+	// Custom profiles are selectable once registered.
+	spew.RegisterProfile("tabs", spew.ConfigState{Indent: "\t"})
+	if err := cs.UseProfile("tabs"); err != nil {
+		t.Fatalf("UseProfile: unexpected error: %v", err)
+	}
+	if got, want := cs.Sdump([]int{1}), "([]int) (len=1 cap=1) {\n\t(int) 1\n}\n"; got != want {
+		t.Errorf("custom profile:\n got: %q\nwant: %q", got, want)
+	}
+
+	// Unknown profiles are rejected without modifying the configuration.
+	if err := cs.UseProfile("unknown"); err == nil {
+		t.Errorf("UseProfile: expected error for unknown profile")
+	}
+	if cs.Indent != "\t" {
+		t.Errorf("UseProfile modified configuration on error")
+	}
+
+	// Modifying a returned configuration does not affect the profile.
+	pc, _ := spew.ProfileConfig("tabs")
+	pc.Indent = "x"
+	if pc2, _ := spew.ProfileConfig("tabs"); pc2.Indent != "\t" {
+		t.Errorf("ProfileConfig returned shared configuration")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// methodCall identifies a value whose error or Stringer interface is being
+// invoked.  References, such as pointers and maps, are identified by their
+// address, while other values are identified by the value itself when it is
+// comparable and by their type alone otherwise since each call back into spew
+// passes a new copy of them.
+type methodCall struct {
+	typ reflect.Type
+	ptr uintptr
+	val interface{}
+}
+
+var (
+	// activeCallsMtx protects activeCalls.
+	activeCallsMtx sync.Mutex
+
+	// activeCalls holds the number of invocations of the methods of each
+	// value which are in progress.
+	activeCalls = make(map[methodCall]int)
+)
+
+// newMethodCall returns the identity of the passed value for detecting calls
+// of its methods which call back into spew with it.
+func newMethodCall(v reflect.Value) methodCall {
+	call := methodCall{typ: v.Type()}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func,
+		reflect.UnsafePointer:
+		call.ptr = v.Pointer()
+		return call
+	}
+	if v.Type().Comparable() && v.CanInterface() {
+		if val := v.Interface(); isComparable(val) {
+			call.val = val
+		}
+	}
+	return call
+}
+
+// isComparable returns whether or not the passed value may be compared, which
+// is not the case for comparable types holding interfaces with uncomparable
+// dynamic values.
+func isComparable(val interface{}) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return val == val
+}
+
+// inMethodCall returns whether or not the current goroutine is running an
+// error or Stringer method invoked by this package.
+func inMethodCall() bool {
+	pcs := make([]uintptr, 256)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, spewFuncPrefix) &&
+			strings.HasSuffix(frame.Function, ".callMethod") {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// callMethod invokes the passed method of value v, which produces the text of
+// its error or Stringer interface, unless the method called back into spew
+// with the same value, in which case invoking it again would recurse
+// endlessly.  It returns whether or not the method was invoked.
+func callMethod(v reflect.Value, method func() string) (text string, ok bool) {
+	call := newMethodCall(v)
+	activeCallsMtx.Lock()
+	active := activeCalls[call] > 0
+	activeCalls[call]++
+	activeCallsMtx.Unlock()
+	defer func() {
+		activeCallsMtx.Lock()
+		if activeCalls[call]--; activeCalls[call] == 0 {
+			delete(activeCalls, call)
+		}
+		activeCallsMtx.Unlock()
+	}()
+
+	// The value may also be in use by a method running on another goroutine,
+	// so only a call from within a method counts as recursion.
+	if active && inMethodCall() {
+		return "", false
+	}
+	return method(), true
+}
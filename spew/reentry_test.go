@@ -0,0 +1,124 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// selfDumpConfig is the configuration selfDumper dumps itself with.
+var selfDumpConfig = spew.ConfigState{Indent: " ", DisablePointerAddresses: true}
+
+// selfDumper is a Stringer which dumps itself with spew.
+type selfDumper struct {
+	N int
+}
+
+func (s *selfDumper) String() string {
+	return selfDumpConfig.Sdump(s)
+}
+
+// selfFormatter is a Stringer with a value receiver which formats itself with
+// spew.
+type selfFormatter struct {
+	N int
+}
+
+func (s selfFormatter) String() string {
+	return spew.Sprintf("formatted %v", s)
+}
+
+// bangInt is a Stringer which formats its underlying integer with spew.
+type bangInt int
+
+func (b bangInt) String() string {
+	return spew.Sprint(int(b)) + "!"
+}
+
+// nestedPair is a Stringer with a value receiver which formats a different
+// value of its own type with spew.
+type nestedPair struct {
+	A, B int
+}
+
+func (p nestedPair) String() string {
+	if p.A == 0 {
+		return "leaf"
+	}
+	return "pair of " + spew.Sprint(nestedPair{B: p.B})
+}
+
+// TestRecursiveMethods ensures error and Stringer methods which call back into
+// spew with the same value are not invoked again.
+func TestRecursiveMethods(t *testing.T) {
+	cs := selfDumpConfig
+	want := "(*spew_test.selfDumper)((*spew_test.selfDumper)(<recursive method call>)\n)\n"
+	if got := cs.Sdump(&selfDumper{N: 1}); got != want {
+		t.Errorf("Sdump got: %q want: %q", got, want)
+	}
+
+	want = "formatted <recursive method call>"
+	if got := spew.Sprint(selfFormatter{N: 1}); got != want {
+		t.Errorf("Sprint got: %q want: %q", got, want)
+	}
+
+	// Stringers which call back into spew with other values are invoked as
+	// usual.
+	want = "pair of leaf"
+	if got := spew.Sprint(nestedPair{A: 1, B: 2}); got != want {
+		t.Errorf("Sprint got: %q want: %q", got, want)
+	}
+
+	want = "(spew_test.bangInt) 1!\n"
+	if got := cs.Sdump(bangInt(1)); got != want {
+		t.Errorf("Sdump got: %q want: %q", got, want)
+	}
+}
+
+// BenchmarkDumpStructSlice measures dumping a slice of small structs, none of
+// which implement the error or Stringer interfaces.
+func BenchmarkDumpStructSlice(b *testing.B) {
+	type point struct{ X, Y int }
+	points := make([]point, 2000)
+	for i := range points {
+		points[i] = point{i, -i}
+	}
+	cs := spew.ConfigState{Indent: " "}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cs.Sdump(points)
+	}
+}
+
+// BenchmarkDumpStringerSlice measures dumping a slice of values which
+// implement the Stringer interface.
+func BenchmarkDumpStringerSlice(b *testing.B) {
+	values := make([]bangInt, 2000)
+	for i := range values {
+		values[i] = bangInt(i)
+	}
+	cs := spew.ConfigState{Indent: " "}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cs.Sdump(values)
+	}
+}
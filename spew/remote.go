@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSinkClosed is returned when writing to a RemoteSink which has been
+// closed.
+var ErrSinkClosed = errors.New("spew: remote sink closed")
+
+// remoteFlagGzip is set in the flags of a frame written by a RemoteSink when
+// the payload is gzip compressed.
+const remoteFlagGzip = 1
+
+// RemoteSinkOptions specifies the behavior of a RemoteSink.  The zero value
+// provides sensible defaults.
+type RemoteSinkOptions struct {
+	// Compress specifies whether or not each dump is gzip compressed before
+	// it is sent.
+	Compress bool
+
+	// QueueSize is the maximum number of dumps waiting to be sent.  Dumps
+	// written while the queue is full are dropped so a slow or unavailable
+	// collector never blocks the application.  It defaults to 256.
+	QueueSize int
+
+	// DialTimeout and WriteTimeout bound the time spent connecting to the
+	// collector and sending a single dump respectively.  They default to
+	// 5 seconds.
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// ReconnectDelay is the time to wait before reconnecting after the
+	// connection to the collector fails.  It defaults to 1 second.
+	ReconnectDelay time.Duration
+}
+
+// RemoteSink is an io.Writer which ships dumps to a remote collector over a
+// stream socket, such as TCP or a Unix socket, so dumps from many processes
+// are able to be aggregated off-box.  Each call to Write is sent as a single
+// frame consisting of a flags byte, the length of the payload as a 4-byte
+// big-endian integer, and the payload itself, which is gzip compressed when
+// the flags byte has its lowest bit set.
+//
+// Dumps are sent asynchronously by a background goroutine which reconnects as
+// needed.  Dumps are dropped rather than blocking the caller when the
+// collector is unable to keep up.
+type RemoteSink struct {
+	dropped uint64 // accessed atomically; first for 64-bit alignment
+
+	// Config is the configuration used by Dump.  The global Config is used
+	// when it is nil.
+	Config *ConfigState
+
+	network string
+	address string
+	opts    RemoteSinkOptions
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+	stop   chan struct{}
+	done   chan struct{}
+	conn   net.Conn
+}
+
+// NewRemoteSink returns a RemoteSink which sends dumps to the collector
+// listening at the passed address on the named network, as accepted by
+// net.Dial.  The connection is established lazily by the background
+// goroutine.  A nil opts uses the defaults.
+func NewRemoteSink(network, address string, opts *RemoteSinkOptions) *RemoteSink {
+	s := &RemoteSink{network: network, address: address}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.QueueSize <= 0 {
+		s.opts.QueueSize = 256
+	}
+	if s.opts.DialTimeout <= 0 {
+		s.opts.DialTimeout = 5 * time.Second
+	}
+	if s.opts.WriteTimeout <= 0 {
+		s.opts.WriteTimeout = 5 * time.Second
+	}
+	if s.opts.ReconnectDelay <= 0 {
+		s.opts.ReconnectDelay = time.Second
+	}
+	s.queue = make(chan []byte, s.opts.QueueSize)
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return s
+}
+
+// Write queues a copy of p to be sent to the collector as a single dump.  It
+// never blocks.  Dumps which do not fit in the queue are dropped and counted
+// by Dropped, which is not reported as an error.
+func (s *RemoteSink) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, ErrSinkClosed
+	}
+
+	msg := make([]byte, len(p))
+	copy(msg, p)
+	select {
+	case s.queue <- msg:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Dump sends the passed arguments, formatted exactly the same as Dump, to
+// the collector as a single dump.  Nothing is sent while output is disabled.
+func (s *RemoteSink) Dump(a ...interface{}) {
+	cs := s.Config
+	if cs == nil {
+		cs = globalConfig()
+	}
+	if NoopBuild || outputDisabled(cs) {
+		return
+	}
+	var buf bytes.Buffer
+	fdump(cs, &buf, a...)
+	s.Write(buf.Bytes())
+}
+
+// Dropped returns the number of dumps which have been dropped, either because
+// the queue was full or because the collector was unavailable when the sink
+// was closed.
+func (s *RemoteSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close sends the dumps remaining in the queue, unless the collector is
+// unavailable, and closes the connection.  Writes after Close return
+// ErrSinkClosed.
+func (s *RemoteSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	close(s.stop)
+	s.mu.Unlock()
+
+	<-s.done
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
+
+// run sends queued dumps until the sink is closed.
+func (s *RemoteSink) run() {
+	defer close(s.done)
+	for msg := range s.queue {
+		s.deliver(s.frame(msg))
+	}
+}
+
+// deliver sends the passed frame, retrying after the reconnect delay until it
+// is sent.  The frame is dropped when the sink is closed while the collector
+// is unavailable.
+func (s *RemoteSink) deliver(frame []byte) {
+	for !s.send(frame) {
+		select {
+		case <-s.stop:
+			atomic.AddUint64(&s.dropped, 1)
+			return
+		case <-time.After(s.opts.ReconnectDelay):
+		}
+	}
+}
+
+// frame returns the frame for the passed dump.
+func (s *RemoteSink) frame(msg []byte) []byte {
+	var flags byte
+	if s.opts.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(msg)
+		zw.Close()
+		msg = buf.Bytes()
+		flags |= remoteFlagGzip
+	}
+
+	frame := make([]byte, 5+len(msg))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(msg)))
+	copy(frame[5:], msg)
+	return frame
+}
+
+// send writes the passed frame to the collector, connecting first when
+// needed.  It returns false when the frame could not be sent, in which case
+// the connection is discarded so it is reestablished on the next attempt.
+func (s *RemoteSink) send(frame []byte) bool {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, s.opts.DialTimeout)
+		if err != nil {
+			return false
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.opts.WriteTimeout))
+	if _, err := s.conn.Write(frame); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return false
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// readFrame reads a single frame written by a RemoteSink and returns its
+// decompressed payload.
+func readFrame(r io.Reader) ([]byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(hdr[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	if hdr[0]&1 == 0 {
+		return payload, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(zr)
+}
+
+// TestRemoteSink ensures dumps are sent to the collector as frames, both with
+// and without compression.
+func TestRemoteSink(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skipf("Listen: %v", err)
+		}
+
+		sink := spew.NewRemoteSink("tcp", ln.Addr().String(),
+			&spew.RemoteSinkOptions{Compress: compress})
+		sink.Config = &spew.ConfigState{Indent: " "}
+		sink.Dump([]int{1})
+		sink.Write([]byte("second"))
+
+		conn, err := ln.Accept()
+		if err != nil {
+			t.Fatalf("Accept: %v", err)
+		}
+		for _, want := range []string{
+			"([]int) (len=1 cap=1) {\n (int) 1\n}\n", "second",
+		} {
+			got, err := readFrame(conn)
+			if err != nil {
+				t.Fatalf("readFrame: %v", err)
+			}
+			if string(got) != want {
+				t.Errorf("compress %v: got %q, want %q", compress, got, want)
+			}
+		}
+		if err := sink.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+		if _, err := sink.Write(nil); err != spew.ErrSinkClosed {
+			t.Errorf("Write after Close: got %v, want %v", err,
+				spew.ErrSinkClosed)
+		}
+		conn.Close()
+		ln.Close()
+	}
+}
+
+// TestRemoteSinkDrop ensures dumps are dropped rather than blocking when the
+// collector is unavailable.
+func TestRemoteSinkDrop(t *testing.T) {
+	// Reserve an address with nothing listening on it.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	sink := spew.NewRemoteSink("tcp", addr, &spew.RemoteSinkOptions{
+		QueueSize:      1,
+		ReconnectDelay: time.Hour,
+	})
+	for i := 0; i < 10; i++ {
+		sink.Write([]byte("dump"))
+	}
+	start := time.Now()
+	sink.Close()
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Errorf("Close blocked for %v", elapsed)
+	}
+	if got := sink.Dropped(); got != 10 {
+		t.Errorf("Dropped: got %d, want 10", got)
+	}
+}
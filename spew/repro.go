@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+)
+
+// reproState contains information about the state of a minimal reproduction
+// dump.
+type reproState struct {
+	cs       *ConfigState
+	w        io.Writer
+	pointers map[uintptr]bool
+	shown    int
+}
+
+// leaf outputs the passed value located at path on its own line in the same
+// format as Dump.
+func (r *reproState) leaf(path string, v reflect.Value) {
+	r.shown++
+	r.w.Write([]byte(r.cs.Indent))
+	r.w.Write([]byte(path))
+	r.w.Write(colonSpaceBytes)
+	d := dumpState{w: r.w, cs: r.cs, pointers: make(map[visitKey]int)}
+	d.dumpLine(v)
+}
+
+// hasMethod returns whether or not the passed value is displayed via its error
+// or Stringer interface, in which case its contents are not walked.
+func (r *reproState) hasMethod(v reflect.Value) bool {
+	if r.cs.DisableMethods {
+		return false
+	}
+	iv, ok := interfaceValue(r.cs, v)
+	if !ok {
+		return false
+	}
+	t := iv.Type()
+	return t.Implements(errorType) || t.Implements(stringerType)
+}
+
+// walk outputs the non-zero leaf values within the passed value located at
+// path.
+func (r *reproState) walk(path string, v reflect.Value) {
+	// Follow pointers and interfaces while detecting circular references.
+	// Nil pointers and interfaces are zero values, so they are skipped.
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			addr := v.Pointer()
+			if r.pointers[addr] {
+				r.shown++
+				r.w.Write([]byte(r.cs.Indent))
+				r.w.Write([]byte(path))
+				r.w.Write(colonSpaceBytes)
+				r.w.Write(circularBytes)
+				r.w.Write(newlineBytes)
+				return
+			}
+			r.pointers[addr] = true
+			defer delete(r.pointers, addr)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.IsZero() {
+		return
+	}
+	if r.hasMethod(v) {
+		r.leaf(path, v)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, sf := range structFields(r.cs, v) {
+			r.walk(fieldPath(path, sf.path), sf.value)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 || v.Type().Elem().Kind() == reflect.Uint8 {
+			r.leaf(path, v)
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			r.walk(indexPath(path, i), v.Index(i))
+		}
+
+	case reflect.Map:
+		if v.Len() == 0 {
+			r.leaf(path, v)
+			return
+		}
+		keys := v.MapKeys()
+		if r.cs.SortKeys {
+			sortValues(keys, r.cs)
+		}
+		for _, k := range keys {
+			r.walk(keyPath(path, k), v.MapIndex(k))
+		}
+
+	default:
+		r.leaf(path, v)
+	}
+}
+
+// fdumpRepro outputs the minimal reproduction of each of the passed values to
+// io.Writer w.
+func fdumpRepro(cs *ConfigState, w io.Writer, a ...interface{}) {
+	if NoopBuild || outputDisabled(cs) {
+		return
+	}
+	for _, arg := range a {
+		v := reflect.ValueOf(arg)
+		root := reflect.Indirect(v)
+		switch root.Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		default:
+			fdumpArg(cs, w, arg, nil, nil, nil)
+			continue
+		}
+
+		w.Write(openParenBytes)
+		w.Write([]byte(typeString(cs, v.Type())))
+		w.Write(closeParenBytes)
+		w.Write(newlineBytes)
+		r := reproState{cs: cs, w: w, pointers: make(map[uintptr]bool)}
+		r.walk("", v)
+		if r.shown == 0 {
+			w.Write([]byte(cs.Indent))
+			w.Write(zeroValueBytes)
+			w.Write(newlineBytes)
+		}
+	}
+}
+
+// FdumpRepro outputs a minimal reproduction of each of the passed values to
+// io.Writer w.  See DumpRepro for details.
+func (c *ConfigState) FdumpRepro(w io.Writer, a ...interface{}) {
+	fdumpRepro(c, w, a...)
+}
+
+// SdumpRepro returns a string with a minimal reproduction of each of the
+// passed values.  See DumpRepro for details.
+func (c *ConfigState) SdumpRepro(a ...interface{}) string {
+	var buf bytes.Buffer
+	fdumpRepro(c, &buf, a...)
+	return buf.String()
+}
+
+// DumpRepro outputs a minimal reproduction of each of the passed values to
+// standard out.  Only the values within them which are not zero values are
+// displayed, one per line, prefixed by their path, which is in the same form
+// as the paths passed to the RedactFunc option.  This results in a compact
+// description of the parts of a large value which matter that is suitable for
+// attaching to bug reports.  For example:
+//
+//	(*main.Config)
+//	 Server.Port: (int) 8080
+//	 Server.Hosts[1]: (string) (len=1) "b"
+//
+// Values which implement the error or Stringer interface are displayed with
+// them rather than walked, and empty slices and maps are displayed since they
+// are not nil.  Values which are not structs, slices, arrays, or maps are
+// displayed the same as with Dump.
+func (c *ConfigState) DumpRepro(a ...interface{}) {
+	fdumpRepro(c, os.Stdout, a...)
+}
+
+// FdumpRepro outputs a minimal reproduction of each of the passed values to
+// io.Writer w using the global Config.  See ConfigState.DumpRepro for details.
+func FdumpRepro(w io.Writer, a ...interface{}) {
+	fdumpRepro(globalConfig(), w, a...)
+}
+
+// SdumpRepro returns a string with a minimal reproduction of each of the
+// passed values using the global Config.  See ConfigState.DumpRepro for
+// details.
+func SdumpRepro(a ...interface{}) string {
+	var buf bytes.Buffer
+	fdumpRepro(globalConfig(), &buf, a...)
+	return buf.String()
+}
+
+// DumpRepro outputs a minimal reproduction of each of the passed values to
+// standard out using the global Config.  See ConfigState.DumpRepro for
+// details.
+func DumpRepro(a ...interface{}) {
+	fdumpRepro(globalConfig(), os.Stdout, a...)
+}
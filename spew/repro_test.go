@@ -0,0 +1,109 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// reproServer and reproConfig are used to test minimal reproduction output.
+type reproServer struct {
+	Port  int
+	Hosts []string
+	Opts  map[string]int
+	Err   error
+}
+
+type reproConfig struct {
+	Name   string
+	Server *reproServer
+	Next   *reproConfig
+	Tags   []string
+}
+
+// TestDumpRepro ensures only the non-zero values within a value are displayed
+// by DumpRepro along with their paths.
+func TestDumpRepro(t *testing.T) {
+	cfg := &reproConfig{Server: &reproServer{
+		Port:  8080,
+		Hosts: []string{"", "b"},
+		Opts:  map[string]int{"x": 0, "y": 2},
+	}}
+	cfg.Next = cfg
+	cs := spew.ConfigState{Indent: " ", SortKeys: true}
+
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{cfg, "(*spew_test.reproConfig)\n" +
+			" Server.Port: (int) 8080\n" +
+			" Server.Hosts[1]: (string) (len=1) \"b\"\n" +
+			" Server.Opts[y]: (int) 2\n" +
+			" Next: <already shown>\n"},
+		{reproConfig{Tags: []string{}}, "(spew_test.reproConfig)\n" +
+			" Tags: ([]string) {\n}\n"},
+		{reproConfig{}, "(spew_test.reproConfig)\n <zero value>\n"},
+		{5, "(int) 5\n"},
+	}
+	for i, test := range tests {
+		if got := cs.SdumpRepro(test.in); got != test.want {
+			t.Errorf("SdumpRepro #%d\n got: %q\nwant: %q", i, got, test.want)
+		}
+	}
+}
+
+// TestDumpReproOptions ensures minimal reproductions honor the options which
+// observe or bound the dump of each value and are not output while disabled.
+func TestDumpReproOptions(t *testing.T) {
+	in := reproServer{Port: 8080, Hosts: []string{"a", "b"}}
+	var paths []string
+	tests := []struct {
+		name string
+		cs   spew.ConfigState
+		want string
+	}{
+		{"NodeFunc", spew.ConfigState{Indent: " ", NodeFunc: func(n spew.Node) {
+			paths = append(paths, n.Path)
+		}}, "(spew_test.reproServer)\n Port: (int) 8080\n" +
+			" Hosts[0]: (string) (len=1) \"a\"\n" +
+			" Hosts[1]: (string) (len=1) \"b\"\n"},
+		{"MaxNodes", spew.ConfigState{Indent: " ", MaxNodes: 1},
+			"(spew_test.reproServer)\n" +
+				" Port: (int) 8080\n Hosts[0]: (string) (len=1) \"a\"\n" +
+				" Hosts[1]: (string) (len=1) \"b\"\n"},
+		{"ProgressFunc", spew.ConfigState{Indent: " ", ProgressInterval: 1,
+			ProgressFunc: func(spew.Progress) bool { return false }},
+			"(spew_test.reproServer)\n Port: \n<dump aborted>\n" +
+				" Hosts[0]: \n<dump aborted>\n" +
+				" Hosts[1]: \n<dump aborted>\n"},
+		{"Disabled", spew.ConfigState{Indent: " ", Disabled: true}, ""},
+	}
+	for _, test := range tests {
+		if got := test.cs.SdumpRepro(in); got != test.want {
+			t.Errorf("%s:\n got: %q\nwant: %q", test.name, got, test.want)
+		}
+	}
+	if len(paths) != 3 {
+		t.Errorf("NodeFunc: got paths %q, want 3", paths)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// DumpRing is an io.Writer which keeps the most recent dumps in memory, so the
+// history of dumped state leading up to an error is able to be attached to the
+// error report once it occurs.  Each call to Write is kept as a single dump.
+// The oldest dumps are discarded when either the maximum number of dumps or
+// the maximum number of bytes is exceeded.
+//
+// It is safe for concurrent use.
+type DumpRing struct {
+	// Config is the configuration used by Dump.  The global Config is used
+	// when it is nil.
+	Config *ConfigState
+
+	mu       sync.Mutex
+	maxBytes int
+	dumps    [][]byte
+	head     int
+	count    int
+	size     int
+}
+
+// NewDumpRing returns a DumpRing which keeps at most maxDumps dumps totaling
+// at most maxBytes bytes.  A maxBytes of zero imposes no limit on the number
+// of bytes.  The most recent dump is always kept, even when it is larger than
+// maxBytes on its own.
+func NewDumpRing(maxDumps, maxBytes int) *DumpRing {
+	if maxDumps < 1 {
+		maxDumps = 1
+	}
+	return &DumpRing{maxBytes: maxBytes, dumps: make([][]byte, maxDumps)}
+}
+
+// discardOldest discards the oldest dump.  The mutex must be held.
+func (r *DumpRing) discardOldest() {
+	r.size -= len(r.dumps[r.head])
+	r.dumps[r.head] = nil
+	r.head = (r.head + 1) % len(r.dumps)
+	r.count--
+}
+
+// Write keeps a copy of p as the most recent dump.  It never fails.
+func (r *DumpRing) Write(p []byte) (int, error) {
+	dump := make([]byte, len(p))
+	copy(dump, p)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.count == len(r.dumps) {
+		r.discardOldest()
+	}
+	r.dumps[(r.head+r.count)%len(r.dumps)] = dump
+	r.count++
+	r.size += len(dump)
+	for r.maxBytes > 0 && r.size > r.maxBytes && r.count > 1 {
+		r.discardOldest()
+	}
+	return len(p), nil
+}
+
+// Dump keeps the passed arguments, formatted exactly the same as Dump, as the
+// most recent dump.  Nothing is kept while output is disabled.
+func (r *DumpRing) Dump(a ...interface{}) {
+	cs := r.Config
+	if cs == nil {
+		cs = globalConfig()
+	}
+	if NoopBuild || outputDisabled(cs) {
+		return
+	}
+	var buf bytes.Buffer
+	fdump(cs, &buf, a...)
+	r.Write(buf.Bytes())
+}
+
+// Dumps returns the dumps currently kept, ordered from oldest to newest.
+func (r *DumpRing) Dumps() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dumps := make([]string, r.count)
+	for i := range dumps {
+		dumps[i] = string(r.dumps[(r.head+i)%len(r.dumps)])
+	}
+	return dumps
+}
+
+// WriteTo writes the dumps currently kept to io.Writer w, ordered from oldest
+// to newest.  It implements the io.WriterTo interface.
+func (r *DumpRing) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, dump := range r.Dumps() {
+		n, err := io.WriteString(w, dump)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Reset discards all of the dumps currently kept.
+func (r *DumpRing) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.count > 0 {
+		r.discardOldest()
+	}
+	r.head = 0
+}
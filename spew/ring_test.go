@@ -0,0 +1,68 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestDumpRing ensures the most recent dumps are kept within the limits on
+// the number of dumps and bytes.
+func TestDumpRing(t *testing.T) {
+	r := spew.NewDumpRing(3, 0)
+	r.Config = &spew.ConfigState{Indent: " "}
+	for _, s := range []string{"a", "b", "c", "d"} {
+		r.Write([]byte(s))
+	}
+	if got, want := r.Dumps(), []string{"b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dumps: got %q, want %q", got, want)
+	}
+
+	r.Dump(1)
+	var sb strings.Builder
+	r.WriteTo(&sb)
+	if got, want := sb.String(), "cd(int) 1\n"; got != want {
+		t.Errorf("WriteTo: got %q, want %q", got, want)
+	}
+
+	r.Reset()
+	if got := r.Dumps(); len(got) != 0 {
+		t.Errorf("Dumps after Reset: got %q, want none", got)
+	}
+
+	// The oldest dumps are discarded to stay within the byte limit, but the
+	// most recent dump is always kept.
+	r = spew.NewDumpRing(10, 5)
+	for _, s := range []string{"aa", "bb", "cc", "dddddd"} {
+		r.Write([]byte(s))
+		if s == "cc" {
+			if got, want := r.Dumps(), []string{"bb", "cc"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Dumps: got %q, want %q", got, want)
+			}
+		}
+	}
+	if got, want := r.Dumps(), []string{"dddddd"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dumps: got %q, want %q", got, want)
+	}
+}
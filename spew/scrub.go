@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import "regexp"
+
+// Scrubber replaces the text matching a regular expression in the output of
+// spew.  The replacement may refer to submatches in the same way as
+// regexp.Regexp.ReplaceAllString.
+type Scrubber struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+var (
+	// ScrubAddresses replaces hexadecimal pointer addresses, such as
+	// 0xc000012345, with 0xADDR.
+	ScrubAddresses = Scrubber{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "0xADDR"}
+
+	// ScrubUUIDs replaces UUIDs with UUID.
+	ScrubUUIDs = Scrubber{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "UUID"}
+
+	// ScrubTimestamps replaces timestamps in RFC 3339 format and in the
+	// format of the String method of time.Time with TIMESTAMP.
+	ScrubTimestamps = Scrubber{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}` +
+		`(?:[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z| ?[+-]\d{2}:?\d{2})?` +
+		`(?: [A-Z]{3,5})?(?: m=[+-]\d+\.\d+)?)`), "TIMESTAMP"}
+)
+
+// needsPostProcessing returns whether or not the output for each value needs to
+// be captured so it can be post-processed according to the Scrubbers and
+// OutputFunc options.
+func (c *ConfigState) needsPostProcessing() bool {
+	return c.OutputFunc != nil || len(c.Scrubbers) > 0
+}
+
+// postProcess returns the passed output after applying the Scrubbers option
+// followed by the OutputFunc option.
+func (c *ConfigState) postProcess(out string) string {
+	for _, s := range c.Scrubbers {
+		out = s.Pattern.ReplaceAllString(out, s.Replacement)
+	}
+	if c.OutputFunc != nil {
+		out = c.OutputFunc(out)
+	}
+	return out
+}
@@ -0,0 +1,236 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// logValuer implements slog.LogValuer for arbitrary values.
+type logValuer struct {
+	cs *ConfigState
+	v  interface{}
+}
+
+// LogValue converts the wrapped value into a slog.Value.  It implements the
+// slog.LogValuer interface, so the conversion is only performed when a record
+// is actually logged.  An empty group, which handlers omit, is returned while
+// output is disabled.
+func (l logValuer) LogValue() slog.Value {
+	if NoopBuild || outputDisabled(l.cs) {
+		return slog.GroupValue()
+	}
+	s := slogState{cs: l.cs, pointers: make(map[uintptr]bool)}
+	return s.value(reflect.ValueOf(l.v), 0)
+}
+
+// slogState contains information about the state of a conversion to a
+// slog.Value.
+type slogState struct {
+	cs       *ConfigState
+	pointers map[uintptr]bool
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// methodValue returns the slog.Value for values which implement the
+// slog.LogValuer, error, or Stringer interfaces, in that order of precedence,
+// along with whether or not one was found.  Methods are not invoked when the
+// DisableMethods option is set, with the exception of slog.LogValuer which is
+// always honored so types may control how they are logged.
+func (s *slogState) methodValue(v reflect.Value) (val slog.Value, found bool) {
+	iv, ok := interfaceValue(s.cs, v)
+	if !ok || !iv.CanInterface() {
+		return slog.Value{}, false
+	}
+
+	defer func() {
+		if err := recover(); err != nil {
+			val = slog.StringValue(fmt.Sprintf("%s%v%s", panicBytes, err,
+				closeParenBytes))
+			found = true
+		}
+	}()
+	if lv, ok := iv.Interface().(slog.LogValuer); ok {
+		return lv.LogValue().Resolve(), true
+	}
+	if !s.cs.DisableMethods {
+		if text, ok := methodText(s.cs, iv.Interface()); ok {
+			return slog.StringValue(text), true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// key returns the attribute key for the passed map key.  Keys are formatted
+// the same as the %v verb of the configuration when the SpewKeys option is set
+// so pointer keys display the values they point to rather than addresses.
+func (s *slogState) key(k reflect.Value) string {
+	if s.cs.SpewKeys && k.CanInterface() {
+		return s.cs.Sprintf("%v", k.Interface())
+	}
+	return fmt.Sprint(k)
+}
+
+// value converts the passed reflect.Value into a slog.Value.  Scalars are
+// converted into values of the matching slog kind while structs, maps,
+// slices, and arrays are converted into groups so structured log backends are
+// able to index their contents.  Pointers and interfaces are followed.
+func (s *slogState) value(v reflect.Value, depth int) slog.Value {
+	// Follow pointers and interfaces while detecting circular references.
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return slog.StringValue(string(nilAngleBytes))
+		}
+		if v.Kind() == reflect.Ptr {
+			if val, ok := s.methodValue(v); ok {
+				return val
+			}
+			addr := v.Pointer()
+			if s.pointers[addr] {
+				return slog.StringValue(string(circularBytes))
+			}
+			s.pointers[addr] = true
+			defer delete(s.pointers, addr)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return slog.StringValue(string(invalidAngleBytes))
+	}
+
+	switch v.Type() {
+	case timeType, durationType:
+		if iv, ok := interfaceValue(s.cs, v); ok && iv.CanInterface() {
+			return slog.AnyValue(reflect.Indirect(iv).Interface())
+		}
+	}
+	if val, ok := s.methodValue(v); ok {
+		return val
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return slog.BoolValue(v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return slog.Int64Value(v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return slog.Uint64Value(v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		return slog.StringValue(fmt.Sprint(v.Complex()))
+
+	case reflect.String:
+		return slog.StringValue(v.String())
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			return slog.StringValue(string(nilAngleBytes))
+		}
+		return slog.StringValue(fmt.Sprintf("%#x", v.Pointer()))
+	}
+
+	// The remaining kinds are composites which are converted into groups.
+	if s.cs.MaxDepth != 0 && depth >= s.cs.MaxDepth {
+		return slog.StringValue(string(truncationMarker(s.cs, maxShortBytes,
+			"MaxDepth", s.cs.MaxDepth)))
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return slog.StringValue(string(nilAngleBytes))
+		}
+		attrs := make([]slog.Attr, v.Len())
+		for i := range attrs {
+			attrs[i] = slog.Attr{
+				Key:   strconv.Itoa(i),
+				Value: s.value(v.Index(i), depth+1),
+			}
+		}
+		return slog.GroupValue(attrs...)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return slog.StringValue(string(nilAngleBytes))
+		}
+		keys := v.MapKeys()
+		if s.cs.SortKeys {
+			sortValues(keys, s.cs)
+		}
+		attrs := make([]slog.Attr, len(keys))
+		for i, key := range keys {
+			attrs[i] = slog.Attr{
+				Key:   s.key(key),
+				Value: s.value(v.MapIndex(key), depth+1),
+			}
+		}
+		return slog.GroupValue(attrs...)
+
+	case reflect.Struct:
+		fields := structFields(s.cs, v)
+		attrs := make([]slog.Attr, len(fields))
+		for i, sf := range fields {
+			attrs[i] = slog.Attr{
+				Key:   sf.name,
+				Value: s.value(sf.value, depth+1),
+			}
+		}
+		return slog.GroupValue(attrs...)
+	}
+
+	return slog.StringValue(fmt.Sprint(v))
+}
+
+// Value returns a slog.LogValuer which converts the passed value into nested
+// slog groups using the configuration in c.  Scalars become attributes of the
+// matching kind while structs, maps, slices, and arrays become groups keyed by
+// field name, map key, and index respectively, so structured log backends are
+// able to index the individual fields instead of storing one large string.
+// Types which implement slog.LogValuer themselves are converted using their
+// own LogValue method.
+//
+// The MaxDepth, DisableMethods, DisablePointerMethods, SortKeys, SpewKeys, and
+// FlattenEmbedded options are honored.
+func (c *ConfigState) Value(v interface{}) slog.LogValuer {
+	return logValuer{cs: c, v: v}
+}
+
+// Value returns a slog.LogValuer which converts the passed value into nested
+// slog groups using the global Config.  See ConfigState.Value for details.
+//
+// For example:
+//
+//	slog.Info("request received", "req", spew.Value(req))
+func Value(v interface{}) slog.LogValuer {
+	return globalConfig().Value(v)
+}
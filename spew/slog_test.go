@@ -0,0 +1,103 @@
+//go:build go1.21 && !spew_noop
+// +build go1.21,!spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// slogTester is used to test conversion of values into slog groups.
+type slogTester struct {
+	Name    string
+	Count   int
+	Ratio   float64
+	OK      bool
+	Tags    []string
+	Attrs   map[string]uint
+	Err     error
+	Timeout time.Duration
+	Next    *slogTester
+}
+
+// TestValue ensures values are converted into nested slog groups as intended.
+func TestValue(t *testing.T) {
+	v := &slogTester{
+		Name:    "a",
+		Count:   -2,
+		Ratio:   0.5,
+		OK:      true,
+		Tags:    []string{"x", "y"},
+		Attrs:   map[string]uint{"b": 2, "a": 1},
+		Err:     customError(1),
+		Timeout: time.Second,
+	}
+	v.Next = v
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	cs := spew.ConfigState{SortKeys: true}
+	logger.Info("msg", "v", cs.Value(v))
+	want := `{"level":"INFO","msg":"msg","v":{"Name":"a","Count":-2,` +
+		`"Ratio":0.5,"OK":true,"Tags":{"0":"x","1":"y"},` +
+		`"Attrs":{"a":1,"b":2},"Err":"error: 1","Timeout":1000000000,` +
+		`"Next":"<already shown>"}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Value:\n got: %s\nwant: %s", got, want)
+	}
+
+	five := 5
+	tests := []struct {
+		cs   *spew.ConfigState
+		in   interface{}
+		want slog.Value
+	}{
+		{&spew.ConfigState{}, 5, slog.Int64Value(5)},
+		{&spew.ConfigState{}, uint8(5), slog.Uint64Value(5)},
+		{&spew.ConfigState{}, "s", slog.StringValue("s")},
+		{&spew.ConfigState{}, (*int)(nil), slog.StringValue("<nil>")},
+		{&spew.ConfigState{}, stringer("x"), slog.StringValue("stringer x")},
+		{&spew.ConfigState{DisableMethods: true}, stringer("x"),
+			slog.StringValue("x")},
+		{&spew.ConfigState{MaxDepth: 1}, [][]int{{1}},
+			slog.GroupValue(slog.String("0", "<max>"))},
+		{&spew.ConfigState{Disabled: true}, 5, slog.GroupValue()},
+		{&spew.ConfigState{SpewKeys: true}, map[*int]int{&five: 1},
+			slog.GroupValue(slog.Int64("<*>5", 1))},
+	}
+	for i, test := range tests {
+		got := test.cs.Value(test.in).LogValue()
+		if !got.Equal(test.want) {
+			t.Errorf("Value #%d: got %v, want %v", i, got, test.want)
+		}
+	}
+}
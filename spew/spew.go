@@ -19,6 +19,8 @@ package spew
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
 // Errorf is a wrapper for fmt.Errorf that treats each argument as if it were
@@ -26,11 +28,18 @@ import (
 // returns the formatted string as a value that satisfies error.  See
 // NewFormatter for formatting details.
 //
+// Arguments for %w verbs are passed through unchanged so the returned error
+// wraps them as usual and works with errors.Is and errors.As.  The same is
+// true of the arguments for '*' widths and precisions.
+//
 // This function is shorthand for the following syntax:
 //
 //	fmt.Errorf(format, spew.NewFormatter(a), spew.NewFormatter(b))
 func Errorf(format string, a ...interface{}) (err error) {
-	return fmt.Errorf(format, convertArgs(a)...)
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return fmt.Errorf(format, a...)
+	}
+	return fmt.Errorf(format, unwrapArgs(format, a, convertArgs(a))...)
 }
 
 // Fprint is a wrapper for fmt.Fprint that treats each argument as if it were
@@ -42,6 +51,9 @@ func Errorf(format string, a ...interface{}) (err error) {
 //
 //	fmt.Fprint(w, spew.NewFormatter(a), spew.NewFormatter(b))
 func Fprint(w io.Writer, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return 0, nil
+	}
 	return fmt.Fprint(w, convertArgs(a)...)
 }
 
@@ -54,6 +66,9 @@ func Fprint(w io.Writer, a ...interface{}) (n int, err error) {
 //
 //	fmt.Fprintf(w, format, spew.NewFormatter(a), spew.NewFormatter(b))
 func Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return 0, nil
+	}
 	return fmt.Fprintf(w, format, convertArgs(a)...)
 }
 
@@ -65,6 +80,9 @@ func Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
 //
 //	fmt.Fprintln(w, spew.NewFormatter(a), spew.NewFormatter(b))
 func Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return 0, nil
+	}
 	return fmt.Fprintln(w, convertArgs(a)...)
 }
 
@@ -77,6 +95,9 @@ func Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
 //
 //	fmt.Print(spew.NewFormatter(a), spew.NewFormatter(b))
 func Print(a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return 0, nil
+	}
 	return fmt.Print(convertArgs(a)...)
 }
 
@@ -89,6 +110,9 @@ func Print(a ...interface{}) (n int, err error) {
 //
 //	fmt.Printf(format, spew.NewFormatter(a), spew.NewFormatter(b))
 func Printf(format string, a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return 0, nil
+	}
 	return fmt.Printf(format, convertArgs(a)...)
 }
 
@@ -101,6 +125,9 @@ func Printf(format string, a ...interface{}) (n int, err error) {
 //
 //	fmt.Println(spew.NewFormatter(a), spew.NewFormatter(b))
 func Println(a ...interface{}) (n int, err error) {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return 0, nil
+	}
 	return fmt.Println(convertArgs(a)...)
 }
 
@@ -112,6 +139,9 @@ func Println(a ...interface{}) (n int, err error) {
 //
 //	fmt.Sprint(spew.NewFormatter(a), spew.NewFormatter(b))
 func Sprint(a ...interface{}) string {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return ""
+	}
 	return fmt.Sprint(convertArgs(a)...)
 }
 
@@ -123,6 +153,9 @@ func Sprint(a ...interface{}) string {
 //
 //	fmt.Sprintf(format, spew.NewFormatter(a), spew.NewFormatter(b))
 func Sprintf(format string, a ...interface{}) string {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return ""
+	}
 	return fmt.Sprintf(format, convertArgs(a)...)
 }
 
@@ -134,9 +167,74 @@ func Sprintf(format string, a ...interface{}) string {
 //
 //	fmt.Sprintln(spew.NewFormatter(a), spew.NewFormatter(b))
 func Sprintln(a ...interface{}) string {
+	if NoopBuild || outputDisabled(globalConfig()) {
+		return ""
+	}
 	return fmt.Sprintln(convertArgs(a)...)
 }
 
+// rawArgIndices returns the indices of the arguments which must be passed to
+// fmt unchanged for the passed format specifier.  They are those consumed by
+// %w verbs and by '*' widths and precisions.  It follows the argument
+// numbering rules of the fmt package, including explicit argument indexes.
+func rawArgIndices(format string) []int {
+	var indices []int
+	argNum := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// Skip the flags, width, and precision while accounting for the
+		// arguments consumed by '*' and explicit argument indexes.
+	verbLoop:
+		for i++; i < len(format); i++ {
+			switch c := format[i]; {
+			case c == '[':
+				end := strings.IndexByte(format[i:], ']')
+				if end < 0 {
+					break verbLoop
+				}
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+					argNum = n - 1
+				}
+				i += end
+			case c == '*':
+				indices = append(indices, argNum)
+				argNum++
+			case strings.IndexByte("+-# 0.", c) >= 0, c >= '1' && c <= '9':
+			default:
+				break verbLoop
+			}
+		}
+		if i >= len(format) {
+			break
+		}
+
+		switch format[i] {
+		case '%':
+		case 'w':
+			indices = append(indices, argNum)
+			argNum++
+		default:
+			argNum++
+		}
+	}
+	return indices
+}
+
+// unwrapArgs replaces the converted arguments which are consumed by %w verbs
+// and '*' widths and precisions in the passed format specifier with the
+// original arguments so they may be wrapped or used by fmt.Errorf.
+func unwrapArgs(format string, args, converted []interface{}) []interface{} {
+	for _, index := range rawArgIndices(format) {
+		if index >= 0 && index < len(args) {
+			converted[index] = args[index]
+		}
+	}
+	return converted
+}
+
 // convertArgs accepts a slice of arguments and returns a slice of the same
 // length with each argument converted to a default spew Formatter interface.
 func convertArgs(args []interface{}) (formatters []interface{}) {
@@ -1,3 +1,6 @@
+//go:build !spew_noop
+// +build !spew_noop
+
 /*
  * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
  *
@@ -18,10 +21,21 @@ package spew_test
 
 import (
 	"bytes"
+	"container/list"
+	"container/ring"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 )
@@ -132,6 +146,127 @@ func initSpewTests() {
 	scsContinue := &spew.ConfigState{Indent: " ", ContinueOnMethod: true}
 	scsNoPtrAddr := &spew.ConfigState{DisablePointerAddresses: true}
 	scsNoCap := &spew.ConfigState{DisableCapacities: true}
+	scsMaxElems := &spew.ConfigState{Indent: " ", MaxElements: 2}
+	scsHeadTail := &spew.ConfigState{Indent: " ", MaxElements: 1,
+		TailElements: 1, SortKeys: true}
+	scsMaxStr := &spew.ConfigState{Indent: " ", MaxStringLength: 4}
+	scsRunes := &spew.ConfigState{Indent: " ", ShowRunes: true}
+	scsSummary := &spew.ConfigState{Indent: " ", SummarizeNumbers: 4}
+	scsUnderlying := &spew.ConfigState{Indent: " ", ShowUnderlyingTypes: true}
+	scsIfaceTypes := &spew.ConfigState{Indent: " ", ShowInterfaceTypes: true,
+		DisablePointerAddresses: true}
+	scsLayout := &spew.ConfigState{Indent: " ", ShowLayout: true}
+	scsTags := &spew.ConfigState{Indent: " ", ShowTags: true}
+	scsTagKeys := &spew.ConfigState{Indent: " ", ShowTags: true,
+		TagKeys: []string{"db", "json"}}
+	scsFlatten := &spew.ConfigState{Indent: " ", FlattenEmbedded: true}
+	scsNoContainers := &spew.ConfigState{Indent: " ", DisableContainerTraversal: true}
+	scsSnapshot := &spew.ConfigState{DisablePointerAddresses: true,
+		SnapshotFuncs: map[reflect.Type]func(v reflect.Value) interface{}{
+			reflect.TypeOf(lockedCounter{}): func(v reflect.Value) interface{} {
+				c := v.Interface().(*lockedCounter)
+				c.mu.Lock()
+				defer c.mu.Unlock()
+				return c.N
+			},
+		},
+	}
+	scsTypeDetails := &spew.ConfigState{Indent: " ", ShowTypeDetails: true}
+	scsPreferString := &spew.ConfigState{MethodPreference: spew.PreferString}
+	scsPreferBoth := &spew.ConfigState{MethodPreference: spew.PreferBoth}
+	scsEscape := &spew.ConfigState{EscapeMethodOutput: true}
+	scsJSON := &spew.ConfigState{Indent: " ", PrettyJSONThreshold: 10}
+	scsIter := &spew.ConfigState{Indent: " ", MaxIteratorElements: 2}
+	scsIndices := &spew.ConfigState{Indent: " ", ShowIndices: true}
+	scsOutput := &spew.ConfigState{Indent: " ", OutputFunc: strings.ToUpper}
+	scsSpare := &spew.ConfigState{Indent: " ", ShowSpareCapacity: true}
+	scsBase := &spew.ConfigState{Indent: " ", IntegerBase: 16,
+		TypeIntegerBases: map[reflect.Type]int{reflect.TypeOf(uint16(0)): 8}}
+	scsFloatF := &spew.ConfigState{Indent: " ", FloatFormat: 'f',
+		FloatPrecision: 2}
+	scsFloatG := &spew.ConfigState{Indent: " ", FloatPrecision: 3}
+	scsNonFinite := &spew.ConfigState{Indent: " ", MarkNonFinite: true,
+		SummarizeNumbers: 2}
+	scsDigits := &spew.ConfigState{Indent: " ", DigitSeparator: ","}
+	scsDigitsUnder := &spew.ConfigState{Indent: " ", DigitSeparator: "_"}
+	scsChars := &spew.ConfigState{Indent: " ", ShowCharacters: true}
+	scsTypedNil := &spew.ConfigState{Indent: " ", WarnTypedNil: true}
+	scsSortFields := &spew.ConfigState{Indent: " ", SortFields: true}
+	scsFieldLess := &spew.ConfigState{Indent: " ",
+		FieldLess: func(a, b reflect.StructField) bool {
+			return a.Type.Size() < b.Type.Size()
+		}}
+	scsAlignMap := &spew.ConfigState{Indent: " ", SortKeys: true,
+		AlignMapValues: true}
+	scsAlignMapElided := &spew.ConfigState{Indent: " ", SortKeys: true,
+		AlignMapValues: true, MaxElements: 1}
+	scsAlignFields := &spew.ConfigState{Indent: " ", AlignFields: true}
+	scsAlignFieldsTags := &spew.ConfigState{Indent: " ", AlignFields: true,
+		ShowTags: true}
+	scsPack := &spew.ConfigState{Indent: " ", PackWidth: 16}
+	scsPackSummary := &spew.ConfigState{Indent: " ", PackWidth: 40,
+		SummarizeNumbers: 2}
+	scsWrap := &spew.ConfigState{Indent: " ", WrapStrings: 4}
+	scsWrapByte := &spew.ConfigState{Indent: " ", WrapStrings: 1}
+	scsRaw := &spew.ConfigState{Indent: " ", RawStrings: true}
+	scsLineNums := &spew.ConfigState{Indent: " ", LineNumbers: true}
+	scsElide := &spew.ConfigState{Indent: " ", ElideElementTypes: true,
+		DisablePointerAddresses: true}
+	scsElideIdx := &spew.ConfigState{Indent: " ", ElideElementTypes: true,
+		ShowIndices: true}
+	scsSchema := &spew.ConfigState{Indent: " ", SchemaOnly: true}
+	scsAnon := &spew.ConfigState{Indent: " ", Anonymize: true,
+		DisablePointerAddresses: true}
+	scsLengths := &spew.ConfigState{Indent: " ", ShowLengths: true}
+	scsSniff := &spew.ConfigState{Indent: " ", SniffBytes: true}
+	scsSkip := &spew.ConfigState{Indent: " ", DisablePointerAddresses: true,
+		SkipTypes: map[reflect.Type]bool{
+			reflect.TypeOf((*context.Context)(nil)).Elem(): true,
+			reflect.TypeOf(time.Time{}):                    true,
+		}}
+	scsInclude := &spew.ConfigState{Indent: " ",
+		IncludeTypes: map[reflect.Type]bool{reflect.TypeOf(""): true}}
+	scsNilColls := &spew.ConfigState{Indent: " ", ExplicitNilCollections: true}
+	scsScrub := &spew.ConfigState{Indent: " ", Scrubbers: []spew.Scrubber{
+		spew.ScrubAddresses, spew.ScrubUUIDs, spew.ScrubTimestamps,
+		{regexp.MustCompile(`host-(\d+)`), "host-N"}}}
+	scsExplain := &spew.ConfigState{Indent: " ", MaxDepth: 1, MaxElements: 2,
+		MaxStringLength: 4, ExplainTruncation: true}
+	scsTransform := &spew.ConfigState{Indent: " ",
+		TransformFunc: func(v reflect.Value) (reflect.Value, bool) {
+			switch v.Type() {
+			case reflect.TypeOf(wrapper{}):
+				return v.Field(0), true
+			case reflect.TypeOf(customError(0)):
+				ce := customError(v.Int())
+				return reflect.ValueOf([]customError{ce, 0}), true
+			}
+			return v, false
+		}}
+	redactPaths := map[string]bool{"Password": true, "[token]": true,
+		"Keys[1]": true}
+	scsTypeFmt := &spew.ConfigState{Indent: " ",
+		TypeFormatters: map[reflect.Type]func(fs fmt.State, v reflect.Value){
+			reflect.TypeOf(customError(0)): func(fs fmt.State, v reflect.Value) {
+				if fs.Flag('+') {
+					fmt.Fprint(fs, "E:")
+				}
+				fmt.Fprintf(fs, "%d", v.Int())
+			},
+		}}
+	scsRedact := &spew.ConfigState{Indent: " ", RedactFunc: func(path string,
+		t reflect.Type, v reflect.Value) (string, bool) {
+		return "<redacted>", redactPaths[path]
+	}}
+	scsRedactMap := &spew.ConfigState{Indent: " ", SortKeys: true,
+		RedactFunc: func(path string, t reflect.Type,
+			v reflect.Value) (string, bool) {
+
+			return "<redacted>", strings.HasPrefix(path, "Env[") ||
+				path == "Env{b}"
+		}}
+	scsRedactAlign := &spew.ConfigState{Indent: " ", SortKeys: true,
+		AlignMapValues: true, RedactFunc: scsRedactMap.RedactFunc}
 
 	// Variables for tests on types which implement Stringer interface with and
 	// without a pointer receiver.
@@ -157,11 +292,328 @@ func initSpewTests() {
 	// Variable for tests on types which implement error interface.
 	te := customError(10)
 
+	// Variable for tests on display of static interface types.
+	type ifaceTester struct {
+		E error
+		S fmt.Stringer
+		A interface{}
+	}
+	tit := ifaceTester{te, &ts, 1}
+
+	// Variable for tests on display of struct memory layout.
+	type layoutTester struct {
+		A uint8
+		B int32
+		C uint16
+	}
+	tlt := layoutTester{1, 2, 3}
+
+	// Variable for tests on display of struct tags.
+	type tagTester struct {
+		A int `json:"a,omitempty" xml:"a" db:"col_a"`
+		B int
+	}
+	ttt := tagTester{1, 2}
+
+	// Variables for tests on flattening of embedded structs.
+	type Base struct {
+		ID   int
+		Name string
+	}
+	type Meta struct {
+		ID int
+	}
+	type flattenTester struct {
+		Base
+		*Meta
+		Name string
+	}
+	tft := flattenTester{Base{1, "base"}, &Meta{2}, "outer"}
+
+	// Variables for tests on snapshots of concurrently accessed values.
+	var av atomic.Value
+	av.Store("stored")
+	lc := &lockedCounter{N: 3}
+
+	// Variables for tests on container traversal.
+	lst := list.New()
+	lst.PushBack(1)
+	lst.PushBack("a")
+	rng := ring.New(3)
+	for i := 0; i < 3; i++ {
+		rng.Value = i
+		rng = rng.Next()
+	}
+
+	// Variables for tests on draining iterator functions.
+	seq := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 1; i <= n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+	seq2 := func(yield func(string, interface{}) bool) {
+		_ = yield("a", 1) && yield("b", nil)
+	}
+	panicSeq := func(yield func(int) bool) { panic("drained") }
+
+	// Variables for tests on circular references which share an address
+	// with other values.
+	type addrInner struct{ N int }
+	type addrOuter struct {
+		In addrInner
+		P  *addrInner
+	}
+	ao := &addrOuter{In: addrInner{1}}
+	ao.P = &ao.In
+	cs := []interface{}{nil, 1}
+	cs[0] = cs
+
+	// Variables for tests on the formatting of floats.
+	tfloat := 0.1
+	tfloat += 0.2
+
+	// Variables for tests on the base integers are displayed in.
+	type integerBases struct {
+		Hex int
+		Bin []uint32 `spew:"bin"`
+		Dec int      `spew:"dec"`
+	}
+	tbases := integerBases{31, []uint32{5, 0}, 31}
+
+	// Variables for tests on warning about nil pointers held by interfaces.
+	type typedNils struct {
+		Any  interface{}
+		Ptr  *int
+		None interface{}
+	}
+	ttypedNils := typedNils{Any: (*int)(nil)}
+
+	// Variables for tests on the order struct fields are displayed in.
+	type fieldOrder struct {
+		Zeta  int8
+		Alpha string
+		Mid   int64
+	}
+	tfieldOrder := fieldOrder{1, "a", 2}
+
+	// Variables for tests on aligning the values of struct fields.
+	type alignedFields struct {
+		ID          int
+		DisplayName string `json:"name"`
+		Nested      struct{ A, Longer int }
+	}
+	talignedFields := alignedFields{1, "a", struct{ A, Longer int }{2, 3}}
+
+	// Variable for tests on eliding the types of elements.
+	elideInt := 5
+
+	// Variables for tests on displaying the spare capacity of slices.
+	spare := []int{1, 2, 3, 0}[:2]
+	spareBytes := []byte("abcd")[:1]
+
+	// Variables for tests on redaction of leaf values.
+	type credentials struct {
+		User     string
+		Password string
+		Keys     []string
+	}
+	tcreds := credentials{"admin", "hunter2", []string{"a", "b"}}
+	tenv := map[string]string{"token": "secret"}
+	type envHolder struct {
+		Env map[string]string
+	}
+	tenvs := envHolder{map[string]string{"a": "1", "b": "2"}}
+
+	// Variables for tests on types which implement SpewRedactor interface
+	// with and without a pointer receiver.
+	type redactorTester struct {
+		R redactor
+		P predactor
+	}
+	trt := redactorTester{"secret", predactor{"admin", "hunter2"}}
+
 	spewTests = []spewTest{
 		{scsDefault, fCSFdump, "", int8(127), "(int8) 127\n"},
 		{scsDefault, fCSFprint, "", int16(32767), "32767"},
 		{scsDefault, fCSFprintf, "%v", int32(2147483647), "2147483647"},
 		{scsDefault, fCSFprintln, "", int(2147483647), "2147483647\n"},
+		{scsDefault, fCSFprint, "", tfloat, "0.30000000000000004"},
+		{scsFloatF, fCSSdump, "", tfloat, "(float64) 0.30\n"},
+		{scsFloatF, fCSFprint, "", complex64(1.5 - 2i), "(1.50-2.00i)"},
+		{scsFloatG, fCSFprint, "", []float32{1234.5678, 0.5}, "[1.23e+03 0.5]"},
+		{scsNonFinite, fCSSdump, "", math.NaN(), "(float64) <NaN>\n"},
+		{scsNonFinite, fCSFprint, "", []float64{math.Inf(-1), 1}, "[<-Inf> 1]"},
+		{scsNonFinite, fCSSdump, "", []float64{math.Inf(1), 1, 2},
+			"([]float64) (len=3 cap=3) {\n <min=1 max=<+Inf> mean=<+Inf> " +
+				"non-finite=1 samples=[<+Inf> 1 2]>\n}\n"},
+		{scsDefault, fCSFprint, "", math.Inf(1), "+Inf"},
+		{scsChars, fCSSdump, "", byte('A'), "(uint8) 65 'A'\n"},
+		{scsChars, fCSSdump, "", 'é', "(int32) 233 'é'\n"},
+		{scsChars, fCSSdump, "", byte(0xff), "(uint8) 255\n"},
+		{scsChars, fCSFprint, "", []rune("a\n"), "[97 'a' 10]"},
+		{scsChars, fCSFprint, "", int16(65), "65"},
+		{scsTypedNil, fCSSdump, "", ttypedNils, "(spew_test.typedNils) {\n" +
+			" Any: (*int)(<nil>) ⚠ non-nil interface wrapping nil,\n" +
+			" Ptr: (*int)(<nil>),\n None: (interface {}) <nil>\n}\n"},
+		{scsTypedNil, fCSSdump, "", (*int)(nil),
+			"(*int)(<nil>) ⚠ non-nil interface wrapping nil\n"},
+		{scsTypedNil, fCSFprint, "", ttypedNils,
+			"{<nil> ⚠ non-nil interface wrapping nil <nil> <nil>}"},
+		{scsTypedNil, fCSFprintf, "%#v", []interface{}{(*int)(nil)},
+			"([]interface {})[(*int)<nil> ⚠ non-nil interface wrapping nil]"},
+		{scsDefault, fCSSdump, "", ttypedNils.Any, "(*int)(<nil>)\n"},
+		{scsSortFields, fCSSdump, "", tfieldOrder, "(spew_test.fieldOrder) {\n" +
+			" Alpha: (string) (len=1) \"a\",\n Mid: (int64) 2,\n" +
+			" Zeta: (int8) 1\n}\n"},
+		{scsFieldLess, fCSSdump, "", tfieldOrder, "(spew_test.fieldOrder) {\n" +
+			" Zeta: (int8) 1,\n Mid: (int64) 2,\n" +
+			" Alpha: (string) (len=1) \"a\"\n}\n"},
+		{scsAlignMap, fCSSdump, "", map[string]string{"host": "a", "port_number": "80"},
+			"(map[string]string) (len=2) {\n" +
+				" (string) (len=4) \"host\":         (string) (len=1) \"a\",\n" +
+				" (string) (len=11) \"port_number\": (string) (len=2) \"80\"\n}\n"},
+		{scsAlignMapElided, fCSSdump, "", map[int]int{1: 2, 1000: 3},
+			"(map[int]int) (len=2) {\n (int) 1: (int) 2,\n <1 element omitted>\n}\n"},
+		{scsAlignMap, fCSSdump, "", map[[1]int]int{{1}: 2},
+			"(map[[1]int]int) (len=1) {\n ([1]int) (len=1 cap=1) {\n  (int) 1\n }: (int) 2\n}\n"},
+		{scsAlignFields, fCSSdump, "", talignedFields, "(spew_test.alignedFields) {\n" +
+			" ID:          (int) 1,\n" +
+			" DisplayName: (string) (len=1) \"a\",\n" +
+			" Nested:      (struct { A int; Longer int }) {\n" +
+			"  A:      (int) 2,\n  Longer: (int) 3\n }\n}\n"},
+		{scsAlignFieldsTags, fCSSdump, "", talignedFields, "(spew_test.alignedFields) {\n" +
+			" ID:                        (int) 1,\n" +
+			" DisplayName `json:\"name\"`: (string) (len=1) \"a\",\n" +
+			" Nested:                    (struct { A int; Longer int }) {\n" +
+			"  A:      (int) 2,\n  Longer: (int) 3\n }\n}\n"},
+		{scsPack, fCSSdump, "", [4]float64{1, 2.5, 3, 4},
+			"([4]float64) (len=4 cap=4) {1, 2.5, 3, 4}\n"},
+		{scsPack, fCSSdump, "", []bool{true, false}, "([]bool) (len=2 cap=2) {true, false}\n"},
+		{scsPack, fCSSdump, "", []int{1, 2, 3, 4, 5, 6},
+			"([]int) (len=6 cap=6) {\n (int) 1,\n (int) 2,\n (int) 3,\n" +
+				" (int) 4,\n (int) 5,\n (int) 6\n}\n"},
+		{scsPack, fCSSdump, "", struct{ P [2]int }{[2]int{1, 2}},
+			"(struct { P [2]int }) {\n P: ([2]int) (len=2 cap=2) {1, 2}\n}\n"},
+		{scsPack, fCSSdump, "", []byte{1}, "([]uint8) (len=1 cap=1) {\n" +
+			" 00000000  01                                                |.|\n}\n"},
+		{scsPackSummary, fCSSdump, "", []int{1, 2}, "([]int) (len=2 cap=2) {1, 2}\n"},
+		{scsPackSummary, fCSSdump, "", []int{1, 2, 3}, "([]int) (len=3 cap=3) {\n" +
+			" <min=1 max=3 mean=2 samples=[1 2 3]>\n}\n"},
+		{scsWrap, fCSSdump, "", "abcd", "(string) (len=4) \"abcd\"\n"},
+		{scsWrap, fCSSdump, "", "abcdefghij", "(string) (len=10) \"abcd\" +\n" +
+			" \"efgh\" +\n \"ij\"\n"},
+		{scsWrap, fCSSdump, "", struct{ S string }{"abcéd"},
+			"(struct { S string }) {\n S: (string) (len=6) \"abc\" +\n  \"éd\"\n}\n"},
+		{scsWrapByte, fCSSdump, "", "é", "(string) (len=2) \"é\"\n"},
+		{scsWrapByte, fCSSdump, "", "aé", "(string) (len=3) \"a\" +\n \"é\"\n"},
+		{scsRaw, fCSSdump, "", "a\n\tb", "(string) (len=4) `a\n \tb`\n"},
+		{scsRaw, fCSSdump, "", struct{ S string }{"a\nb"},
+			"(struct { S string }) {\n S: (string) (len=3) `a\n  b`\n}\n"},
+		{scsRaw, fCSSdump, "", "ab", "(string) (len=2) \"ab\"\n"},
+		{scsRaw, fCSSdump, "", "a\n`b`", "(string) (len=5) \"a\\n`b`\"\n"},
+		{scsRaw, fCSSdump, "", "a\r\nb", "(string) (len=4) \"a\\r\\nb\"\n"},
+		{scsLineNums, fCSSdump, "", []int{1, 2}, "   1 | ([]int) (len=2 cap=2) {\n" +
+			"   2 |  (int) 1,\n   3 |  (int) 2\n   4 | }\n"},
+		{scsLineNums, fCSSdump, "", "a", "   1 | (string) (len=1) \"a\"\n"},
+		{scsElide, fCSSdump, "", []int{1, 2}, "([]int) (len=2 cap=2) {\n 1,\n 2\n}\n"},
+		{scsElide, fCSSdump, "", []interface{}{1, 2},
+			"([]interface {}) (len=2 cap=2 elem=int) {\n 1,\n 2\n}\n"},
+		{scsElide, fCSSdump, "", []interface{}{1, "a"},
+			"([]interface {}) (len=2 cap=2) {\n (int) 1,\n (string) (len=1) \"a\"\n}\n"},
+		{scsElide, fCSSdump, "", []*int{&elideInt, nil},
+			"([]*int) (len=2 cap=2) {\n (5),\n (<nil>)\n}\n"},
+		{scsElideIdx, fCSSdump, "", [][]int{{1}},
+			"([][]int) (len=1 cap=1) {\n [0]: (len=1 cap=1) {\n  [0]: 1\n }\n}\n"},
+		{scsSchema, fCSSdump, "", struct {
+			N int
+			S string
+			B []byte
+			P *int
+		}{1, "ab", []byte{1}, &elideInt},
+			"(struct { N int; S string; B []uint8; P *int }) {\n" +
+				" N: (int) <elided>,\n S: (string) (len=2) <elided>,\n" +
+				" B: ([]uint8) (len=1 cap=1) {\n  <elided>\n },\n" +
+				" P: (*int)(<elided>)\n}\n"},
+		{scsSchema, fCSSdump, "", map[string]error{"k": errors.New("e")},
+			"(map[string]error) (len=1) {\n" +
+				" (string) (len=1) <elided>: (*errors.errorString)({\n" +
+				"  s: (string) (len=1) <elided>\n })\n}\n"},
+		{scsAnon, fCSSdump, "", struct {
+			A, B, C string
+			N       float64
+			T       bool
+			X       []byte
+		}{"alice", "bob", "alice", 1.5, false, []byte{0xff}},
+			"(struct { A string; B string; C string; N float64; T bool; X []uint8 }) {\n" +
+				" A: (string) (len=5) \"str#1\",\n B: (string) (len=3) \"str#2\",\n" +
+				" C: (string) (len=5) \"str#1\",\n N: (float64) 0,\n T: (bool) true,\n" +
+				" X: ([]uint8) (len=1 cap=1) {\n" +
+				"  00000000  00                                                |.|\n }\n}\n"},
+		{scsAnon, fCSSdump, "", errors.New("secret"),
+			"(*errors.errorString)({\n s: (string) (len=6) \"str#1\"\n})\n"},
+		{scsLengths, fCSSdump, "", "", "(string) (len=0) \"\"\n"},
+		{scsLengths, fCSSdump, "", map[string]int{}, "(map[string]int) (len=0) {\n}\n"},
+		{scsLengths, fCSSdump, "", map[string]int(nil), "(map[string]int) (len=0) <nil>\n"},
+		{scsLengths, fCSSdump, "", []int{}, "([]int) {\n}\n"},
+		{scsSniff, fCSSdump, "", []byte("héllo\n"),
+			"([]uint8) (len=7 cap=7) {\n \"héllo\\n\" (utf8 text)\n}\n"},
+		{scsSniff, fCSSdump, "", []byte("abc\x00"),
+			"([]uint8) (len=4 cap=4) {\n \"abc\\x00\" (text, 1 of 4 bytes escaped)\n}\n"},
+		{scsSniff, fCSSdump, "", []byte{0, 1, 'a'}, "([]uint8) (len=3 cap=3) {\n" +
+			" (binary, 2 of 3 bytes not printable)\n" +
+			" 00000000  00 01 61                                          |..a|\n}\n"},
+		{scsSkip, fCSSdump, "", struct {
+			Ctx context.Context
+			T   *time.Time
+			N   int
+		}{context.Background(), &time.Time{}, 1},
+			"(struct { Ctx context.Context; T *time.Time; N int }) {\n" +
+				" Ctx: (context.Context) <skipped>,\n T: (*time.Time)(<skipped>),\n" +
+				" N: (int) 1\n}\n"},
+		{scsInclude, fCSSdump, "", []interface{}{"a", 1},
+			"([]interface {}) (len=2 cap=2) {\n (string) (len=1) \"a\",\n (int) <skipped>\n}\n"},
+		{scsSkip, fCSFprint, "", struct {
+			Ctx context.Context
+			N   int
+		}{context.Background(), 1}, "{<skipped> 1}"},
+		{scsSkip, fCSFprintf, "%#v", struct {
+			Ctx context.Context
+			T   *time.Time
+		}{context.Background(), &time.Time{}}, "(struct { Ctx context.Context; T *time.Time })" +
+			"{Ctx:(context.Context)<skipped> T:(*time.Time)<skipped>}"},
+		{scsInclude, fCSFprint, "", []interface{}{"a", 1}, "[a <skipped>]"},
+		{scsInclude, fCSFprintf, "%#v", []interface{}{"a", 1}, "([]interface {})[(string)a (int)<skipped>]"},
+		{scsNilColls, fCSSdump, "", map[string]int(nil), "(map[string]int)(nil)\n"},
+		{scsNilColls, fCSSdump, "", map[string]int{}, "(map[string]int) {}\n"},
+		{scsNilColls, fCSSdump, "", struct{ S, E []int }{nil, []int{}},
+			"(struct { S []int; E []int }) {\n S: ([]int)(nil),\n E: ([]int) {}\n}\n"},
+		{scsNilColls, fCSSdump, "", make([]int, 0, 2), "([]int) (cap=2) {}\n"},
+		{scsNilColls, fCSFprint, "", struct {
+			M map[string]int
+			S []int
+		}{nil, []int{}}, "{map[string]int(nil) []}"},
+		{scsNilColls, fCSFprintf, "%#v", map[string]int(nil), "(map[string]int)(nil)"},
+		{scsNilColls, fCSFprint, "", map[string]int{}, "map[]"},
+		{scsDigits, fCSSdump, "", 1234567, "(int) 1,234,567\n"},
+		{scsDigits, fCSFprint, "", int64(-123456), "-123,456"},
+		{scsDigits, fCSFprint, "", uint8(255), "255"},
+		{scsDigitsUnder, fCSFprint, "", uint64(1000), "1_000"},
+		{scsDigits, fCSSdump, "", make([]bool, 0, 1500),
+			"([]bool) (cap=1,500) {\n}\n"},
+		{scsDigitsUnder, fCSSdump, "", strings.Repeat("a", 1000),
+			"(string) (len=1_000) \"" + strings.Repeat("a", 1000) + "\"\n"},
+		{scsBase, fCSSdump, "", -255, "(int) -0xff\n"},
+		{scsBase, fCSSdump, "", uint16(8), "(uint16) 0o10\n"},
+		{scsBase, fCSSdump, "", tbases, "(spew_test.integerBases) {\n" +
+			" Hex: (int) 0x1f,\n Bin: ([]uint32) (len=2 cap=2) {\n" +
+			"  (uint32) 0b101,\n  (uint32) 0b0\n },\n Dec: (int) 31\n}\n"},
+		{scsBase, fCSFprint, "", tbases, "{0x1f [0b101 0b0] 31}"},
+		{scsDefault, fCSFprint, "", tbases, "{31 [0b101 0b0] 31}"},
+		{scsBase, fCSFprint, "", int64(math.MinInt64), "-0x8000000000000000"},
 		{scsDefault, fCSPrint, "", int64(9223372036854775807), "9223372036854775807"},
 		{scsDefault, fCSPrintln, "", uint8(255), "255\n"},
 		{scsDefault, fCSSdump, "", uint8(64), "(uint8) 64\n"},
@@ -193,6 +645,8 @@ func initSpewTests() {
 			" arr: ([1]string) (len=1 cap=1) {\n  <max depth reached>\n },\n" +
 			" slice: ([]string) (len=1 cap=1) {\n  <max depth reached>\n },\n" +
 			" m: (map[string]int) (len=1) {\n  <max depth reached>\n }\n}\n"},
+		{scsMaxDepth, fCSSdump, "", struct{ D dumper }{dumper{"a"}},
+			"(struct { D spew_test.dumper }) {\n D: (spew_test.dumper) <max>\n}\n"},
 		{scsContinue, fCSFprint, "", ts, "(stringer test) test"},
 		{scsContinue, fCSFdump, "", ts, "(spew_test.stringer) " +
 			"(len=4) (stringer test) \"test\"\n"},
@@ -200,9 +654,201 @@ func initSpewTests() {
 		{scsContinue, fCSFdump, "", te, "(spew_test.customError) " +
 			"(error: 10) 10\n"},
 		{scsNoPtrAddr, fCSFprint, "", tptr, "<*>{<*>{}}"},
+		{scsNoPtrAddr, fCSFprint, "", ao, "<*>{{1} <*>{1}}"},
+		{scsNoPtrAddr, fCSSdump, "", ao, "(*spew_test.addrOuter)({\nIn: " +
+			"(spew_test.addrInner) {\nN: (int) 1\n},\nP: (*spew_test.addrInner)" +
+			"({\nN: (int) 1\n})\n})\n"},
+		{scsNoPtrAddr, fCSFprint, "", cs, "[<already shown> 1]"},
+		{scsNoPtrAddr, fCSSdump, "", cs, "([]interface {}) (len=2 cap=2) {\n" +
+			"([]interface {}) (len=2 cap=2) <already shown>,\n(int) 1\n}\n"},
 		{scsNoPtrAddr, fCSSdump, "", tptr, "(*spew_test.ptrTester)({\ns: (*struct {})({\n})\n})\n"},
 		{scsNoCap, fCSSdump, "", make([]string, 0, 10), "([]string) {\n}\n"},
 		{scsNoCap, fCSSdump, "", make([]string, 1, 10), "([]string) (len=1) {\n(string) \"\"\n}\n"},
+		{scsTypeFmt, fCSFprint, "", te, "10"},
+		{scsTypeFmt, fCSSprintf, "%+v", te, "E:10"},
+		{scsTypeFmt, fCSSprintf, "%#v", &te, "(*spew_test.customError)10"},
+		{scsTypeFmt, fCSSprint, "", []customError{1, 2}, "[1 2]"},
+		{scsTypeFmt, fCSSdump, "", te, "(spew_test.customError) error: 10\n"},
+		{scsMaxElems, fCSFprint, "", []int{1, 2, 3, 4}, "[1 2 <2 elements omitted>]"},
+		{scsMaxElems, fCSFprint, "", []int{1, 2}, "[1 2]"},
+		{scsMaxElems, fCSSdump, "", [4]int{1, 2, 3, 4}, "([4]int) (len=4 cap=4) {\n" +
+			" (int) 1,\n (int) 2,\n <2 elements omitted>\n}\n"},
+		{scsHeadTail, fCSFprint, "", []int{1, 2, 3, 4}, "[1 <2 elements omitted> 4]"},
+		{scsHeadTail, fCSFprint, "", []int{1, 2}, "[1 2]"},
+		{scsHeadTail, fCSFprint, "", map[int]int{1: 1, 2: 2, 3: 3},
+			"map[1:1 <1 element omitted> 3:3]"},
+		{scsHeadTail, fCSSdump, "", map[int]int{1: 1, 2: 2, 3: 3}, "(map[int]int) (len=3) {\n" +
+			" (int) 1: (int) 1,\n <1 element omitted>,\n (int) 3: (int) 3\n}\n"},
+		{scsMaxStr, fCSFprint, "", "abcdefgh", "abcd...(len=8)"},
+		{scsMaxStr, fCSFprint, "", "abcd", "abcd"},
+		{scsMaxStr, fCSFprint, "", "abcé", "abc...(len=5)"},
+		{scsMaxStr, fCSSdump, "", "abcdefgh", "(string) (len=8) \"abcd\"...(len=8)\n"},
+		{scsRunes, fCSFprint, "", "cafe\u0301!", "\"cafe\" '\u0301' U+0301 \"!\""},
+		{scsRunes, fCSFprint, "", "é\xffx", "'é' U+00E9 \"\\xffx\""},
+		{scsRunes, fCSFprint, "", "abc", "abc"},
+		{scsRunes, fCSSdump, "", "café", "(string) (len=5) \"caf\" 'é' U+00E9\n"},
+		{scsSummary, fCSSdump, "", []int{5, -2, 9, 1, 3, 4, 7, 0}, "([]int) (len=8 cap=8) {\n" +
+			" <min=-2 max=9 mean=3.375 samples=[5 -2 9 ... 4 7 0]>\n}\n"},
+		{scsSummary, fCSSdump, "", [5]float32{0.5, 1, 1.5, 2, 2.5}, "([5]float32) (len=5 cap=5) {\n" +
+			" <min=0.5 max=2.5 mean=1.5 samples=[0.5 1 1.5 2 2.5]>\n}\n"},
+		{scsSummary, fCSSdump, "", []uint16{1, 2, 3, 4}, "([]uint16) (len=4 cap=4) {\n" +
+			" (uint16) 1,\n (uint16) 2,\n (uint16) 3,\n (uint16) 4\n}\n"},
+		{scsUnderlying, fCSSdump, "", te, "(spew_test.customError=int) error: 10\n"},
+		{scsUnderlying, fCSSdump, "", []stringer{"a"}, "([]spew_test.stringer) (len=1 cap=1) {\n" +
+			" (spew_test.stringer=string) (len=1) stringer a\n}\n"},
+		{scsUnderlying, fCSSprintf, "%#v", &te, "(*spew_test.customError=int)error: 10"},
+		{scsUnderlying, fCSSdump, "", int8(1), "(int8) 1\n"},
+		{scsIfaceTypes, fCSSdump, "", tit, "(spew_test.ifaceTester) {\n" +
+			" E: (error ⇒ spew_test.customError) error: 10,\n" +
+			" S: (fmt.Stringer ⇒ *spew_test.stringer)((len=4) stringer test),\n" +
+			" A: (int) 1\n}\n"},
+		{scsIfaceTypes, fCSSprintf, "%#v", tit, "(spew_test.ifaceTester){" +
+			"E:(error ⇒ spew_test.customError)error: 10 " +
+			"S:(fmt.Stringer ⇒ *spew_test.stringer)stringer test A:(int)1}"},
+		{scsLayout, fCSSdump, "", tlt, "(spew_test.layoutTester) {\n" +
+			" A (offset=0 size=1): (uint8) 1,\n" +
+			" B (offset=4 size=4): (int32) 2,\n" +
+			" C (offset=8 size=2): (uint16) 3,\n" +
+			" <size=12 padding=5 holes=[1:3 10:2]>\n}\n"},
+		{scsLayout, fCSSdump, "", struct{}{}, "(struct {}) {\n <size=0 padding=0>\n}\n"},
+		{scsTags, fCSSdump, "", ttt, "(spew_test.tagTester) {\n" +
+			" A `json:\"a,omitempty\" xml:\"a\" db:\"col_a\"`: (int) 1,\n" +
+			" B: (int) 2\n}\n"},
+		{scsTagKeys, fCSSdump, "", ttt, "(spew_test.tagTester) {\n" +
+			" A `db:\"col_a\" json:\"a,omitempty\"`: (int) 1,\n" +
+			" B: (int) 2\n}\n"},
+		{scsDefault, fCSSdump, "", []multiline{"a\nb\n"}, "([]spew_test.multiline) (len=1 cap=1) {\n" +
+			" (spew_test.multiline) (len=4) a\n  b\n\n}\n"},
+		{scsDefault, fCSSdump, "", multiline("a\nb"), "(spew_test.multiline) (len=3) a\n b\n"},
+		{scsDefault, fCSSprint, "", []multiline{"a\nb"}, "[a\nb]"},
+		{scsJSON, fCSSdump, "", struct{ P string }{`{"a":1,"b":[true,null]}`},
+			"(struct { P string }) {\n P: (string) (len=23) (json) {\n" +
+				"  \"a\": 1,\n  \"b\": [\n   true,\n   null\n  ]\n }\n}\n"},
+		{scsJSON, fCSSdump, "", `{"a":1`, "(string) (len=6) \"{\\\"a\\\":1\"\n"},
+		{scsJSON, fCSSdump, "", `{"a": "not json"`, "(string) (len=16) \"{\\\"a\\\": \\\"not json\\\"\"\n"},
+		{scsJSON, fCSSdump, "", "plain text here", "(string) (len=15) \"plain text here\"\n"},
+		{scsJSON, fCSSprint, "", `{"a":1,"b":2}`, `{"a":1,"b":2}`},
+		{scsEscape, fCSSprint, "", multiline("\x1b[31mred\r\nINFO fake\u202e\xff é"),
+			`\x1b[31mred\r\nINFO fake\u202e\xff é`},
+		{scsEscape, fCSSdump, "", multiline("a\tb"), "(spew_test.multiline) (len=3) a\\tb\n"},
+		{scsDefault, fCSSdump, "", detailedError(7), "(spew_test.detailedError) failed\n"},
+		{scsPreferString, fCSSdump, "", detailedError(7), "(spew_test.detailedError) failed: code 7\n"},
+		{scsPreferString, fCSSprint, "", stringer("x"), "stringer x"},
+		{scsPreferBoth, fCSSprint, "", detailedError(7), "failed / failed: code 7"},
+		{scsPreferBoth, fCSSprint, "", customError(7), "error: 7"},
+		{scsDefault, fCSSdump, "", reflect.TypeOf(0), "(reflect.Type) int (kind=int)\n"},
+		{scsDefault, fCSSdump, "", []reflect.Type{reflect.TypeOf("")},
+			"([]reflect.Type) (len=1 cap=1) {\n (reflect.Type) string (kind=string)\n}\n"},
+		{scsDefault, fCSSprint, "", reflect.TypeOf(embed{}), "spew_test.embed"},
+		{scsDefault, fCSSprintf, "%#v", reflect.TypeOf(0), "(reflect.Type)int"},
+		{scsTypeDetails, fCSSdump, "", reflect.TypeOf(customError(0)),
+			"(reflect.Type) spew_test.customError (kind=int) {\n" +
+				" Methods: {\n  Error() string\n }\n}\n"},
+		{scsTypeDetails, fCSSdump, "", reflect.TypeOf(struct {
+			A int
+			b []string
+		}{}), "(reflect.Type) struct { A int; b []string } (kind=struct) {\n" +
+			" Fields: {\n  A int,\n  b []string\n },\n" +
+			" Methods: {\n }\n}\n"},
+		{scsTypeDetails, fCSSdump, "", reflect.TypeOf((*io.ReadWriter)(nil)).Elem(),
+			"(reflect.Type) io.ReadWriter (kind=interface) {\n" +
+				" Methods: {\n  Read([]uint8) (int, error),\n" +
+				"  Write([]uint8) (int, error)\n }\n}\n"},
+		{scsSnapshot, fCSSprint, "", &av, "<*>stored"},
+		{scsSnapshot, fCSSprint, "", []*lockedCounter{lc}, "[<*>3]"},
+		{scsDefault, fCSSdump, "", *lst, "(list.List) (len=2) {\n" +
+			" (int) 1,\n (string) (len=1) \"a\"\n}\n"},
+		{scsDefault, fCSSdump, "", list.List{}, "(list.List) {\n}\n"},
+		{scsDefault, fCSSprint, "", *lst, "[1 a]"},
+		{scsDefault, fCSSprint, "", rng.Next(), "<*>[1 2 0]"},
+		{scsDefault, fCSSprint, "", *rng, "[0 1 2]"},
+		{scsDefault, fCSSprint, "", ring.Ring{Value: 5}, "[5]"},
+		{scsNoContainers, fCSSprint, "", ring.Ring{Value: 5}, "{<nil> <nil> 5}"},
+		{scsIter, fCSSdump, "", seq(3), "(func(func(int) bool)) (sampled=2+) {\n" +
+			" (int) 1,\n (int) 2\n}\n"},
+		{scsIter, fCSSdump, "", seq(0), "(func(func(int) bool)) (sampled=0) {\n}\n"},
+		{scsIter, fCSSdump, "", seq2, "(func(func(string, interface {}) bool)) (sampled=2) {\n" +
+			" (string) (len=1) \"a\": (int) 1,\n" +
+			" (string) (len=1) \"b\": (interface {}) <nil>\n}\n"},
+		{scsIter, fCSSdump, "", panicSeq, "(func(func(int) bool)) (PANIC=drained)\n"},
+		{scsIter, fCSSprint, "", seq(3), "[1 2 ...]"},
+		{scsIter, fCSSprint, "", seq(1), "[1]"},
+		{scsIter, fCSSprint, "", seq2, "[a:1 b:<nil>]"},
+		{scsFlatten, fCSSdump, "", tft, "(spew_test.flattenTester) {\n" +
+			" Base.ID: (int) 1,\n" +
+			" Base.Name: (string) (len=4) \"base\",\n" +
+			" Meta.ID: (int) 2,\n" +
+			" Name: (string) (len=5) \"outer\"\n}\n"},
+		{scsFlatten, fCSSdump, "", struct{ Base }{Base{1, "a"}}, "(struct { spew_test.Base }) {\n" +
+			" ID: (int) 1,\n" +
+			" Name: (string) (len=1) \"a\"\n}\n"},
+		{scsFlatten, fCSSdump, "", flattenTester{}, "(spew_test.flattenTester) {\n" +
+			" ID: (int) 0,\n" +
+			" Base.Name: (string) \"\",\n" +
+			" Meta: (*spew_test.Meta)(<nil>),\n" +
+			" Name: (string) \"\"\n}\n"},
+		{scsIndices, fCSSdump, "", [][]int{{1}, nil}, "([][]int) (len=2 cap=2) {\n" +
+			" [0]: ([]int) (len=1 cap=1) {\n  [0]: (int) 1\n },\n" +
+			" [1]: ([]int) <nil>\n}\n"},
+		{scsIndices, fCSSdump, "", []*int{nil}, "([]*int) (len=1 cap=1) {\n" +
+			" [0]: (*int)(<nil>)\n}\n"},
+		{scsSpare, fCSSdump, "", spare, "([]int) (len=2 cap=4) {\n (int) 1,\n" +
+			" (int) 2\n <spare capacity [2:4]>\n (int) 3,\n (int) 0\n}\n"},
+		{scsSpare, fCSSdump, "", spare[:0], "([]int) (cap=4) {\n" +
+			" <spare capacity [0:4]>\n (int) 1,\n (int) 2,\n (int) 3,\n (int) 0\n}\n"},
+		{scsSpare, fCSSdump, "", spareBytes, "([]uint8) (len=1 cap=4) {\n" +
+			" 00000000  61                                                |a|\n" +
+			" <spare capacity [1:4]>\n" +
+			" 00000000  62 63 64                                          |bcd|\n}\n"},
+		{scsSpare, fCSFprint, "", spare, "[1 2]"},
+		{scsScrub, fCSSdump, "", "2e4f1c3a-9b7d-4e2f-8a6c-1d3b5f7e9a0c host-42",
+			"(string) (len=44) \"UUID host-N\"\n"},
+		{scsScrub, fCSSprint, "", time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+			"TIMESTAMP"},
+		{scsScrub, fCSSprintf, "%+v", &spare, "<*>(0xADDR)[1 2]"},
+		{scsExplain, fCSFprint, "", []int{1, 2, 3, 4},
+			"[1 2 <2 elements omitted, MaxElements=2>]"},
+		{scsExplain, fCSFprint, "", "abcdefgh", "abcd...(len=8, MaxStringLength=4)"},
+		{scsExplain, fCSFprint, "", dt, "{{<max, MaxDepth=1>} [<max, MaxDepth=1>] " +
+			"[<max, MaxDepth=1>] map[<max, MaxDepth=1>]}"},
+		{scsExplain, fCSSdump, "", [][]int{{1}}, "([][]int) (len=1 cap=1) {\n" +
+			" ([]int) (len=1 cap=1) {\n  <max depth reached, MaxDepth=1>\n }\n}\n"},
+		{scsOutput, fCSSdump, "", "abc", "(STRING) (LEN=3) \"ABC\"\n"},
+		{scsOutput, fCSSdump, "", nil, "(INTERFACE {}) <NIL>\n"},
+		{scsOutput, fCSSprint, "", "abc", "ABC"},
+		{scsOutput, fCSSprintf, "%#v", nil, "(INTERFACE {})<NIL>"},
+		{scsOutput, fCSSprintf, "%x", "abc", "616263"},
+		{scsTransform, fCSFprint, "", []wrapper{{1}, {"a"}}, "[1 a]"},
+		{scsTransform, fCSSdump, "", wrapper{wrapper{true}}, "(spew_test.wrapper) {\n" +
+			" v: (bool) true\n}\n"},
+		{scsTransform, fCSFprint, "", te, "[error: 10 error: 0]"},
+		{scsTransform, fCSSdump, "", te, "([]spew_test.customError) (len=2 cap=2) {\n" +
+			" (spew_test.customError) error: 10,\n" +
+			" (spew_test.customError) error: 0\n}\n"},
+		{scsRedact, fCSFprint, "", tcreds, "{admin <redacted> [a <redacted>]}"},
+		{scsRedact, fCSFdump, "", tcreds, "(spew_test.credentials) {\n" +
+			" User: (string) (len=5) \"admin\",\n" +
+			" Password: (string) <redacted>,\n" +
+			" Keys: ([]string) (len=2 cap=2) {\n" +
+			"  (string) (len=1) \"a\",\n  (string) <redacted>\n }\n}\n"},
+		{scsRedact, fCSSprint, "", tenv, "map[token:<redacted>]"},
+		{scsRedactMap, fCSSdump, "", tenvs, "(spew_test.envHolder) {\n" +
+			" Env: (map[string]string) (len=2) {\n" +
+			"  (string) (len=1) \"a\": (string) <redacted>,\n" +
+			"  (string) <redacted>: (string) <redacted>\n }\n}\n"},
+		{scsRedactAlign, fCSSdump, "", tenvs, "(spew_test.envHolder) {\n" +
+			" Env: (map[string]string) (len=2) {\n" +
+			"  (string) (len=1) \"a\": (string) <redacted>,\n" +
+			"  (string) <redacted>:  (string) <redacted>\n }\n}\n"},
+		{scsRedactMap, fCSFprint, "", tenvs, "{map[a:<redacted> <redacted>:<redacted>]}"},
+		{scsDefault, fCSFprint, "", &trt, "<*>{<redacted> {admin ***}}"},
+		{scsDefault, fCSFprintf, "%+v", &trt.P, "<*>(" +
+			fmt.Sprintf("%p", &trt.P) + "){user:admin password:***}"},
+		{scsNoPtrAddr, fCSSdump, "", &trt, "(*spew_test.redactorTester)({\n" +
+			"R: (string) (len=10) \"<redacted>\",\n" +
+			"P: (spew_test.predactor) {\n" +
+			"user: (string) (len=5) \"admin\",\n" +
+			"password: (string) (len=3) \"***\"\n}\n})\n"},
 	}
 }
 
@@ -318,3 +964,285 @@ func TestSpew(t *testing.T) {
 		}
 	}
 }
+
+// TestErrorfWrap ensures the Errorf functions wrap the arguments of %w verbs
+// while still formatting the remaining arguments with spew.
+func TestErrorfWrap(t *testing.T) {
+	base := customError(1)
+	v := &embed{"x"}
+	cs := spew.ConfigState{DisablePointerAddresses: true}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{spew.Errorf("failed on %v: %w", v, base),
+			"failed on <*>{x}: error: 1"},
+		{cs.Errorf("%*d %[4]w %[3]v", 3, 7, v, base),
+			"  7 error: 1 <*>{x}"},
+		{cs.Errorf("100%% %.*f: %w", 1, 2.25, base),
+			"100% 2.2: error: 1"},
+	}
+	for i, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("Errorf #%d\n got: %s want: %s", i, got, test.want)
+		}
+		if !errors.Is(test.err, base) {
+			t.Errorf("Errorf #%d does not wrap %v", i, base)
+		}
+	}
+}
+
+// TestInaccessibleValues ensures values whose Stringer interface can't be
+// invoked due to the unsafe package being unavailable are displayed according
+// to the InaccessibleValues option.
+func TestInaccessibleValues(t *testing.T) {
+	type inaccessible struct {
+		s stringer
+	}
+	v := inaccessible{"test"}
+
+	tests := []struct {
+		mode spew.InaccessibleMode
+		want string
+	}{
+		{spew.InaccessibleSilent, "{test}"},
+		{spew.InaccessiblePlaceholder, "{(unexported spew_test.stringer)}"},
+		{spew.InaccessibleVerbose, "{(unexported spew_test.stringer) test}"},
+	}
+	for i, test := range tests {
+		cs := spew.ConfigState{InaccessibleValues: test.mode}
+		want := test.want
+		if !spew.UnsafeDisabled {
+			want = "{stringer test}"
+		}
+		if got := cs.Sprint(v); got != want {
+			t.Errorf("InaccessibleValues #%d\n got: %s want: %s", i, got, want)
+		}
+	}
+}
+
+// TestDumpIf ensures DumpIf and DumpWhen only produce output when their
+// condition holds and invoke predicates exactly once.
+func TestDumpIf(t *testing.T) {
+	cs := spew.ConfigState{Indent: " "}
+	var calls int
+	v := pstringer("v")
+	pred := func(result bool) func() bool {
+		return func() bool { calls++; return result }
+	}
+
+	b, err := redirStdout(func() {
+		cs.DumpIf(false, &v)
+		cs.DumpWhen(pred(false), &v)
+		spew.DumpIf(false, &v)
+		spew.DumpWhen(pred(false), &v)
+	})
+	if err != nil || len(b) != 0 {
+		t.Errorf("disabled dumps wrote %q (err %v)", b, err)
+	}
+
+	b, err = redirStdout(func() {
+		cs.DumpIf(true, 1)
+		cs.DumpWhen(pred(true), 2)
+		spew.DumpIf(true, 3)
+		spew.DumpWhen(pred(true), 4)
+	})
+	want := "(int) 1\n(int) 2\n(int) 3\n(int) 4\n"
+	if err != nil || string(b) != want {
+		t.Errorf("enabled dumps wrote %q (err %v), want %q", b, err, want)
+	}
+	if calls != 4 {
+		t.Errorf("predicates invoked %d times, want 4", calls)
+	}
+}
+
+// TestDisable ensures the output functions produce no output while output is
+// disabled globally or by the Disabled option.
+func TestDisable(t *testing.T) {
+	var buf bytes.Buffer
+	output := func(cs *spew.ConfigState) string {
+		buf.Reset()
+		cs.Fdump(&buf, 1)
+		cs.Fprintf(&buf, "%v", 2)
+		spew.Fprint(&buf, 3)
+		return buf.String() + cs.Sprint(4) + cs.Sdump(5)
+	}
+
+	cs := &spew.ConfigState{}
+	defer spew.Enable()
+	spew.Disable()
+	if spew.Enabled() {
+		t.Errorf("Enabled: got true after Disable")
+	}
+	if got := output(cs); got != "" {
+		t.Errorf("output while disabled: %q", got)
+	}
+
+	spew.Enable()
+	want := "(int) 1\n234(int) 5\n"
+	if got := output(cs); got != want {
+		t.Errorf("output after Enable: got %q, want %q", got, want)
+	}
+
+	cs.Disabled = true
+	if got := output(cs); got != "3" {
+		t.Errorf("output with Disabled option: got %q, want %q", got, "3")
+	}
+}
+
+// TestDisableOutputs ensures every function which outputs values produces no
+// output while output is disabled globally or by the Disabled option.
+func TestDisableOutputs(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	outputs := []struct {
+		name string
+		fn   func(cs *spew.ConfigState, w io.Writer)
+	}{
+		{"Fdump", func(cs *spew.ConfigState, w io.Writer) { cs.Fdump(w, 1) }},
+		{"Fprintf", func(cs *spew.ConfigState, w io.Writer) {
+			cs.Fprintf(w, "%v", 1)
+		}},
+		{"KV", func(cs *spew.ConfigState, w io.Writer) {
+			io.WriteString(w, cs.KV("k", 1))
+		}},
+		{"DumpAsync", func(cs *spew.ConfigState, w io.Writer) {
+			io.Copy(w, cs.DumpAsync(1))
+		}},
+		{"FdumpJSONLines", func(cs *spew.ConfigState, w io.Writer) {
+			cs.FdumpJSONLines(w, 1)
+		}},
+		{"FdumpMethods", func(cs *spew.ConfigState, w io.Writer) {
+			cs.FdumpMethods(w, 1)
+		}},
+		{"DumpPanic", func(cs *spew.ConfigState, w io.Writer) {
+			cs.DumpPanic(w, 1)
+		}},
+		{"RecoverDump", func(cs *spew.ConfigState, w io.Writer) {
+			defer cs.RecoverDump(w)
+			panic(1)
+		}},
+		{"FdumpRepro", func(cs *spew.ConfigState, w io.Writer) {
+			cs.FdumpRepro(w, 1)
+		}},
+		{"CapturedDump.Fdump", func(cs *spew.ConfigState, w io.Writer) {
+			cs.Capture(1).Fdump(w)
+		}},
+		{"Baseline.FdumpChanges", func(cs *spew.ConfigState, w io.Writer) {
+			cs.NewBaseline(1).FdumpChanges(w, 2)
+		}},
+		{"Watch", func(cs *spew.ConfigState, w io.Writer) {
+			cs.Watch(canceled, time.Hour, func() interface{} { return 1 }, w)
+		}},
+		{"Attributes", func(cs *spew.ConfigState, w io.Writer) {
+			for _, attr := range cs.Attributes(1, spew.AttributeLimits{}) {
+				fmt.Fprint(w, attr)
+			}
+		}},
+		{"Printer.Dump", func(cs *spew.ConfigState, w io.Writer) {
+			spew.NewPrinterFromConfig(cs).To(w).Dump(1)
+		}},
+		{"DumpRing.Dump", func(cs *spew.ConfigState, w io.Writer) {
+			r := spew.NewDumpRing(1, 0)
+			r.Config = cs
+			r.Dump(1)
+			r.WriteTo(w)
+		}},
+	}
+
+	var buf bytes.Buffer
+	check := func(cs *spew.ConfigState, enabled bool) {
+		for _, output := range outputs {
+			buf.Reset()
+			output.fn(cs, &buf)
+			if got := buf.Len() != 0; got != enabled {
+				t.Errorf("%s: got output %q, want output %v", output.name,
+					buf.String(), enabled)
+			}
+		}
+	}
+
+	cs := &spew.ConfigState{}
+	check(cs, true)
+	defer spew.Enable()
+	spew.Disable()
+	check(cs, false)
+	spew.Enable()
+	cs.Disabled = true
+	check(cs, false)
+}
+
+// TestDumpNamed ensures each value dumped with DumpNamed is prefixed by its
+// label.
+func TestDumpNamed(t *testing.T) {
+	cs := spew.ConfigState{Indent: " "}
+	want := "request: (int) 1\nresponse: ([]string) (len=1 cap=1) {\n" +
+		" (string) (len=1) \"a\"\n}\n5: (bool) true\n(string) (len=4) \"last\"\n"
+	for _, parallel := range []bool{false, true} {
+		cs.ParallelDump = parallel
+		got := cs.SdumpNamed("request", 1, "response", []string{"a"}, 5, true,
+			"last")
+		if got != want {
+			t.Errorf("parallel %v: got %q, want %q", parallel, got, want)
+		}
+	}
+
+	cs.OutputFunc = strings.ToUpper
+	if got, want := cs.SdumpNamed("x", 1), "X: (INT) 1\n"; got != want {
+		t.Errorf("OutputFunc: got %q, want %q", got, want)
+	}
+
+	b, err := redirStdout(func() { spew.DumpNamed("n", 2) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "n: (int) 2\n"; string(b) != want {
+		t.Errorf("DumpNamed: got %q, want %q", b, want)
+	}
+}
+
+// TestScrubbers ensures the predefined scrubbers replace the text they are
+// intended to, and nothing else, and that scrubbers are applied in order
+// before the OutputFunc option.
+func TestScrubbers(t *testing.T) {
+	keyScrubber := spew.Scrubber{regexp.MustCompile(`key=(\w)\w*`), "key=$1***"}
+	tests := []struct {
+		name      string
+		scrubbers []spew.Scrubber
+		output    func(string) string
+		in        string
+		want      string
+	}{
+		{"address", []spew.Scrubber{spew.ScrubAddresses}, nil,
+			"p=0xc000012345 q=0XAB", "p=0xADDR q=0XAB"},
+		{"address within word", []spew.Scrubber{spew.ScrubAddresses}, nil,
+			"id0x12 0x1f", "id0x12 0xADDR"},
+		{"uuid", []spew.Scrubber{spew.ScrubUUIDs}, nil,
+			"2E4F1C3A-9B7D-4E2F-8A6C-1D3B5F7E9A0C", "UUID"},
+		{"uuid too short", []spew.Scrubber{spew.ScrubUUIDs}, nil,
+			"2e4f1c3a-9b7d-4e2f-8a6c-1d3b5f7e9a0", "2e4f1c3a-9b7d-4e2f-8a6c-1d3b5f7e9a0"},
+		{"rfc3339", []spew.Scrubber{spew.ScrubTimestamps}, nil,
+			"at 2024-05-06T07:08:09Z.", "at TIMESTAMP."},
+		{"rfc3339 nano offset", []spew.Scrubber{spew.ScrubTimestamps}, nil,
+			"2024-05-06T07:08:09.123456+02:00", "TIMESTAMP"},
+		{"time string", []spew.Scrubber{spew.ScrubTimestamps}, nil,
+			"2024-05-06 07:08:09.5 +0000 UTC m=+0.001234", "TIMESTAMP"},
+		{"date without time", []spew.Scrubber{spew.ScrubTimestamps}, nil,
+			"2024-05-06", "2024-05-06"},
+		{"submatch", []spew.Scrubber{keyScrubber}, nil,
+			"key=secret", "key=s***"},
+		{"in order", []spew.Scrubber{spew.ScrubAddresses,
+			{regexp.MustCompile(`0xADDR`), "ptr"}}, nil, "0x1", "ptr"},
+		{"before OutputFunc", []spew.Scrubber{keyScrubber}, strings.ToUpper,
+			"key=secret", "KEY=S***"},
+	}
+
+	for _, test := range tests {
+		cs := spew.ConfigState{Scrubbers: test.scrubbers,
+			OutputFunc: test.output}
+		if got := cs.Sprint(test.in); got != test.want {
+			t.Errorf("%s:\n got: %q\nwant: %q", test.name, got, test.want)
+		}
+	}
+}
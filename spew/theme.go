@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import "io"
+
+// Style is a set of ANSI SGR (Select Graphic Rendition) parameters separated
+// by semicolons, such as "1;34" for bold blue, which is applied to text by
+// wrapping it in the corresponding escape sequences.  The empty style leaves
+// text unstyled.
+type Style string
+
+// Theme specifies the styles used to display each kind of element of a dump.
+// It is used by the Dump family of functions when the Theme option of
+// ConfigState is set.  Elements with the empty style are not styled.
+type Theme struct {
+	// TypeName is the style of types, such as "(int)", including their
+	// parentheses.
+	TypeName Style
+
+	// FieldName is the style of the names of struct fields.
+	FieldName Style
+
+	// String is the style of the contents of strings.
+	String Style
+
+	// Number is the style of integers, floating point, and complex
+	// numbers.
+	Number Style
+
+	// Address is the style of pointer addresses.
+	Address Style
+
+	// Nil is the style of nil values.
+	Nil Style
+
+	// Circular is the style of the markers for circular references.
+	Circular Style
+}
+
+var (
+	// ThemeDark is a theme intended for terminals with dark backgrounds.
+	ThemeDark = &Theme{
+		TypeName:  "36",
+		FieldName: "1",
+		String:    "32",
+		Number:    "35",
+		Address:   "90",
+		Nil:       "31",
+		Circular:  "33",
+	}
+
+	// ThemeLight is a theme intended for terminals with light backgrounds.
+	ThemeLight = &Theme{
+		TypeName:  "34",
+		FieldName: "1",
+		String:    "32",
+		Number:    "35",
+		Address:   "2",
+		Nil:       "31",
+		Circular:  "1;31",
+	}
+
+	// ThemeMonochromeBold is a theme which does not use color, which makes it
+	// suitable for any terminal and for readers with color vision
+	// deficiencies.  Elements are distinguished by weight and underlining
+	// instead.
+	ThemeMonochromeBold = &Theme{
+		TypeName:  "1",
+		FieldName: "4",
+		Address:   "2",
+		Nil:       "1",
+		Circular:  "1;4",
+	}
+)
+
+// beginStyle outputs the escape sequence which applies the passed style, if
+// any, to Writer w.
+func beginStyle(w io.Writer, s Style) {
+	if s == "" {
+		return
+	}
+	w.Write(escapeBytes)
+	io.WriteString(w, string(s))
+	w.Write(sgrEndBytes)
+}
+
+// endStyle outputs the escape sequence which resets the passed style, if any,
+// to Writer w.
+func endStyle(w io.Writer, s Style) {
+	if s == "" {
+		return
+	}
+	w.Write(resetStyleBytes)
+}
+
+// writeStyled outputs b in the passed style to Writer w.
+func writeStyled(w io.Writer, s Style, b []byte) {
+	beginStyle(w, s)
+	w.Write(b)
+	endStyle(w, s)
+}
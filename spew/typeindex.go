@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// typeIndexEntry is the number of values of a type which were dumped along
+// with the total number of bytes they were rendered as.
+type typeIndexEntry struct {
+	typ   reflect.Type
+	count int
+	bytes int64
+}
+
+// typeIndex tracks the types of the values dumped by a single call in order to
+// append the index requested with the ShowTypeIndex option.  It is safe for
+// concurrent use so arguments are able to be dumped in parallel.
+type typeIndex struct {
+	mtx     sync.Mutex
+	entries map[reflect.Type]*typeIndexEntry
+}
+
+// record records a value of the passed type which was rendered as n bytes.
+// The bytes of values nested within another value of the same type are
+// already included in those of the outer value, so outermost is only set for
+// values which are not.
+func (ti *typeIndex) record(t reflect.Type, n int64, outermost bool) {
+	ti.mtx.Lock()
+	defer ti.mtx.Unlock()
+	if ti.entries == nil {
+		ti.entries = make(map[reflect.Type]*typeIndexEntry)
+	}
+	entry, ok := ti.entries[t]
+	if !ok {
+		entry = &typeIndexEntry{typ: t}
+		ti.entries[t] = entry
+	}
+	entry.count++
+	if outermost {
+		entry.bytes += n
+	}
+}
+
+// write outputs the index to Writer w, one type per line, ordered by the
+// number of bytes their values were rendered as from largest to smallest.
+func (ti *typeIndex) write(w io.Writer, cs *ConfigState) {
+	ti.mtx.Lock()
+	defer ti.mtx.Unlock()
+	entries := make([]*typeIndexEntry, 0, len(ti.entries))
+	for _, entry := range ti.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].bytes != entries[j].bytes {
+			return entries[i].bytes > entries[j].bytes
+		}
+		return entries[i].typ.String() < entries[j].typ.String()
+	})
+
+	w.Write(typeIndexBytes)
+	for _, entry := range entries {
+		w.Write([]byte(cs.Indent))
+		w.Write(openParenBytes)
+		w.Write([]byte(typeString(cs, entry.typ)))
+		w.Write(closeParenBytes)
+		w.Write(spaceBytes)
+		w.Write(countEqualsBytes)
+		printCount(w, cs, entry.count)
+		w.Write(spaceBytes)
+		w.Write(bytesEqualsBytes)
+		w.Write([]byte(groupDigits(strconv.FormatInt(entry.bytes, 10),
+			cs.DigitSeparator)))
+		w.Write(newlineBytes)
+	}
+}
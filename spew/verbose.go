@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// Level is a verbosity level for V.  Higher levels are more verbose.
+type Level int32
+
+// verbosity is the global verbosity level.  It is accessed atomically.
+var verbosity int32
+
+// SetVerbosity sets the global verbosity level which V compares against.  It
+// is safe for concurrent use.
+func SetVerbosity(level Level) {
+	atomic.StoreInt32(&verbosity, int32(level))
+}
+
+// Verbosity returns the global verbosity level set by SetVerbosity.
+func Verbosity() Level {
+	return Level(atomic.LoadInt32(&verbosity))
+}
+
+// Verbose is returned by V and provides the output functions which only
+// produce output when the verbosity level passed to V is enabled.  No
+// formatting work is performed when it is not.
+type Verbose struct {
+	enabled bool
+	p       *Printer
+}
+
+// V reports whether or not the passed verbosity level is enabled, which is
+// the case when it is less than or equal to the level set by SetVerbosity.
+// This allows graded diagnostic dumps to be left in the code and the amount
+// of detail to be turned up per environment without code changes:
+//
+//	spew.V(2).Dump(myVar)
+//
+// The output is written to standard out using the global Config.
+func V(level Level) Verbose {
+	return Verbose{enabled: level <= Verbosity()}
+}
+
+// Enabled returns whether or not the verbosity level is enabled and output is
+// not disabled, either by Disable, the Disabled option of the configuration
+// used, or the spew_noop build tag.
+func (v Verbose) Enabled() bool {
+	switch {
+	case NoopBuild || !v.enabled:
+		return false
+	case v.p != nil:
+		return !outputDisabled(&v.p.cs)
+	default:
+		return !outputDisabled(globalConfig())
+	}
+}
+
+// Dump displays the passed parameters in the same style as Dump when the
+// verbosity level is enabled.
+func (v Verbose) Dump(a ...interface{}) {
+	switch {
+	case !v.enabled:
+	case v.p != nil:
+		v.p.Dump(a...)
+	default:
+		fdump(globalConfig(), os.Stdout, a...)
+	}
+}
+
+// Printf formats the passed arguments according to the format specifier in
+// the same style as Printf when the verbosity level is enabled.
+func (v Verbose) Printf(format string, a ...interface{}) {
+	switch {
+	case !v.enabled:
+	case v.p != nil:
+		v.p.Printf(format, a...)
+	default:
+		globalConfig().Printf(format, a...)
+	}
+}
+
+// Println formats the passed arguments in the same style as Println when the
+// verbosity level is enabled.
+func (v Verbose) Println(a ...interface{}) {
+	switch {
+	case !v.enabled:
+	case v.p != nil:
+		v.p.Println(a...)
+	default:
+		globalConfig().Println(a...)
+	}
+}
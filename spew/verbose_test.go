@@ -0,0 +1,68 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestV ensures output is only produced when the verbosity level is enabled,
+// using the printer's own level when it has one.
+func TestV(t *testing.T) {
+	defer spew.SetVerbosity(spew.Verbosity())
+	spew.SetVerbosity(1)
+
+	if !spew.V(1).Enabled() || spew.V(2).Enabled() {
+		t.Errorf("V: unexpected enabled levels for verbosity 1")
+	}
+	b, err := redirStdout(func() {
+		spew.V(2).Dump(1)
+		spew.V(2).Printf("%v", 1)
+		spew.V(1).Dump(2)
+		spew.V(0).Println(3)
+	})
+	if want := "(int) 2\n3\n"; err != nil || string(b) != want {
+		t.Errorf("V: got %q (err %v), want %q", b, err, want)
+	}
+
+	var buf bytes.Buffer
+	p := spew.NewPrinter().To(&buf)
+	p.V(1).Dump(1)
+	p.V(2).Dump(2)
+	p.Verbosity(3).V(3).Printf("%v\n", 3)
+	p.Verbosity(0).V(1).Println(4)
+	if want := "(int) 1\n3\n"; buf.String() != want {
+		t.Errorf("Printer.V: got %q, want %q", buf.String(), want)
+	}
+
+	// Levels are not enabled while output is disabled.
+	p = p.Configure(func(cs *spew.ConfigState) { cs.Disabled = true })
+	if p.V(1).Enabled() {
+		t.Errorf("Printer.V: enabled with the Disabled option")
+	}
+	defer spew.Enable()
+	spew.Disable()
+	if spew.V(1).Enabled() {
+		t.Errorf("V: enabled while output is disabled")
+	}
+}
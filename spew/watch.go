@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+)
+
+// Watch calls fn every interval until ctx is done and writes the changes to
+// the value it returns since the previous call to io.Writer w in the same
+// format as Baseline.DumpChanges.  Nothing is written while the value is
+// unchanged, so slow state machines may be observed in long tests without
+// flooding the output.  The value returned by the first call is written in
+// full with Fdump.  Watch blocks until ctx is done, so it is typically run in
+// its own goroutine:
+//
+//	go spew.Watch(ctx, 100*time.Millisecond, func() interface{} {
+//		return machine.State()
+//	}, os.Stderr)
+//
+// Each dump is written to w with a single call to Write.  The value is
+// formatted by the goroutine running Watch, so fn must synchronize access to
+// it as needed, for example by returning a copy.
+//
+// Whether or not output is disabled is checked on every interval.  While it
+// is, fn is not called and nothing is written, and once output is enabled
+// again the changes since the last value written are written.
+func (c *ConfigState) Watch(ctx context.Context, interval time.Duration, fn func() interface{}, w io.Writer) {
+	if NoopBuild {
+		<-ctx.Done()
+		return
+	}
+
+	var buf bytes.Buffer
+	var baseline *Baseline
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if !outputDisabled(c) {
+			value := fn()
+			buf.Reset()
+			if baseline == nil {
+				c.Fdump(&buf, value)
+			} else if changes := baseline.Changes(value); len(changes) != 0 {
+				baseline.writeChanges(&buf, value, changes)
+			}
+			if buf.Len() != 0 {
+				w.Write(buf.Bytes())
+				baseline = c.NewBaseline(value)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Watch calls fn every interval until ctx is done and writes the changes to
+// the value it returns to io.Writer w using the global Config.  See
+// ConfigState.Watch for details.
+func Watch(ctx context.Context, interval time.Duration, fn func() interface{}, w io.Writer) {
+	globalConfig().Watch(ctx, interval, fn, w)
+}
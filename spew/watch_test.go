@@ -0,0 +1,156 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// writerFunc adapts a function to the io.Writer interface.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// TestWatch ensures the watched value is written in full initially and then
+// only when it changes.
+func TestWatch(t *testing.T) {
+	var mtx sync.Mutex
+	calls := 0
+	fn := func() interface{} {
+		mtx.Lock()
+		defer mtx.Unlock()
+		calls++
+		return map[string]int{"state": calls / 3}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var dumps []string
+	w := writerFunc(func(p []byte) (int, error) {
+		dumps = append(dumps, string(p))
+		if len(dumps) == 3 {
+			cancel()
+		}
+		return len(p), nil
+	})
+	cs := spew.ConfigState{Indent: " "}
+	done := make(chan struct{})
+	go func() {
+		cs.Watch(ctx, time.Millisecond, fn, w)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Watch did not return after the context was canceled")
+	}
+
+	want := []string{
+		"(map[string]int) (len=1) {\n (string) (len=5) \"state\": (int) 0\n}\n",
+		"(map[string]int)\n [state]: (int) 0 → (int) 1\n",
+		"(map[string]int)\n [state]: (int) 1 → (int) 2\n",
+	}
+	if len(dumps) != len(want) {
+		t.Fatalf("got %d dumps %q, want %d", len(dumps), dumps, len(want))
+	}
+	for i := range want {
+		if dumps[i] != want[i] {
+			t.Errorf("#%d: got %q, want %q", i, dumps[i], want[i])
+		}
+	}
+}
+
+// TestWatchDisable ensures Watch keeps running while output is disabled,
+// writes nothing until output is enabled again, and stops writing once output
+// is disabled.
+func TestWatchDisable(t *testing.T) {
+	var mtx sync.Mutex
+	calls, writes := 0, 0
+	fn := func() interface{} {
+		mtx.Lock()
+		defer mtx.Unlock()
+		calls++
+		return calls
+	}
+	written := make(chan struct{}, 1)
+	w := writerFunc(func(p []byte) (int, error) {
+		mtx.Lock()
+		writes++
+		mtx.Unlock()
+		select {
+		case written <- struct{}{}:
+		default:
+		}
+		return len(p), nil
+	})
+	counts := func() (int, int) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return calls, writes
+	}
+
+	defer spew.Enable()
+	spew.Disable()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		spew.Watch(ctx, time.Millisecond, fn, w)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("Watch returned while output was disabled")
+	default:
+	}
+	if c, n := counts(); c != 0 || n != 0 {
+		t.Fatalf("while disabled: got %d calls and %d writes, want none", c,
+			n)
+	}
+
+	spew.Enable()
+	select {
+	case <-written:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Watch did not write after output was enabled")
+	}
+
+	spew.Disable()
+	time.Sleep(20 * time.Millisecond)
+	_, before := counts()
+	time.Sleep(20 * time.Millisecond)
+	if _, n := counts(); n != before {
+		t.Errorf("after Disable: got %d writes, want %d", n, before)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Watch did not return after the context was canceled")
+	}
+}
@@ -0,0 +1,63 @@
+//go:build go1.24 && !spew_noop
+// +build go1.24,!spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spew_test
+
+import (
+	"runtime"
+	"testing"
+	"weak"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// weakTarget is a type used as the target of weak pointers.  It contains a
+// pointer so it is not allocated by the tiny allocator and is reliably
+// collected.
+type weakTarget struct {
+	Name string
+}
+
+// TestWeakPointer ensures weak.Pointer values are displayed by whether or not
+// their target is still alive.
+func TestWeakPointer(t *testing.T) {
+	cfg := spew.ConfigState{DisablePointerAddresses: true}
+	target := &weakTarget{Name: "live"}
+	live := weak.Make(target)
+	collected := weak.Make(&weakTarget{Name: "collected"})
+	runtime.GC()
+
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{cfg.Sdump(live), "(weak.Pointer[github.com/davecgh/go-spew/spew_test.weakTarget]) " +
+			"(weak, live) (*spew_test.weakTarget)({\nName: (string) (len=4) \"live\"\n})\n"},
+		{cfg.Sdump(collected), "(weak.Pointer[github.com/davecgh/go-spew/spew_test.weakTarget]) " +
+			"(weak, collected)\n"},
+		{cfg.Sprint(struct{ P weak.Pointer[weakTarget] }{collected}), "{(weak, collected)}"},
+	}
+	for i, test := range tests {
+		if test.got != test.want {
+			t.Errorf("Weak pointer #%d mismatch:\n got: %q\nwant: %q", i,
+				test.got, test.want)
+		}
+	}
+	runtime.KeepAlive(target)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spewhttp
+
+import (
+	"encoding/json"
+	"expvar"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// expvarValue implements expvar.Var for arbitrary values.
+type expvarValue struct {
+	v interface{}
+}
+
+// String returns the value formatted with the %v verb, with the
+// deterministic settings of the test profile, as a JSON string.  It
+// implements the expvar.Var interface.
+func (e expvarValue) String() string {
+	cs, _ := spew.ProfileConfig(spew.ProfileTest)
+	b, err := json.Marshal(cs.Sprintf("%v", e.v))
+	if err != nil {
+		return `""`
+	}
+	return string(b)
+}
+
+// Expvar publishes the passed value as an expvar variable under the passed
+// name, so existing scrapers of /debug/vars pick up a compact dump of it.
+// The value should be a pointer so the live value is formatted on each
+// scrape.  The output is deterministic since map keys are sorted and pointer
+// addresses are hidden.  Like expvar.Publish, it panics if the name is
+// already in use.
+func Expvar(name string, v interface{}) expvar.Var {
+	ev := expvarValue{v: v}
+	expvar.Publish(name, ev)
+	return ev
+}
@@ -0,0 +1,55 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spewhttp_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"testing"
+
+	"github.com/davecgh/go-spew/spewhttp"
+)
+
+// expvarRuns counts the runs of TestExpvar so each publishes a unique name,
+// since expvar does not allow names to be reused.
+var expvarRuns int
+
+// TestExpvar ensures published values are formatted as deterministic JSON
+// strings of the live value.
+func TestExpvar(t *testing.T) {
+	type state struct {
+		Tags map[string]int
+	}
+	s := &state{Tags: map[string]int{"b": 2}}
+	expvarRuns++
+	name := fmt.Sprintf("%s_%d", t.Name(), expvarRuns)
+	spewhttp.Expvar(name, s)
+	s.Tags["a"] = 1
+
+	var got string
+	v := expvar.Get(name)
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("Unmarshal %q: %v", v.String(), err)
+	}
+	if want := "<*>{map[a:1 b:2]}"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
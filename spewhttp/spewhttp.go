@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+/*
+Package spewhttp serves live dumps of registered values over HTTP, in the
+style of net/http/pprof and expvar, for inspecting the in-memory state of
+long-running services:
+
+	spewhttp.Register("cache", cache)
+	spewhttp.RegisterFunc("sessions", func() interface{} {
+		return sessions.Snapshot()
+	})
+
+Importing the package installs its handler on http.DefaultServeMux under
+/debug/spew/, so the values above are served at /debug/spew/cache and
+/debug/spew/sessions.  The index at /debug/spew/ lists the registered names.
+Programs which do not use http.DefaultServeMux may install Handler on their
+own mux instead.
+
+The following query parameters override the configuration for a request:
+
+	depth=N   display at most N levels of nested values
+	format=F  the output format, which is one of dump (the default), print
+	          for the %v verb, or jsonl for JSON Lines
+
+Expvar publishes a value as an expvar variable instead, so existing scrapers
+of /debug/vars pick up a compact dump of it.
+
+Dumps of live values may reveal sensitive information, so the handler should
+only be reachable by trusted clients.
+*/
+package spewhttp
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Prefix is the path the handler is installed under on http.DefaultServeMux.
+const Prefix = "/debug/spew/"
+
+var (
+	// Config is the configuration used to format values.  It is the spew
+	// global configuration by default.
+	Config = &spew.Config
+
+	mu        sync.RWMutex
+	providers = make(map[string]func() interface{})
+)
+
+func init() {
+	http.Handle(Prefix, Handler())
+}
+
+// Register registers the passed value under the passed name.  Values should
+// be pointers so the live value is dumped on each request rather than a copy
+// of it taken at registration.  Registering a name again replaces the value.
+func Register(name string, v interface{}) {
+	RegisterFunc(name, func() interface{} { return v })
+}
+
+// RegisterFunc registers the passed function under the passed name.  The
+// value it returns is dumped on each request.  It must be safe to call from
+// the goroutines serving requests.
+func RegisterFunc(name string, fn func() interface{}) {
+	mu.Lock()
+	providers[name] = fn
+	mu.Unlock()
+}
+
+// Unregister removes the value registered under the passed name, if any.
+func Unregister(name string) {
+	mu.Lock()
+	delete(providers, name)
+	mu.Unlock()
+}
+
+// handler serves the index and dumps of registered values.
+type handler struct{}
+
+// Handler returns the http.Handler which serves the index of registered names
+// and the dumps of their values.  The name is taken from the final portion of
+// the request path after Prefix, so the handler may also be installed with
+// http.StripPrefix.
+func Handler() http.Handler {
+	return handler{}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, Prefix)
+	name = strings.TrimPrefix(name, "/")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if name == "" {
+		serveIndex(w)
+		return
+	}
+
+	mu.RLock()
+	fn, ok := providers[name]
+	mu.RUnlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown value %q", name), http.StatusNotFound)
+		return
+	}
+
+	cs := *Config
+	query := r.URL.Query()
+	if depth := query.Get("depth"); depth != "" {
+		n, err := strconv.Atoi(depth)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("invalid depth %q", depth),
+				http.StatusBadRequest)
+			return
+		}
+		cs.MaxDepth = n
+	}
+
+	switch format := query.Get("format"); format {
+	case "", "dump":
+		cs.Fdump(w, fn())
+	case "print":
+		cs.Fprintf(w, "%v\n", fn())
+	case "jsonl":
+		w.Header().Set("Content-Type", "application/jsonl")
+		cs.FdumpJSONLines(w, fn())
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q", format),
+			http.StatusBadRequest)
+	}
+}
+
+// serveIndex writes the sorted names of the registered values.
+func serveIndex(w http.ResponseWriter) {
+	mu.RLock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spewhttp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/davecgh/go-spew/spewhttp"
+)
+
+// TestHandler ensures registered values are served with the requested
+// overrides.
+func TestHandler(t *testing.T) {
+	defer func(cs *spew.ConfigState) { spewhttp.Config = cs }(spewhttp.Config)
+	spewhttp.Config = &spew.ConfigState{Indent: " ", DisableCapacities: true,
+		DisablePointerAddresses: true}
+
+	type state struct {
+		Items []int
+	}
+	s := &state{Items: []int{1}}
+	spewhttp.Register("state", s)
+	spewhttp.RegisterFunc("count", func() interface{} { return len(s.Items) })
+	defer spewhttp.Unregister("state")
+	defer spewhttp.Unregister("count")
+
+	// Values are dumped live rather than when they were registered.
+	s.Items = append(s.Items, 2)
+
+	tests := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/debug/spew/", http.StatusOK, "count\nstate\n"},
+		{"/debug/spew/count", http.StatusOK, "(int) 2\n"},
+		{"/debug/spew/state?depth=1", http.StatusOK,
+			"(*spewhttp_test.state)({\n Items: ([]int) (len=2) {\n  <max depth reached>\n }\n})\n"},
+		{"/debug/spew/state?format=print", http.StatusOK,
+			"<*>{[1 2]}\n"},
+		{"/debug/spew/count?format=jsonl", http.StatusOK,
+			`{"path":"","type":"int","kind":"int","value":2,"depth":0}` + "\n"},
+		{"/debug/spew/missing", http.StatusNotFound, "unknown value \"missing\"\n"},
+		{"/debug/spew/count?depth=x", http.StatusBadRequest, "invalid depth \"x\"\n"},
+		{"/debug/spew/count?format=x", http.StatusBadRequest, "unknown format \"x\"\n"},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+		body, _ := ioutil.ReadAll(rec.Body)
+		if rec.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.path, rec.Code, test.status)
+		}
+		if got := string(body); got != test.want {
+			t.Errorf("%s:\n got: %q\nwant: %q", test.path, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+/*
+Package spewk provides verbosity gated spew helpers in the style of klog and
+glog, which are heavily used in Kubernetes controllers:
+
+	spewk.V(4).Dump(obj)
+
+The deep formatting work is skipped entirely when the requested verbosity
+level is not enabled, so the helpers are cheap to leave in hot paths.
+
+By default the verbosity is controlled by SetVerbosity.  Programs which
+already use klog may instead defer to its flags:
+
+	spewk.EnabledFunc = func(level spewk.Level) bool {
+		return klog.V(klog.Level(level)).Enabled()
+	}
+*/
+package spewk
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Level is a verbosity level.  Higher levels are more verbose.  It is the
+// same type as spew.Level.
+type Level = spew.Level
+
+// Verbose is returned by V and provides spew helpers which only produce
+// output when the verbosity level passed to V is enabled.  It is the same type
+// as spew.Verbose.
+type Verbose = spew.Verbose
+
+var (
+	// EnabledFunc, when set, is consulted to determine whether or not a
+	// verbosity level is enabled instead of the level set by SetVerbosity.
+	// It is intended to be set once during initialization.
+	EnabledFunc func(level Level) bool
+
+	// Output is the io.Writer the output is written to.  It is standard
+	// error by default.  It is intended to be set once during
+	// initialization.
+	Output io.Writer = os.Stderr
+
+	// Config is the configuration used to format values.  It is the spew
+	// global configuration by default, in which case the copy locked by
+	// spew.LockConfig is used while it is locked.
+	Config = &spew.Config
+)
+
+// SetVerbosity sets the verbosity level which V compares against.  It is the
+// same level as the one set by spew.SetVerbosity, and it is safe for
+// concurrent use.
+func SetVerbosity(level Level) {
+	spew.SetVerbosity(level)
+}
+
+// Verbosity returns the verbosity level set by SetVerbosity.
+func Verbosity() Level {
+	return spew.Verbosity()
+}
+
+// V reports whether or not the passed verbosity level is enabled.  The result
+// provides the Dump family of methods which write to Output using Config when
+// it is, and do nothing otherwise, so no formatting work is performed:
+//
+//	spewk.V(4).Dump(obj)
+//
+// The level is enabled when it is less than or equal to the level set by
+// SetVerbosity, or when EnabledFunc returns true if it is set.
+func V(level Level) Verbose {
+	enabled := level <= Verbosity()
+	if EnabledFunc != nil {
+		enabled = EnabledFunc(level)
+	}
+	if !enabled {
+		return Verbose{}
+	}
+	p := spew.NewPrinterFromConfig(Config).To(Output)
+	return p.Verbosity(level).V(level)
+}
+
+// lazyDump implements fmt.Stringer by deferring the dump of its values until
+// String is called.
+type lazyDump []interface{}
+
+// String returns the passed values formatted in the same style as spew.Sdump.
+func (l lazyDump) String() string {
+	return spew.NewPrinterFromConfig(Config).Sdump(l...)
+}
+
+// Lazy returns a fmt.Stringer which formats the passed values in the same
+// style as spew.Sdump only when its String method is called.  This allows
+// dumps to be passed to loggers, such as klog, which skip formatting their
+// arguments when the verbosity level is disabled:
+//
+//	klog.V(4).InfoS("reconciling", "obj", spewk.Lazy(obj))
+func Lazy(a ...interface{}) fmt.Stringer {
+	return lazyDump(a)
+}
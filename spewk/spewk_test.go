@@ -0,0 +1,112 @@
+//go:build !spew_noop
+// +build !spew_noop
+
+/*
+ * Copyright (c) 2013-2016 Dave Collins <dave@davec.name>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package spewk_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/davecgh/go-spew/spewk"
+)
+
+// counter counts the number of times it is formatted.
+type counter struct {
+	n    *int
+	name string
+}
+
+func (c counter) String() string {
+	*c.n++
+	return c.name
+}
+
+// TestV ensures output is only produced, and formatting only performed, when
+// the verbosity level is enabled.
+func TestV(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(w io.Writer) { spewk.Output = w }(spewk.Output)
+	spewk.Output = &buf
+	defer spewk.SetVerbosity(spewk.Verbosity())
+	spewk.SetVerbosity(2)
+
+	var n int
+	c := counter{&n, "counter"}
+	spewk.V(3).Dump(c)
+	spewk.V(3).Printf("%v", c)
+	spewk.V(3).Println(c)
+	if n != 0 || buf.Len() != 0 {
+		t.Fatalf("disabled level formatted %d times: %q", n, buf.String())
+	}
+
+	spewk.V(2).Dump(c)
+	spewk.V(1).Printf("%v\n", c)
+	if n != 2 {
+		t.Errorf("enabled level formatted %d times, want 2", n)
+	}
+	want := "(spewk_test.counter) counter\ncounter\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output:\n got: %q\nwant: %q", got, want)
+	}
+
+	spewk.EnabledFunc = func(level spewk.Level) bool { return level == 7 }
+	defer func() { spewk.EnabledFunc = nil }()
+	if spewk.V(1).Enabled() || !spewk.V(7).Enabled() {
+		t.Errorf("EnabledFunc not honored")
+	}
+}
+
+// TestLazy ensures Lazy defers formatting until String is called.
+func TestLazy(t *testing.T) {
+	var n int
+	s := spewk.Lazy(counter{&n, "counter"})
+	if n != 0 {
+		t.Fatalf("Lazy formatted eagerly")
+	}
+	if got, want := s.String(), "(spewk_test.counter) counter\n"; got != want {
+		t.Errorf("Lazy:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+// TestLockedConfig ensures the copy of the spew global configuration locked by
+// spew.LockConfig is used rather than later modifications to it.
+func TestLockedConfig(t *testing.T) {
+	orig := spew.Config
+	defer func() {
+		spew.UnlockConfig()
+		spew.Config = orig
+	}()
+	spew.LockConfigFunc(func(err error) {})
+	spew.Config.Indent = "\t"
+
+	want := "([]int) (len=1 cap=1) {\n (int) 1\n}\n"
+	if got := spewk.Lazy([]int{1}).String(); got != want {
+		t.Errorf("Lazy:\n got: %q\nwant: %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	defer func(w io.Writer) { spewk.Output = w }(spewk.Output)
+	spewk.Output = &buf
+	spewk.V(0).Dump([]int{1})
+	if got := buf.String(); got != want {
+		t.Errorf("V(0).Dump:\n got: %q\nwant: %q", got, want)
+	}
+}